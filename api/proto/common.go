@@ -3,6 +3,9 @@ package proto
 import (
 	"bytes"
 	"errors"
+	"fmt"
+
+	"github.com/golang/snappy"
 )
 
 /*
@@ -12,6 +15,8 @@ The message structure of any message in Harmony network
 1 byte            - message category
                     0x00: Consensus
                     0x01: Node...
+                    the 0x80 bit is the Compressed flag; if set, the rest of
+                    the content is snappy-compressed
 1 byte            - message type
                     - for Consensus category
                       0x00: consensus
@@ -42,6 +47,10 @@ const (
 	MessageCategoryBytes = 1
 	// MessageTypeBytes is the number of bytes message type takes
 	MessageTypeBytes = 1
+	// Compressed is the flag of the message category byte set on messages
+	// whose content after the category byte is snappy-compressed. Nodes that
+	// do not support compression see an unknown category and drop them.
+	Compressed = 0x80
 )
 
 // GetMessageCategory gets the message category from the p2p message content
@@ -112,3 +121,47 @@ func ConstructStakingMessage(payload []byte) []byte {
 	byteBuffer.Write(payload)
 	return byteBuffer.Bytes()
 }
+
+// IsCompressed returns whether the p2p message content is snappy-compressed.
+func IsCompressed(message []byte) bool {
+	return len(message) >= MessageCategoryBytes && message[0]&Compressed != 0
+}
+
+// CompressMessage snappy-compresses the p2p message content after the
+// category byte and sets the Compressed flag. Messages compression does not
+// make smaller are returned as is.
+func CompressMessage(message []byte) []byte {
+	if len(message) < MessageCategoryBytes || IsCompressed(message) {
+		return message
+	}
+	compressed := make([]byte, MessageCategoryBytes+snappy.MaxEncodedLen(len(message)-MessageCategoryBytes))
+	compressed[0] = message[0] | Compressed
+	encoded := snappy.Encode(compressed[MessageCategoryBytes:], message[MessageCategoryBytes:])
+	if MessageCategoryBytes+len(encoded) >= len(message) {
+		return message
+	}
+	return compressed[:MessageCategoryBytes+len(encoded)]
+}
+
+// DecompressMessage returns the uncompressed p2p message content of a
+// compressed one, or the content as is if it is not compressed. Messages
+// larger than maxSize bytes once decompressed are rejected without
+// decompressing them.
+func DecompressMessage(message []byte, maxSize int) ([]byte, error) {
+	if !IsCompressed(message) {
+		return message, nil
+	}
+	size, err := snappy.DecodedLen(message[MessageCategoryBytes:])
+	if err != nil {
+		return nil, err
+	}
+	if MessageCategoryBytes+size > maxSize {
+		return nil, fmt.Errorf("compressed message too large: %d > %d bytes", MessageCategoryBytes+size, maxSize)
+	}
+	decompressed := make([]byte, MessageCategoryBytes+size)
+	decompressed[0] = message[0] &^ Compressed
+	if _, err := snappy.Decode(decompressed[MessageCategoryBytes:], message[MessageCategoryBytes:]); err != nil {
+		return nil, err
+	}
+	return decompressed, nil
+}
@@ -0,0 +1,38 @@
+package proto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCompressMessage(t *testing.T) {
+	message := append([]byte{byte(Node), 1}, bytes.Repeat([]byte{7}, 1000)...)
+	compressed := CompressMessage(message)
+	if !IsCompressed(compressed) || len(compressed) >= len(message) {
+		t.Fatalf("message not compressed: %d bytes", len(compressed))
+	}
+	if category, _ := GetMessageCategory(compressed); category == Node {
+		t.Error("compressed message category readable without decompressing")
+	}
+	if _, err := DecompressMessage(compressed, len(message)-1); err == nil {
+		t.Error("message larger than the maximum size decompressed")
+	}
+	decompressed, err := DecompressMessage(compressed, len(message))
+	if err != nil {
+		t.Fatalf("DecompressMessage: %v", err)
+	}
+	if !bytes.Equal(decompressed, message) {
+		t.Errorf("decompressed message differs: %v", decompressed)
+	}
+	if _, err := DecompressMessage([]byte{byte(Node) | Compressed, 0xff}, 1<<20); err == nil {
+		t.Error("corrupt message decompressed")
+	}
+
+	small := []byte{byte(Consensus), 1, 2, 3}
+	if !bytes.Equal(CompressMessage(small), small) {
+		t.Error("message compressed although it does not get smaller")
+	}
+	if uncompressed, err := DecompressMessage(small, 0); err != nil || !bytes.Equal(uncompressed, small) {
+		t.Errorf("uncompressed message changed: %v, %v", uncompressed, err)
+	}
+}
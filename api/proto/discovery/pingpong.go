@@ -20,6 +20,7 @@ import (
 	"github.com/harmony-one/harmony/api/proto/node"
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/p2p"
+	ma "github.com/multiformats/go-multiaddr"
 )
 
 // PingMessageType defines the data structure of the Ping message
@@ -54,6 +55,7 @@ func NewPingMessage(peer p2p.Peer, isClient bool) *PingMessageType {
 	ping.Node.IP = peer.IP
 	ping.Node.Port = peer.Port
 	ping.Node.PeerID = peer.PeerID
+	ping.Node.Addrs = addrStrings(peer.Addrs)
 	if !isClient {
 		ping.Node.PubKey = peer.ConsensusPubKey.Serialize()
 		ping.Node.Role = node.ValidatorRole
@@ -80,6 +82,7 @@ func NewPongMessage(peers []p2p.Peer, pubKeys []*bls.PublicKey, leaderKey *bls.P
 		n.IP = p.IP
 		n.Port = p.Port
 		n.PeerID = p.PeerID
+		n.Addrs = addrStrings(p.Addrs)
 		n.PubKey = p.ConsensusPubKey.Serialize()
 		if err != nil {
 			fmt.Printf("Error Marshal PubKey: %v", err)
@@ -99,6 +102,15 @@ func NewPongMessage(peers []p2p.Peer, pubKeys []*bls.PublicKey, leaderKey *bls.P
 	return pong
 }
 
+// addrStrings returns the string forms of multiaddrs.
+func addrStrings(addrs []ma.Multiaddr) []string {
+	var strs []string
+	for _, addr := range addrs {
+		strs = append(strs, addr.String())
+	}
+	return strs
+}
+
 // GetPingMessage deserializes the Ping Message from a list of byte
 func GetPingMessage(payload []byte) (*PingMessageType, error) {
 	ping := new(PingMessageType)
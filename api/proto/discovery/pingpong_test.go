@@ -11,6 +11,7 @@ import (
 	"github.com/harmony-one/harmony/api/proto/node"
 	"github.com/harmony-one/harmony/crypto/pki"
 	"github.com/harmony-one/harmony/p2p"
+	ma "github.com/multiformats/go-multiaddr"
 )
 
 var (
@@ -101,3 +102,31 @@ func TestSerialize(test *testing.T) {
 		test.Error("Serialize/Deserialze Pong Message Failed")
 	}
 }
+
+func TestPingAddrs(test *testing.T) {
+	peer := p1
+	for _, s := range []string{"/ip4/1.2.3.4/tcp/9999", "/ip4/5.6.7.8/tcp/9000/ipfs/QmNnooDu7bfjPFoTZYxMNLWUQJyrVwtbZg5gBMjTezGAJN/p2p-circuit"} {
+		addr, err := ma.NewMultiaddr(s)
+		if err != nil {
+			test.Fatal(err)
+		}
+		peer.Addrs = append(peer.Addrs, addr)
+	}
+	msg, err := proto.GetMessagePayload(NewPingMessage(peer, false).ConstructPingMessage())
+	if err != nil {
+		test.Fatal("GetMessagePayload Failed!")
+	}
+	ping, err := GetPingMessage(msg)
+	if err != nil {
+		test.Fatal("Ping failed!")
+	}
+	addrs := ping.Node.Multiaddrs()
+	if len(addrs) != len(peer.Addrs) {
+		test.Fatalf("expect addrs %v, got %v", peer.Addrs, addrs)
+	}
+	for i := range addrs {
+		if !addrs[i].Equal(peer.Addrs[i]) {
+			test.Errorf("expect addrs %v, got %v", peer.Addrs, addrs)
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/harmony-one/harmony/api/proto"
 	peer "github.com/libp2p/go-libp2p-peer"
+	ma "github.com/multiformats/go-multiaddr"
 )
 
 // MessageType is to indicate the specific type of message under Node category
@@ -84,7 +85,20 @@ type Info struct {
 	Port   string
 	PubKey []byte
 	Role   RoleType
-	PeerID peer.ID // Peerstore ID
+	PeerID peer.ID  // Peerstore ID
+	Addrs  []string // multiaddrs the node advertises, e.g. public or relay addresses
+}
+
+// Multiaddrs returns the advertised addresses of the node, skipping the
+// invalid ones.
+func (info Info) Multiaddrs() []ma.Multiaddr {
+	var addrs []ma.Multiaddr
+	for _, s := range info.Addrs {
+		if addr, err := ma.NewMultiaddr(s); err == nil {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
 }
 
 func (info Info) String() string {
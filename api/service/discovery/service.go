@@ -75,11 +75,7 @@ func (s *Service) Run() {
 func (s *Service) contactP2pPeers() {
 	tick := time.NewTicker(5 * time.Second)
 
-	pingMsg := proto_discovery.NewPingMessage(s.host.GetSelfPeer(), s.config.IsClient)
-
-	utils.GetLogInstance().Info("Constructing Ping Message", "myPing", pingMsg)
-	msgBuf := host.ConstructP2pMessage(byte(0), pingMsg.ConstructPingMessage())
-	s.sentPingMessage(s.config.ShardGroupID, msgBuf)
+	s.sentPingMessage(s.config.ShardGroupID, s.pingMessage())
 
 	for {
 		select {
@@ -114,13 +110,24 @@ func (s *Service) contactP2pPeers() {
 				}
 
 				if a == p2p.ActionStart || a == p2p.ActionResume || a == p2p.ActionPause {
-					s.sentPingMessage(g, msgBuf)
+					s.sentPingMessage(g, s.pingMessage())
 				}
 			}
 		}
 	}
 }
 
+// pingMessage constructs the ping message of the node. It advertises the
+// addresses the host currently knows it is reachable at, which change as
+// port mappings, observed addresses and AutoNAT results come in.
+func (s *Service) pingMessage() []byte {
+	self := s.host.GetSelfPeer()
+	self.Addrs = s.host.GetAdvertisedAddrs()
+	pingMsg := proto_discovery.NewPingMessage(self, s.config.IsClient)
+	utils.GetLogInstance().Debug("Constructing Ping Message", "myPing", pingMsg, "addrs", self.Addrs)
+	return host.ConstructP2pMessage(byte(0), pingMsg.ConstructPingMessage())
+}
+
 // sentPingMessage sends a ping message to a pubsub topic
 func (s *Service) sentPingMessage(g p2p.GroupID, msgBuf []byte) {
 	var err error
@@ -20,6 +20,7 @@ import (
 	"github.com/harmony-one/harmony/core/types"
 	common2 "github.com/harmony-one/harmony/internal/common"
 	"github.com/harmony-one/harmony/internal/ctxerror"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/p2p"
 )
@@ -45,15 +46,17 @@ type Service struct {
 	server            *http.Server
 	messageChan       chan *msg_pb.Message
 	GetAccountBalance func(common.Address) (*big.Int, error)
+	config            *hmyparams.ChainConfig
 }
 
-// New returns explorer service.
-func New(selfPeer *p2p.Peer, GetNodeIDs func() []libp2p_peer.ID, GetAccountBalance func(common.Address) (*big.Int, error)) *Service {
+// New returns explorer service for the chain of the given configuration.
+func New(selfPeer *p2p.Peer, GetNodeIDs func() []libp2p_peer.ID, GetAccountBalance func(common.Address) (*big.Int, error), config *hmyparams.ChainConfig) *Service {
 	return &Service{
 		IP:                selfPeer.IP,
 		Port:              selfPeer.Port,
 		GetNodeIDs:        GetNodeIDs,
 		GetAccountBalance: GetAccountBalance,
+		config:            config,
 	}
 }
 
@@ -200,7 +203,7 @@ func (s *Service) GetExplorerBlocks(w http.ResponseWriter, r *http.Request) {
 		block := NewBlock(accountBlock, id+fromInt-1)
 		// Populate transactions
 		for _, tx := range accountBlock.Transactions() {
-			transaction := GetTransaction(tx, accountBlock)
+			transaction := GetTransaction(tx, accountBlock, s.config)
 			if transaction != nil {
 				block.TXs = append(block.TXs, transaction)
 			}
@@ -11,6 +11,7 @@ import (
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/harmony-one/harmony/core/types"
 	"github.com/harmony-one/harmony/internal/ctxerror"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
 	"github.com/harmony-one/harmony/internal/utils"
 )
 
@@ -84,7 +85,8 @@ func (storage *Storage) GetDB() *ethdb.LDBDatabase {
 }
 
 // Dump extracts information from block and index them into lvdb for explorer.
-func (storage *Storage) Dump(block *types.Block, height uint32) {
+// The block belongs to the chain of the given configuration.
+func (storage *Storage) Dump(block *types.Block, height uint32, config *hmyparams.ChainConfig) {
 	utils.GetLogInstance().Info("Dumping block ", "block height", height)
 	if block == nil {
 		return
@@ -112,7 +114,7 @@ func (storage *Storage) Dump(block *types.Block, height uint32) {
 			continue
 		}
 
-		explorerTransaction := GetTransaction(tx, block)
+		explorerTransaction := GetTransaction(tx, block, config)
 		storage.UpdateTXStorage(batch, explorerTransaction, tx)
 		storage.UpdateAddress(batch, explorerTransaction, tx)
 	}
@@ -164,3 +166,94 @@ func (storage *Storage) UpdateAddressStorage(batch ethdb.Batch, adr string, expl
 		utils.GetLogInstance().Error("Can not encode address account.")
 	}
 }
+
+// Rewind removes the blocks numbered above blockNum, along with their
+// transactions, from the storage. The blocks belong to the chain of the given
+// configuration.
+func (storage *Storage) Rewind(blockNum uint64, config *hmyparams.ChainConfig) error {
+	res, err := storage.db.Get([]byte(BlockHeightKey))
+	if err != nil {
+		// Nothing dumped yet.
+		return nil
+	}
+	height, err := strconv.Atoi(string(res))
+	if err != nil {
+		return ctxerror.New("invalid explorer block height", "height", string(res)).WithCause(err)
+	}
+	batch := storage.db.NewBatch()
+	addresses := make(map[string]*Address)
+	newHeight := 0
+	for ; height > 0; height-- {
+		data, err := storage.db.Get([]byte(GetBlockKey(height)))
+		if err != nil {
+			continue
+		}
+		block := new(types.Block)
+		if err := rlp.DecodeBytes(data, block); err != nil {
+			return ctxerror.New("cannot decode explorer block", "height", height).WithCause(err)
+		}
+		if block.NumberU64() <= blockNum {
+			newHeight = height
+			break
+		}
+		for _, tx := range block.Transactions() {
+			if tx.To() == nil {
+				continue
+			}
+			explorerTransaction := GetTransaction(tx, block, config)
+			if err := batch.Delete([]byte(GetTXKey(tx.Hash().Hex()))); err != nil {
+				return ctxerror.New("cannot batch TX deletion").WithCause(err)
+			}
+			for _, adr := range []string{explorerTransaction.To, explorerTransaction.From} {
+				if err := storage.rewindAddress(addresses, adr, tx); err != nil {
+					return err
+				}
+			}
+		}
+		if err := batch.Delete([]byte(GetBlockKey(height))); err != nil {
+			return ctxerror.New("cannot batch block deletion", "height", height).WithCause(err)
+		}
+	}
+	for key, address := range addresses {
+		encoded, err := rlp.EncodeToBytes(address)
+		if err != nil {
+			return ctxerror.New("cannot encode address", "address", address.ID).WithCause(err)
+		}
+		if err := batch.Put([]byte(key), encoded); err != nil {
+			return ctxerror.New("cannot batch address").WithCause(err)
+		}
+	}
+	if err := batch.Put([]byte(BlockHeightKey), []byte(strconv.Itoa(newHeight))); err != nil {
+		return ctxerror.New("cannot batch block height").WithCause(err)
+	}
+	return batch.Write()
+}
+
+// rewindAddress undoes what UpdateAddressStorage did for tx on address adr.
+// Addresses are loaded into the given map once and updated there.
+func (storage *Storage) rewindAddress(addresses map[string]*Address, adr string, tx *types.Transaction) error {
+	key := GetAddressKey(adr)
+	address, ok := addresses[key]
+	if !ok {
+		data, err := storage.db.Get([]byte(key))
+		if err != nil {
+			return nil
+		}
+		address = new(Address)
+		if err := rlp.DecodeBytes(data, address); err != nil {
+			return ctxerror.New("cannot decode address", "address", adr).WithCause(err)
+		}
+		addresses[key] = address
+	}
+	id := tx.Hash().Hex()
+	for i := len(address.TXs) - 1; i >= 0; i-- {
+		if address.TXs[i].ID == id {
+			address.TXs = append(address.TXs[:i], address.TXs[i+1:]...)
+			if address.Balance != nil {
+				address.Balance.Sub(address.Balance, tx.Value())
+			}
+			break
+		}
+	}
+	return nil
+}
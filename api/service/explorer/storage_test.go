@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/harmony-one/harmony/core/types"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -50,7 +51,7 @@ func TestDump(t *testing.T) {
 
 	block := types.NewBlock(&types.Header{Number: big.NewInt(314)}, txs, nil)
 	ins := GetStorageInstance("1.1.1.1", "3333", true)
-	ins.Dump(block, uint32(1))
+	ins.Dump(block, uint32(1), hmyparams.TestChainConfig)
 	db := ins.GetDB()
 
 	res, err := db.Get([]byte(BlockHeightKey))
@@ -77,7 +78,7 @@ func TestUpdateAddressStorage(t *testing.T) {
 
 	block := types.NewBlock(&types.Header{Number: big.NewInt(314)}, txs, nil)
 	ins := GetStorageInstance("1.1.1.1", "3333", true)
-	ins.Dump(block, uint32(1))
+	ins.Dump(block, uint32(1), hmyparams.TestChainConfig)
 	db := ins.GetDB()
 
 	res, err := db.Get([]byte(BlockHeightKey))
@@ -95,3 +96,26 @@ func TestUpdateAddressStorage(t *testing.T) {
 	assert.Nil(t, err, "should be nil")
 	assert.Equal(t, bytes.Compare(data, blockData), 0, "should be equal")
 }
+
+func TestRewind(t *testing.T) {
+	tx1 := types.NewTransaction(1, common.BytesToAddress([]byte{0x11}), 0, big.NewInt(111), 1111, big.NewInt(11111), []byte{0x11, 0x11, 0x11})
+	tx2 := types.NewTransaction(2, common.BytesToAddress([]byte{0x22}), 0, big.NewInt(222), 2222, big.NewInt(22222), []byte{0x22, 0x22, 0x22})
+
+	block1 := types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{tx1}, nil)
+	block2 := types.NewBlock(&types.Header{Number: big.NewInt(2)}, []*types.Transaction{tx2}, nil)
+	ins := GetStorageInstance("1.1.1.1", "3333", true)
+	ins.Dump(block1, uint32(1), hmyparams.TestChainConfig)
+	ins.Dump(block2, uint32(2), hmyparams.TestChainConfig)
+	db := ins.GetDB()
+
+	assert.Nil(t, ins.Rewind(1, hmyparams.TestChainConfig), "error")
+	res, err := db.Get([]byte(BlockHeightKey))
+	assert.Nil(t, err, "error")
+	assert.Equal(t, string(res), "1", "block height should be rewound")
+	_, err = db.Get([]byte(GetBlockKey(2)))
+	assert.NotNil(t, err, "rewound block should be deleted")
+	_, err = db.Get([]byte(GetTXKey(tx2.Hash().Hex())))
+	assert.NotNil(t, err, "rewound transaction should be deleted")
+	_, err = db.Get([]byte(GetTXKey(tx1.Hash().Hex())))
+	assert.Nil(t, err, "kept transaction should not be deleted")
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/harmony-one/harmony/core/types"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
 )
 
 /*
@@ -82,12 +83,13 @@ func NewBlock(block *types.Block, height int) *Block {
 	}
 }
 
-// GetTransaction ...
-func GetTransaction(tx *types.Transaction, accountBlock *types.Block) *Transaction {
+// GetTransaction returns the explorer transaction of a transaction of the
+// given block, on the chain of the given configuration.
+func GetTransaction(tx *types.Transaction, accountBlock *types.Block, config *hmyparams.ChainConfig) *Transaction {
 	if tx.To() == nil {
 		return nil
 	}
-	msg, err := tx.AsMessage(types.HomesteadSigner{})
+	msg, err := tx.AsMessage(types.MakeShardSigner(config, accountBlock.ShardID(), accountBlock.Header().Epoch))
 	if err != nil {
 		Log.Error("Error when parsing tx into message")
 	}
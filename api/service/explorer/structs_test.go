@@ -9,6 +9,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/harmony-one/harmony/core/types"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
 )
 
 // Test for GetBlockInfoKey
@@ -20,7 +21,7 @@ func TestGetTransaction(t *testing.T) {
 
 	block := types.NewBlock(&types.Header{Number: big.NewInt(314)}, txs, nil)
 
-	tx := GetTransaction(tx1, block)
+	tx := GetTransaction(tx1, block, hmyparams.TestChainConfig)
 	assert.Equal(t, tx.ID, tx1.Hash().Hex(), "should be equal tx1.Hash()")
 	assert.Equal(t, tx.To, tx1.To().Hex(), "should be equal tx1.To()") // TODO ek – use bech32
 	assert.Equal(t, tx.Bytes, strconv.Itoa(int(tx1.Size())), "should be equal tx1.Size()")
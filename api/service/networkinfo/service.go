@@ -13,6 +13,7 @@ import (
 	"github.com/harmony-one/harmony/p2p"
 	libp2pdis "github.com/libp2p/go-libp2p-discovery"
 	libp2pdht "github.com/libp2p/go-libp2p-kad-dht"
+	dhtopts "github.com/libp2p/go-libp2p-kad-dht/opts"
 	peerstore "github.com/libp2p/go-libp2p-peerstore"
 	manet "github.com/multiformats/go-multiaddr-net"
 )
@@ -50,11 +51,14 @@ const (
 	dhtTicker = 6 * time.Hour
 )
 
-// New returns role conversion service.
+// New returns role conversion service. The rendezvous point and the DHT
+// protocol are scoped to the network of the host, so that only peers of the
+// same network find each other.
 func New(h p2p.Host, rendezvous p2p.GroupID, peerChan chan p2p.Peer, bootnodes utils.AddrList) *Service {
 	var cancel context.CancelFunc
 	ctx, cancel = context.WithTimeout(context.Background(), connectionTimeout)
-	dht, err := libp2pdht.New(ctx, h.GetP2PHost())
+	dht, err := libp2pdht.New(ctx, h.GetP2PHost(),
+		dhtopts.Protocols(h.GetNetworkScope().ProtocolID(libp2pdht.ProtocolDHT)))
 	if err != nil {
 		panic(err)
 	}
@@ -62,7 +66,7 @@ func New(h p2p.Host, rendezvous p2p.GroupID, peerChan chan p2p.Peer, bootnodes u
 	return &Service{
 		Host:        h,
 		dht:         dht,
-		Rendezvous:  rendezvous,
+		Rendezvous:  h.GetNetworkScope().GroupID(rendezvous),
 		cancel:      cancel,
 		stopChan:    make(chan struct{}),
 		stoppedChan: make(chan struct{}),
@@ -101,13 +105,22 @@ func (s *Service) Init() error {
 		s.bootnodes = utils.BootNodes
 	}
 
+	// The host may already be connected to the known peers it restored on
+	// startup. Then the bootnodes are tried once each, and not needed.
+	restored := len(s.Host.GetP2PHost().Network().Peers())
+	retries := ConnectionRetry
+	if restored > 0 {
+		utils.GetLogInstance().Info("connected to known peers", "peers", restored)
+		retries = 1
+	}
+
 	connected := false
 	for _, peerAddr := range s.bootnodes {
 		peerinfo, _ := peerstore.InfoFromP2pAddr(peerAddr)
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			for i := 0; i < ConnectionRetry; i++ {
+			for i := 0; i < retries; i++ {
 				if err := s.Host.GetP2PHost().Connect(ctx, *peerinfo); err != nil {
 					utils.GetLogInstance().Warn("can't connect to bootnode", "error", err, "try", i)
 					time.Sleep(waitInRetry)
@@ -122,7 +135,7 @@ func (s *Service) Init() error {
 	}
 	wg.Wait()
 
-	if !connected {
+	if !connected && restored == 0 {
 		return fmt.Errorf("[FATAL] error connecting to bootnodes")
 	}
 
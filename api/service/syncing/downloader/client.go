@@ -10,6 +10,10 @@ import (
 	"google.golang.org/grpc"
 )
 
+// maxBlockSize bounds the size of the blocks received from peers once
+// decompressed.
+const maxBlockSize = 32 << 20
+
 // Client is the client model for downloader package.
 type Client struct {
 	dlClient pb.DownloaderClient
@@ -53,10 +57,12 @@ func (client *Client) GetBlockHashes(startHash []byte) *pb.DownloaderResponse {
 }
 
 // GetBlocks gets blocks in serialization byte array by calling a grpc request.
+// Peers which support it send the blocks snappy-compressed; they are returned
+// decompressed.
 func (client *Client) GetBlocks(hashes [][]byte) *pb.DownloaderResponse {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	request := &pb.DownloaderRequest{Type: pb.DownloaderRequest_BLOCK}
+	request := &pb.DownloaderRequest{Type: pb.DownloaderRequest_BLOCK, Snappy: true}
 	request.Hashes = make([][]byte, len(hashes))
 	for i := range hashes {
 		request.Hashes[i] = make([]byte, len(hashes[i]))
@@ -66,6 +72,12 @@ func (client *Client) GetBlocks(hashes [][]byte) *pb.DownloaderResponse {
 	if err != nil {
 		utils.GetLogInstance().Info("[SYNC] downloader/client.go:GetBlocks query failed.", "error", err)
 	}
+	if response != nil {
+		if err := response.DecompressPayload(maxBlockSize); err != nil {
+			utils.GetLogInstance().Info("[SYNC] downloader/client.go:GetBlocks cannot decompress blocks.", "error", err)
+			return nil
+		}
+	}
 	return response
 }
 
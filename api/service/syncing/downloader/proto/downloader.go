@@ -1,3 +1,45 @@
 package downloader
 
 //go:generate protoc downloader.proto --go_out=plugins=grpc:.
+
+import (
+	"fmt"
+
+	"github.com/golang/snappy"
+)
+
+// CompressPayload snappy-compresses the payloads of a response if its request
+// accepts compressed payloads.
+func (m *DownloaderResponse) CompressPayload(request *DownloaderRequest) {
+	if !request.GetSnappy() || m.Snappy {
+		return
+	}
+	for i, payload := range m.Payload {
+		m.Payload[i] = snappy.Encode(nil, payload)
+	}
+	m.Snappy = true
+}
+
+// DecompressPayload decompresses the payloads of a response if they are
+// compressed, rejecting payloads larger than maxSize bytes once decompressed.
+func (m *DownloaderResponse) DecompressPayload(maxSize int) error {
+	if !m.Snappy {
+		return nil
+	}
+	payloads := make([][]byte, len(m.Payload))
+	for i, payload := range m.Payload {
+		size, err := snappy.DecodedLen(payload)
+		if err != nil {
+			return err
+		}
+		if size > maxSize {
+			return fmt.Errorf("payload too large: %d > %d bytes", size, maxSize)
+		}
+		if payloads[i], err = snappy.Decode(nil, payload); err != nil {
+			return err
+		}
+	}
+	m.Payload = payloads
+	m.Snappy = false
+	return nil
+}
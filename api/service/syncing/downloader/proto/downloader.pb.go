@@ -95,11 +95,13 @@ type DownloaderRequest struct {
 	// Request type.
 	Type DownloaderRequest_RequestType `protobuf:"varint,1,opt,name=type,proto3,enum=downloader.DownloaderRequest_RequestType" json:"type,omitempty"`
 	// The hashes of the blocks we want to download.
-	Hashes               [][]byte `protobuf:"bytes,2,rep,name=hashes,proto3" json:"hashes,omitempty"`
-	PeerHash             []byte   `protobuf:"bytes,3,opt,name=peerHash,proto3" json:"peerHash,omitempty"`
-	BlockHash            []byte   `protobuf:"bytes,4,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
-	Ip                   string   `protobuf:"bytes,5,opt,name=ip,proto3" json:"ip,omitempty"`
-	Port                 string   `protobuf:"bytes,6,opt,name=port,proto3" json:"port,omitempty"`
+	Hashes    [][]byte `protobuf:"bytes,2,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	PeerHash  []byte   `protobuf:"bytes,3,opt,name=peerHash,proto3" json:"peerHash,omitempty"`
+	BlockHash []byte   `protobuf:"bytes,4,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	Ip        string   `protobuf:"bytes,5,opt,name=ip,proto3" json:"ip,omitempty"`
+	Port      string   `protobuf:"bytes,6,opt,name=port,proto3" json:"port,omitempty"`
+	// Whether the requester accepts snappy-compressed payloads.
+	Snappy               bool     `protobuf:"varint,7,opt,name=snappy,proto3" json:"snappy,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -172,16 +174,25 @@ func (m *DownloaderRequest) GetPort() string {
 	return ""
 }
 
+func (m *DownloaderRequest) GetSnappy() bool {
+	if m != nil {
+		return m.Snappy
+	}
+	return false
+}
+
 // DownloaderResponse is the generic response of DownloaderRequest.
 type DownloaderResponse struct {
 	// payload of Block.
 	Payload [][]byte `protobuf:"bytes,1,rep,name=payload,proto3" json:"payload,omitempty"`
 	// response of registration request
-	Type                 DownloaderResponse_RegisterResponseType `protobuf:"varint,2,opt,name=type,proto3,enum=downloader.DownloaderResponse_RegisterResponseType" json:"type,omitempty"`
-	BlockHeight          uint64                                  `protobuf:"varint,3,opt,name=blockHeight,proto3" json:"blockHeight,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                                `json:"-"`
-	XXX_unrecognized     []byte                                  `json:"-"`
-	XXX_sizecache        int32                                   `json:"-"`
+	Type        DownloaderResponse_RegisterResponseType `protobuf:"varint,2,opt,name=type,proto3,enum=downloader.DownloaderResponse_RegisterResponseType" json:"type,omitempty"`
+	BlockHeight uint64                                  `protobuf:"varint,3,opt,name=blockHeight,proto3" json:"blockHeight,omitempty"`
+	// Whether the payloads are snappy-compressed.
+	Snappy               bool     `protobuf:"varint,4,opt,name=snappy,proto3" json:"snappy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *DownloaderResponse) Reset()         { *m = DownloaderResponse{} }
@@ -230,6 +241,13 @@ func (m *DownloaderResponse) GetBlockHeight() uint64 {
 	return 0
 }
 
+func (m *DownloaderResponse) GetSnappy() bool {
+	if m != nil {
+		return m.Snappy
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterEnum("downloader.DownloaderRequest_RequestType", DownloaderRequest_RequestType_name, DownloaderRequest_RequestType_value)
 	proto.RegisterEnum("downloader.DownloaderResponse_RegisterResponseType", DownloaderResponse_RegisterResponseType_name, DownloaderResponse_RegisterResponseType_value)
@@ -240,32 +258,32 @@ func init() {
 func init() { proto.RegisterFile("downloader.proto", fileDescriptor_6a99ec95c7ab1ff1) }
 
 var fileDescriptor_6a99ec95c7ab1ff1 = []byte{
-	// 388 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x92, 0xdf, 0x6f, 0x94, 0x40,
-	0x10, 0xc7, 0x6f, 0x39, 0xa0, 0x77, 0xc3, 0xa5, 0x5d, 0x47, 0x63, 0x48, 0xa3, 0x86, 0xf0, 0x84,
-	0x2f, 0x3c, 0xb4, 0x4f, 0x3e, 0xf8, 0x50, 0xe9, 0x7a, 0x90, 0x56, 0x2e, 0x2e, 0x9c, 0x8d, 0x8f,
-	0xd4, 0x6e, 0x0a, 0xb1, 0x29, 0x2b, 0x4b, 0x63, 0xf8, 0xe3, 0xfc, 0x2f, 0xfc, 0x83, 0x0c, 0x7b,
-	0x3f, 0x20, 0x51, 0xef, 0x69, 0xe7, 0xfb, 0x9d, 0x9d, 0xc9, 0xcc, 0x27, 0x03, 0xf4, 0xae, 0xfe,
-	0xf9, 0xf8, 0x50, 0x17, 0x77, 0xa2, 0x09, 0x65, 0x53, 0xb7, 0x35, 0xc2, 0xe0, 0xf8, 0xbf, 0x0c,
-	0x78, 0x76, 0xb9, 0x97, 0x5c, 0xfc, 0x78, 0x12, 0xaa, 0xc5, 0xf7, 0x60, 0xb6, 0x9d, 0x14, 0x2e,
-	0xf1, 0x48, 0x70, 0x7c, 0xf6, 0x36, 0x1c, 0xb5, 0xf8, 0xeb, 0x73, 0xb8, 0x7d, 0xf3, 0x4e, 0x0a,
-	0xae, 0xcb, 0xf0, 0x25, 0xd8, 0x65, 0xa1, 0x4a, 0xa1, 0x5c, 0xc3, 0x9b, 0x06, 0x0b, 0xbe, 0x55,
-	0x78, 0x0a, 0x33, 0x29, 0x44, 0x13, 0x17, 0xaa, 0x74, 0xa7, 0x1e, 0x09, 0x16, 0x7c, 0xaf, 0xf1,
-	0x15, 0xcc, 0x6f, 0x1f, 0xea, 0x6f, 0xdf, 0x75, 0xd2, 0xd4, 0xc9, 0xc1, 0xc0, 0x63, 0x30, 0x2a,
-	0xe9, 0x5a, 0x1e, 0x09, 0xe6, 0xdc, 0xa8, 0x24, 0x22, 0x98, 0xb2, 0x6e, 0x5a, 0xd7, 0xd6, 0x8e,
-	0x8e, 0x7d, 0x05, 0xce, 0x68, 0x14, 0x04, 0xb0, 0x63, 0x76, 0x71, 0xc9, 0x38, 0x9d, 0xe0, 0x1c,
-	0xac, 0x0f, 0xd7, 0xab, 0xe8, 0x8a, 0x12, 0x5c, 0xc0, 0x2c, 0x65, 0x37, 0x1b, 0x65, 0xe0, 0x09,
-	0x38, 0x3a, 0x8c, 0x59, 0xb2, 0x8c, 0x73, 0x3a, 0xed, 0xd3, 0x9c, 0x2d, 0x93, 0x2c, 0x67, 0x9c,
-	0x9a, 0xf8, 0x1c, 0x4e, 0x76, 0x2a, 0x4f, 0x3e, 0xb1, 0xd5, 0x3a, 0xa7, 0x16, 0x3a, 0x70, 0xb4,
-	0x4e, 0xaf, 0xd2, 0xd5, 0x4d, 0x4a, 0x6d, 0xff, 0x37, 0x01, 0x1c, 0x23, 0x51, 0xb2, 0x7e, 0x54,
-	0x02, 0x5d, 0x38, 0x92, 0x45, 0xd7, 0x9b, 0x2e, 0xd1, 0x08, 0x76, 0x12, 0x97, 0x5b, 0xb4, 0x86,
-	0x46, 0x7b, 0xfe, 0x3f, 0xb4, 0x9b, 0x3e, 0x21, 0x17, 0xf7, 0x95, 0x6a, 0x07, 0x63, 0x04, 0xd9,
-	0x03, 0x67, 0xc3, 0x47, 0x54, 0xf7, 0x65, 0xab, 0x79, 0x9a, 0x7c, 0x6c, 0xf9, 0xef, 0xe0, 0xc5,
-	0xbf, 0xea, 0xfb, 0x05, 0xb2, 0x75, 0x14, 0xb1, 0x2c, 0xa3, 0x13, 0x9c, 0x81, 0xf9, 0xf1, 0x22,
-	0xb9, 0xa6, 0xa4, 0x07, 0x96, 0xa4, 0xd9, 0xd7, 0x34, 0xa2, 0xc6, 0xd9, 0x17, 0x80, 0x61, 0x1a,
-	0x8c, 0xc1, 0xfa, 0xfc, 0x24, 0x9a, 0x0e, 0x5f, 0x1f, 0xbc, 0x84, 0xd3, 0x37, 0x87, 0xb7, 0xf1,
-	0x27, 0xb7, 0xb6, 0xbe, 0xc0, 0xf3, 0x3f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x45, 0x42, 0x00, 0x51,
-	0x95, 0x02, 0x00, 0x00,
+	// 393 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7d, 0x92, 0xcf, 0x4e, 0xc2, 0x40,
+	0x10, 0xc6, 0xa1, 0x94, 0x02, 0x53, 0x02, 0x75, 0x34, 0xa6, 0x21, 0x6a, 0x08, 0x27, 0xbc, 0x70,
+	0x80, 0x93, 0x07, 0x0f, 0x08, 0x95, 0x36, 0x62, 0x89, 0xdb, 0x22, 0xf1, 0x08, 0xb2, 0x01, 0x22,
+	0xa1, 0x6b, 0x5b, 0x62, 0x78, 0x50, 0xdf, 0xc2, 0x87, 0x70, 0xbb, 0xfc, 0x69, 0x8d, 0xca, 0x69,
+	0xe7, 0xfb, 0x66, 0x67, 0x76, 0xe6, 0x97, 0x05, 0x6d, 0xea, 0x7d, 0xac, 0x96, 0xde, 0x78, 0x4a,
+	0xfd, 0x06, 0xf3, 0xbd, 0xd0, 0x43, 0x88, 0x9d, 0xda, 0xa7, 0x04, 0x27, 0xdd, 0x83, 0x24, 0xf4,
+	0x7d, 0x4d, 0x83, 0x10, 0x6f, 0x41, 0x0e, 0x37, 0x8c, 0xea, 0xe9, 0x6a, 0xba, 0x5e, 0x6a, 0x5e,
+	0x37, 0x12, 0x2d, 0x7e, 0x5d, 0x6e, 0xec, 0x4e, 0x97, 0x17, 0x10, 0x51, 0x86, 0xe7, 0xa0, 0xcc,
+	0xc7, 0xc1, 0x9c, 0x06, 0xba, 0x54, 0xcd, 0xd4, 0x8b, 0x64, 0xa7, 0xb0, 0x02, 0x79, 0x46, 0xa9,
+	0x6f, 0x72, 0xa5, 0x67, 0x78, 0xeb, 0x22, 0x39, 0x68, 0xbc, 0x80, 0xc2, 0x64, 0xe9, 0xbd, 0xbe,
+	0x89, 0xa4, 0x2c, 0x92, 0xb1, 0x81, 0x25, 0x90, 0x16, 0x4c, 0xcf, 0x72, 0xbb, 0x40, 0x78, 0x84,
+	0x08, 0x32, 0xf3, 0xfc, 0x50, 0x57, 0x84, 0x23, 0xe2, 0xe8, 0xd5, 0x60, 0x35, 0x66, 0x6c, 0xa3,
+	0xe7, 0xb8, 0x9b, 0x27, 0x3b, 0x55, 0x0b, 0x40, 0x4d, 0x8c, 0x88, 0x00, 0x8a, 0x69, 0xb4, 0xbb,
+	0x06, 0xd1, 0x52, 0x58, 0x80, 0xec, 0x5d, 0x7f, 0xd0, 0x79, 0xd0, 0xd2, 0x58, 0x84, 0xbc, 0x6d,
+	0x8c, 0xb6, 0x4a, 0xc2, 0x32, 0xa8, 0x22, 0x34, 0x0d, 0xab, 0x67, 0xba, 0x5a, 0x26, 0x4a, 0x13,
+	0xa3, 0x67, 0x39, 0x2e, 0xaf, 0x93, 0xf1, 0x14, 0xca, 0x7b, 0xe5, 0x5a, 0x8f, 0xc6, 0x60, 0xe8,
+	0x6a, 0x59, 0x54, 0x21, 0x37, 0xb4, 0x1f, 0xec, 0xc1, 0xc8, 0xd6, 0x94, 0xda, 0x57, 0x1a, 0x30,
+	0x89, 0x2a, 0x60, 0xde, 0x2a, 0xa0, 0xa8, 0x43, 0x8e, 0x8d, 0x37, 0x91, 0xc9, 0xd9, 0x46, 0x68,
+	0xf6, 0x12, 0x7b, 0x3b, 0xe4, 0x92, 0x40, 0xde, 0xfa, 0x0f, 0xf9, 0xb6, 0x0f, 0x67, 0x3e, 0x5b,
+	0x04, 0x61, 0x6c, 0x24, 0xe0, 0x57, 0x41, 0xdd, 0x72, 0xa3, 0x8b, 0xd9, 0x3c, 0x14, 0x9c, 0x65,
+	0x92, 0xb4, 0x12, 0xa0, 0xe4, 0x1f, 0xa0, 0x6e, 0xe0, 0xec, 0xaf, 0xbe, 0xd1, 0x62, 0xce, 0xb0,
+	0xd3, 0x31, 0x1c, 0x87, 0x23, 0xcb, 0x83, 0x7c, 0xdf, 0xb6, 0xfa, 0x9c, 0x18, 0x07, 0x69, 0xd9,
+	0xce, 0x8b, 0xdd, 0xd1, 0xa4, 0xe6, 0x33, 0x40, 0x3c, 0x25, 0x9a, 0x90, 0x7d, 0x5a, 0x53, 0x7f,
+	0x83, 0x97, 0x47, 0x7f, 0x4e, 0xe5, 0xea, 0xf8, 0x96, 0xb5, 0xd4, 0x44, 0x11, 0x3f, 0xb6, 0xf5,
+	0x0d, 0x97, 0x71, 0xf1, 0x82, 0xc5, 0x02, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
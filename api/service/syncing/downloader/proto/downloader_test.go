@@ -0,0 +1,31 @@
+package downloader
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCompressPayload(t *testing.T) {
+	payload := [][]byte{bytes.Repeat([]byte{1}, 1000), {}}
+
+	response := &DownloaderResponse{Payload: [][]byte{payload[0], payload[1]}}
+	response.CompressPayload(&DownloaderRequest{})
+	if response.Snappy || !bytes.Equal(response.Payload[0], payload[0]) {
+		t.Fatal("payload compressed for a request that does not accept it")
+	}
+
+	response.CompressPayload(&DownloaderRequest{Snappy: true})
+	if !response.Snappy || len(response.Payload[0]) >= len(payload[0]) {
+		t.Fatalf("payload not compressed: %d bytes", len(response.Payload[0]))
+	}
+	if err := response.DecompressPayload(100); err == nil {
+		t.Error("payload larger than the maximum size decompressed")
+	}
+	if err := response.DecompressPayload(1000); err != nil {
+		t.Fatalf("DecompressPayload: %v", err)
+	}
+	if response.Snappy || len(response.Payload) != 2 ||
+		!bytes.Equal(response.Payload[0], payload[0]) || len(response.Payload[1]) != 0 {
+		t.Errorf("unexpected decompressed payload %v", response.Payload)
+	}
+}
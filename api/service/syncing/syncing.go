@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
+	"net"
 	"reflect"
 	"sort"
 	"strconv"
@@ -21,6 +22,7 @@ import (
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/node/worker"
 	"github.com/harmony-one/harmony/p2p"
+	libp2p_peer "github.com/libp2p/go-libp2p-peer"
 )
 
 // Constants for syncing.
@@ -37,6 +39,7 @@ const (
 type SyncPeerConfig struct {
 	ip          string
 	port        string
+	peerID      libp2p_peer.ID
 	peerHash    []byte
 	client      *downloader.Client
 	blockHashes [][]byte       // block hashes before node doing sync
@@ -91,6 +94,8 @@ func CreateStateSync(ip string, port string, peerHash [20]byte) *StateSync {
 	stateSync.selfPeerHash = peerHash
 	stateSync.commonBlocks = make(map[int]*types.Block)
 	stateSync.lastMileBlocks = []*types.Block{}
+	stateSync.blockSources = make(map[common.Hash]*SyncPeerConfig)
+	stateSync.lastMileSources = make(map[common.Hash]libp2p_peer.ID)
 	return stateSync
 }
 
@@ -100,17 +105,63 @@ type StateSync struct {
 	selfport           string
 	selfPeerHash       [20]byte // hash of ip and address combination
 	commonBlocks       map[int]*types.Block
-	lastMileBlocks     []*types.Block // last mile blocks to catch up with the consensus
+	lastMileBlocks     []*types.Block                  // last mile blocks to catch up with the consensus
+	blockSources       map[common.Hash]*SyncPeerConfig // block hash -> peer the block was received from
+	lastMileSources    map[common.Hash]libp2p_peer.ID  // last mile block hash -> author of the message that carried it
 	syncConfig         *SyncConfig
 	stateSyncTaskQueue *queue.Queue
 	syncMux            sync.Mutex
+	peerScorer         *p2p.PeerScorer
 }
 
-// AddLastMileBlock add the lastest a few block into queue for syncing
-func (ss *StateSync) AddLastMileBlock(block *types.Block) {
+// SetPeerScorer sets the scorer to report the behaviour of the sync peers to.
+func (ss *StateSync) SetPeerScorer(peerScorer *p2p.PeerScorer) {
+	ss.peerScorer = peerScorer
+}
+
+// reportPeer reports an event about a sync peer to the peer scorer, if any.
+func (ss *StateSync) reportPeer(peerConfig *SyncPeerConfig, event p2p.PeerEvent) {
+	if ss.peerScorer == nil || peerConfig == nil || peerConfig.peerID == "" {
+		return
+	}
+	if ss.peerScorer.Report(peerConfig.peerID, event) {
+		utils.GetLogInstance().Warn("[SYNC] banned sync peer", "ip", peerConfig.ip, "port", peerConfig.port, "event", event)
+	}
+}
+
+// LastMileBlock is a recent block broadcast to the network, along with the
+// author of the message that carried it, if known.
+type LastMileBlock struct {
+	Block  *types.Block
+	Source libp2p_peer.ID
+}
+
+// AddLastMileBlock add the lastest a few block into queue for syncing. The
+// source of the block is scored once the block is added to the blockchain.
+func (ss *StateSync) AddLastMileBlock(block LastMileBlock) {
 	ss.syncMux.Lock()
 	defer ss.syncMux.Unlock()
-	ss.lastMileBlocks = append(ss.lastMileBlocks, block)
+	ss.lastMileBlocks = append(ss.lastMileBlocks, block.Block)
+	if block.Source != "" {
+		ss.lastMileSources[block.Block.Hash()] = block.Source
+	}
+}
+
+// reportBlockSource reports an event about the peer that provided a block,
+// either a sync peer or the author of a last mile block, and forgets the
+// latter.
+func (ss *StateSync) reportBlockSource(hash common.Hash, source *SyncPeerConfig, event p2p.PeerEvent) {
+	if source != nil {
+		ss.reportPeer(source, event)
+		return
+	}
+	ss.syncMux.Lock()
+	id, ok := ss.lastMileSources[hash]
+	delete(ss.lastMileSources, hash)
+	ss.syncMux.Unlock()
+	if ok && ss.peerScorer != nil && ss.peerScorer.Report(id, event) {
+		utils.GetLogInstance().Warn("[SYNC] banned last mile block source", "peer", id.Pretty(), "event", event)
+	}
 }
 
 // CloseConnections close grpc  connections for state sync clients
@@ -146,6 +197,7 @@ func (ss *StateSync) AddNewBlock(peerHash []byte, block *types.Block) {
 	pc.mux.Lock()
 	defer pc.mux.Unlock()
 	pc.newBlocks = append(pc.newBlocks, block)
+	ss.setBlockSource(block.Hash(), pc)
 	utils.GetLogInstance().Debug("[SYNC] new block received", "total", len(pc.newBlocks), "blockHeight", block.NumberU64())
 }
 
@@ -201,6 +253,7 @@ func (ss *StateSync) CreateSyncConfig(peers []p2p.Peer, isBeacon bool) error {
 			peerConfig := &SyncPeerConfig{
 				ip:     peer.IP,
 				port:   peer.Port,
+				peerID: peer.PeerID,
 				client: client,
 			}
 			ss.syncConfig.AddPeer(peerConfig)
@@ -300,6 +353,7 @@ func (ss *StateSync) GetConsensusHashes(startHash []byte) bool {
 				defer wg.Done()
 				response := peerConfig.client.GetBlockHashes(startHash)
 				if response == nil {
+					ss.reportPeer(peerConfig, p2p.PeerTimeout)
 					return
 				}
 				peerConfig.blockHashes = response.Payload
@@ -355,6 +409,7 @@ func (ss *StateSync) downloadBlocks(bc *core.BlockChain) {
 				//id := syncTask.index
 				payload, err := peerConfig.GetBlocks([][]byte{syncTask.blockHash})
 				if err != nil || len(payload) == 0 {
+					ss.reportPeer(peerConfig, p2p.PeerTimeout)
 					count++
 					utils.GetLogInstance().Debug("[SYNC] GetBlocks failed", "failNumber", count)
 					if count > TimesToFail {
@@ -373,6 +428,7 @@ func (ss *StateSync) downloadBlocks(bc *core.BlockChain) {
 				err = rlp.DecodeBytes(payload[0], &blockObj)
 
 				if err != nil {
+					ss.reportPeer(peerConfig, p2p.PeerInvalidMessage)
 					count++
 					utils.GetLogInstance().Debug("[SYNC] downloadBlocks: failed to DecodeBytes from received new block")
 					if count > TimesToFail {
@@ -388,6 +444,7 @@ func (ss *StateSync) downloadBlocks(bc *core.BlockChain) {
 				ss.syncMux.Lock()
 				ss.commonBlocks[syncTask.index] = &blockObj
 				ss.syncMux.Unlock()
+				ss.setBlockSource(blockObj.Hash(), peerConfig)
 			}
 		}(ss.stateSyncTaskQueue, bc)
 		return
@@ -471,11 +528,36 @@ func (ss *StateSync) getBlockFromLastMileBlocksByParentHash(parentHash common.Ha
 	return nil
 }
 
+// setBlockSource records the peer a block was received from.
+func (ss *StateSync) setBlockSource(hash common.Hash, peerConfig *SyncPeerConfig) {
+	ss.syncMux.Lock()
+	defer ss.syncMux.Unlock()
+	ss.blockSources[hash] = peerConfig
+}
+
+// blockSource returns the peer a block was received from, or nil if unknown.
+func (ss *StateSync) blockSource(hash common.Hash) *SyncPeerConfig {
+	ss.syncMux.Lock()
+	defer ss.syncMux.Unlock()
+	return ss.blockSources[hash]
+}
+
 func (ss *StateSync) updateBlockAndStatus(block *types.Block, bc *core.BlockChain, worker *worker.Worker) bool {
 	utils.GetLogInstance().Info("[SYNC] Current Block", "blockHex", bc.CurrentBlock().Hash().Hex())
-	_, err := bc.InsertChain([]*types.Block{block})
+	source, from := ss.blockSource(block.Hash()), ""
+	if source != nil {
+		from = "peer " + net.JoinHostPort(source.ip, source.port)
+	}
+	_, err := bc.InsertChainFrom([]*types.Block{block}, from)
 	if err != nil {
 		utils.GetLogInstance().Debug("Error adding new block to blockchain", "Error", err)
+		if err != core.ErrKnownBlock {
+			ss.reportBlockSource(block.Hash(), source, p2p.PeerInvalidMessage)
+		} else {
+			ss.syncMux.Lock()
+			delete(ss.lastMileSources, block.Hash())
+			ss.syncMux.Unlock()
+		}
 
 		utils.GetLogInstance().Debug("Rolling back current block!", "block", bc.CurrentBlock())
 		bc.Rollback([]common.Hash{bc.CurrentBlock().Hash()})
@@ -486,6 +568,7 @@ func (ss *StateSync) updateBlockAndStatus(block *types.Block, bc *core.BlockChai
 		ctxerror.Warn(utils.GetLogger(), err, "(*Worker).UpdateCurrent failed")
 	}
 	ss.syncMux.Unlock()
+	ss.reportBlockSource(block.Hash(), source, p2p.PeerUsefulBlock)
 	utils.GetLogInstance().Info("[SYNC] new block added to blockchain", "blockHeight", bc.CurrentBlock().NumberU64(), "blockHex", bc.CurrentBlock().Hash().Hex())
 	return true
 }
@@ -529,6 +612,7 @@ func (ss *StateSync) generateNewState(bc *core.BlockChain, worker *worker.Worker
 		peer.newBlocks = []*types.Block{}
 		return
 	})
+	ss.blockSources = make(map[common.Hash]*SyncPeerConfig)
 	ss.syncMux.Unlock()
 
 	// update last mile blocks if any
@@ -10,7 +10,11 @@ import (
 	"path"
 
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/harmony-one/harmony/common/config"
+	"github.com/harmony-one/harmony/core"
+	shardingconfig "github.com/harmony-one/harmony/internal/configs/sharding"
 	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/node"
 	"github.com/harmony-one/harmony/p2p"
 	"github.com/harmony-one/harmony/p2p/p2pimpl"
 
@@ -18,6 +22,7 @@ import (
 	dsync "github.com/ipfs/go-datastore/sync"
 
 	kaddht "github.com/libp2p/go-libp2p-kad-dht"
+	dhtopts "github.com/libp2p/go-libp2p-kad-dht/opts"
 )
 
 var (
@@ -50,6 +55,10 @@ func main() {
 	versionFlag := flag.Bool("version", false, "Output version info")
 	verbosity := flag.Int("verbosity", 5, "Logging verbosity: 0=silent, 1=error, 2=warn, 3=info, 4=debug, 5=detail (default: 5)")
 	logConn := flag.Bool("log_conn", false, "log incoming/outgoing connections")
+	natService := flag.Bool("nat_service", false, "serve AutoNAT dial-backs and relay connections for nodes behind NATs")
+	statusAddr := flag.String("status_addr", "", "serve bootnode status, known peers and a readiness probe over HTTP on this address, e.g. :9877 (disabled if empty)")
+	networkType := flag.String("network_type", "testnet", "type of the network the bootnode serves: mainnet, testnet or devnet")
+	genesisFile := flag.String("genesis_file", "", "network genesis file of a custom network the bootnode serves")
 
 	flag.Parse()
 
@@ -67,26 +76,64 @@ func main() {
 		panic(err)
 	}
 
+	// Nodes speak the DHT protocol of their network only, so a bootnode
+	// serves a single network.
+	network, err := config.ParseNetworkType(*networkType)
+	if err != nil {
+		panic(err)
+	}
+	config.Network = network
+	core.ShardingSchedule = shardingconfig.ScheduleForNetwork(network)
+	if *genesisFile != "" {
+		if err := node.UseNetworkGenesisFile(*genesisFile); err != nil {
+			panic(err)
+		}
+	}
+	scope, err := node.NetworkScope(network)
+	if err != nil {
+		panic(err)
+	}
+
 	var selfPeer = p2p.Peer{IP: *ip, Port: *port}
 
-	host, err := p2pimpl.NewHost(&selfPeer, privKey)
+	hostConfig := p2p.DefaultHostConfig
+	hostConfig.Network = scope
+	hostConfig.NAT.Service = *natService
+	host, err := p2pimpl.NewHostWithConfig(&selfPeer, privKey, hostConfig)
 	if err != nil {
 		panic(err)
 	}
 
-	log.Info("bootnode", "BN_MA", fmt.Sprintf("/ip4/%s/tcp/%s/p2p/%s", *ip, *port, host.GetID().Pretty()))
+	log.Info("bootnode", "BN_MA", fmt.Sprintf("/ip4/%s/tcp/%s/p2p/%s", *ip, *port, host.GetID().Pretty()), "network", scope)
 
 	if *logConn {
 		host.GetP2PHost().Network().Notify(utils.NewConnLogger(utils.GetLogInstance()))
 	}
 
+	var statusSrv *statusServer
+	if *statusAddr != "" {
+		statusSrv = newStatusServer(host.GetP2PHost())
+		statusSrv.start(*statusAddr)
+	}
+
 	dataStore := dsync.MutexWrap(ds.NewMapDatastore())
-	dht := kaddht.NewDHT(context.Background(), host.GetP2PHost(), dataStore)
+	dht, err := kaddht.New(context.Background(), host.GetP2PHost(),
+		dhtopts.Datastore(dataStore),
+		dhtopts.Protocols(scope.ProtocolID(kaddht.ProtocolDHT)))
+	if err != nil {
+		panic(err)
+	}
 
+	if statusSrv != nil {
+		statusSrv.setDHT(dht)
+	}
 	if err := dht.Bootstrap(context.Background()); err != nil {
 		log.Error("failed to bootstrap DHT")
 		panic(err)
 	}
+	if statusSrv != nil {
+		statusSrv.setBootstrapped()
+	}
 
 	select {}
 }
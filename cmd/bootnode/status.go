@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/harmony-one/harmony/internal/ctxerror"
+	"github.com/harmony-one/harmony/internal/utils"
+
+	libp2p_host "github.com/libp2p/go-libp2p-host"
+	kaddht "github.com/libp2p/go-libp2p-kad-dht"
+	libp2p_net "github.com/libp2p/go-libp2p-net"
+)
+
+// status is the health report of the bootnode, served at /status.
+type status struct {
+	ID               string   `json:"id"`
+	Addrs            []string `json:"addrs"`
+	Uptime           string   `json:"uptime"`
+	UptimeSeconds    int64    `json:"uptimeSeconds"`
+	ConnectedPeers   int      `json:"connectedPeers"`
+	RoutingTableSize int      `json:"routingTableSize"`
+	KnownPeers       int      `json:"knownPeers"`
+	Ready            bool     `json:"ready"`
+}
+
+// peerStatus is a peer known to the bootnode, served at /peers.
+type peerStatus struct {
+	ID        string   `json:"id"`
+	Addrs     []string `json:"addrs"`
+	Protocols []string `json:"protocols"`
+	Connected bool     `json:"connected"`
+}
+
+// statusServer serves the health of the bootnode and the peers it knows over
+// HTTP.
+type statusServer struct {
+	host             libp2p_host.Host
+	started          time.Time
+	routingTableSize atomic.Value // func() int, set once the DHT is up
+	bootstrapped     int32        // atomic, set once the DHT is bootstrapped
+}
+
+func newStatusServer(host libp2p_host.Host) *statusServer {
+	return &statusServer{host: host, started: time.Now()}
+}
+
+// setDHT records the DHT of the bootnode, whose routing table size is
+// reported in /status.
+func (s *statusServer) setDHT(dht *kaddht.IpfsDHT) {
+	s.setRoutingTableSize(func() int { return dht.RoutingTable().Size() })
+}
+
+func (s *statusServer) setRoutingTableSize(size func() int) {
+	s.routingTableSize.Store(size)
+}
+
+// tableSize returns the size of the DHT routing table, 0 before the DHT is up.
+func (s *statusServer) tableSize() int {
+	size, ok := s.routingTableSize.Load().(func() int)
+	if !ok {
+		return 0
+	}
+	return size()
+}
+
+// setBootstrapped records that the DHT of the bootnode is bootstrapped.
+func (s *statusServer) setBootstrapped() {
+	atomic.StoreInt32(&s.bootstrapped, 1)
+}
+
+// isReady reports whether the bootnode can serve peer lookups: its host is
+// listening and its DHT is bootstrapped. The routing table may still be
+// empty, as it is on the first bootnode of a network until nodes join.
+func (s *statusServer) isReady() bool {
+	return atomic.LoadInt32(&s.bootstrapped) == 1 && len(s.host.Network().ListenAddresses()) > 0
+}
+
+// handler returns the handler of /status, /peers, /healthz and /ready.
+func (s *statusServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", s.serveStatus)
+	mux.HandleFunc("/peers", s.servePeers)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok\n"))
+	})
+	mux.HandleFunc("/ready", s.serveReady)
+	return mux
+}
+
+// start serves /status, /peers, /healthz and /ready on addr.
+func (s *statusServer) start(addr string) *http.Server {
+	server := &http.Server{Addr: addr, Handler: s.handler()}
+	utils.GetLogInstance().Info("serving bootnode status", "addr", addr)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			ctxerror.Warn(utils.GetLogger(), err, "server.ListenAndServe()")
+		}
+	}()
+	return server
+}
+
+// serveReady answers 200 once the bootnode is ready and 503 before, for
+// readiness probes of container orchestrators.
+func (s *statusServer) serveReady(w http.ResponseWriter, r *http.Request) {
+	if !s.isReady() {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+		return
+	}
+	w.Write([]byte("ok\n"))
+}
+
+func (s *statusServer) serveStatus(w http.ResponseWriter, r *http.Request) {
+	uptime := time.Since(s.started)
+	st := status{
+		ID:               s.host.ID().Pretty(),
+		Addrs:            []string{},
+		Uptime:           uptime.Round(time.Second).String(),
+		UptimeSeconds:    int64(uptime / time.Second),
+		ConnectedPeers:   len(s.host.Network().Peers()),
+		RoutingTableSize: s.tableSize(),
+		KnownPeers:       s.host.Peerstore().Peers().Len(),
+		Ready:            s.isReady(),
+	}
+	for _, addr := range s.host.Addrs() {
+		st.Addrs = append(st.Addrs, addr.String())
+	}
+	writeJSON(w, st)
+}
+
+func (s *statusServer) servePeers(w http.ResponseWriter, r *http.Request) {
+	peerstore := s.host.Peerstore()
+	peers := []peerStatus{}
+	for _, id := range peerstore.Peers() {
+		if id == s.host.ID() {
+			continue
+		}
+		p := peerStatus{
+			ID:        id.Pretty(),
+			Addrs:     []string{},
+			Protocols: []string{},
+			Connected: s.host.Network().Connectedness(id) == libp2p_net.Connected,
+		}
+		for _, addr := range peerstore.Addrs(id) {
+			p.Addrs = append(p.Addrs, addr.String())
+		}
+		if protocols, err := peerstore.GetProtocols(id); err == nil {
+			sort.Strings(protocols)
+			p.Protocols = append(p.Protocols, protocols...)
+		}
+		peers = append(peers, p)
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].ID < peers[j].ID })
+	writeJSON(w, peers)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		ctxerror.Warn(utils.GetLogger(), err, "cannot JSON-encode response")
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+)
+
+func TestStatusServer(t *testing.T) {
+	mn := mocknet.New(context.Background())
+	bootnode, err := mn.GenPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	peer, err := mn.GenPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mn.LinkAll(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mn.ConnectAllButSelf(); err != nil {
+		t.Fatal(err)
+	}
+	s := newStatusServer(bootnode)
+	handler := s.handler()
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	if code := get("/ready").Code; code != http.StatusServiceUnavailable {
+		t.Errorf("ready without a DHT: got %d", code)
+	}
+	tableSize := 0
+	s.setRoutingTableSize(func() int { return tableSize })
+	if code := get("/ready").Code; code != http.StatusServiceUnavailable {
+		t.Errorf("ready before bootstrapping the DHT: got %d", code)
+	}
+	// The first bootnode of a network is ready with an empty routing table.
+	s.setBootstrapped()
+	if code := get("/ready").Code; code != http.StatusOK {
+		t.Errorf("not ready with a bootstrapped DHT: got %d", code)
+	}
+	tableSize = 1
+
+	var st status
+	if err := json.Unmarshal(get("/status").Body.Bytes(), &st); err != nil {
+		t.Fatalf("cannot decode status: %v", err)
+	}
+	if st.ID != bootnode.ID().Pretty() || st.ConnectedPeers != 1 || st.RoutingTableSize != 1 || !st.Ready {
+		t.Errorf("unexpected status %+v", st)
+	}
+
+	var peers []peerStatus
+	if err := json.Unmarshal(get("/peers").Body.Bytes(), &peers); err != nil {
+		t.Fatalf("cannot decode peers: %v", err)
+	}
+	if len(peers) != 1 || peers[0].ID != peer.ID().Pretty() || !peers[0].Connected || len(peers[0].Addrs) == 0 {
+		t.Errorf("unexpected peers %+v", peers)
+	}
+}
@@ -23,10 +23,12 @@ import (
 
 	"github.com/harmony-one/harmony/api/client"
 	proto_node "github.com/harmony-one/harmony/api/proto/node"
+	"github.com/harmony-one/harmony/common/config"
 	"github.com/harmony-one/harmony/common/denominations"
 	"github.com/harmony-one/harmony/core/types"
 	"github.com/harmony-one/harmony/crypto/bls"
 	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	shardingconfig "github.com/harmony-one/harmony/internal/configs/sharding"
 	"github.com/harmony-one/harmony/internal/genesis"
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/node"
@@ -74,6 +76,10 @@ var (
 	keyFile = flag.String("key", "./.txgenkey", "the private key file of the txgen")
 	// logging verbosity
 	verbosity = flag.Int("verbosity", 5, "Logging verbosity: 0=silent, 1=error, 2=warn, 3=info, 4=debug, 5=detail (default: 5)")
+	// networkType is the type of the network the transactions are sent to.
+	networkType = flag.String("network_type", "testnet", "type of the network: mainnet, testnet or devnet")
+	// genesisFile is the network genesis of a custom network, as given to "harmony init".
+	genesisFile = flag.String("genesis_file", "", "network genesis file of a custom network")
 )
 
 func setUpTXGen() *node.Node {
@@ -92,7 +98,11 @@ func setUpTXGen() *node.Node {
 	gsif, err := consensus.NewGenesisStakeInfoFinder()
 	// Nodes containing blockchain data to mirror the shards' data in the network
 
-	myhost, err := p2pimpl.NewHost(&selfPeer, nodePriKey)
+	hostConfig := p2p.DefaultHostConfig
+	if hostConfig.Network, err = node.NetworkScope(config.Network); err != nil {
+		panic(err)
+	}
+	myhost, err := p2pimpl.NewHostWithConfig(&selfPeer, nodePriKey, hostConfig)
 	if err != nil {
 		panic("unable to new host in txgen")
 	}
@@ -108,7 +118,7 @@ func setUpTXGen() *node.Node {
 	consensusObj.ChainReader = txGen.Blockchain()
 	consensusObj.PublicKeys = nil
 	startIdx := 0
-	endIdx := startIdx + core.GenesisShardSize
+	endIdx := startIdx + core.ShardingSchedule.InstanceForEpoch(big.NewInt(core.GenesisEpoch)).NumNodesPerShard()
 	for _, acct := range genesis.GenesisAccounts[startIdx:endIdx] {
 		pub := &bls2.PublicKey{}
 		if err := pub.DeserializeHexStr(acct.BlsPublicKey); err != nil {
@@ -135,6 +145,19 @@ func main() {
 	if *versionFlag {
 		printVersion(os.Args[0])
 	}
+	network, err := config.ParseNetworkType(*networkType)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error :%v \n", err)
+		os.Exit(1)
+	}
+	config.Network = network
+	core.ShardingSchedule = shardingconfig.ScheduleForNetwork(config.Network)
+	if *genesisFile != "" {
+		if err := node.UseNetworkGenesisFile(*genesisFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error :%v \n", err)
+			os.Exit(1)
+		}
+	}
 	// Add GOMAXPROCS to achieve max performance.
 	runtime.GOMAXPROCS(1024)
 	// Logging setup
@@ -275,18 +298,19 @@ func GenerateSimulatedTransactionsAccount(shardID uint32, node *node.Node, setti
 	txs := make([]*types.Transaction, TxnsToGenerate)
 	rounds := (TxnsToGenerate / 100)
 	remainder := TxnsToGenerate % 100
+	signer := types.NewHarmonySigner(node.Blockchain().HarmonyConfig().ChainID, shardID)
 	for i := 0; i < 100; i++ {
 		baseNonce := node.Worker.GetCurrentState().GetNonce(crypto.PubkeyToAddress(node.TestBankKeys[i].PublicKey))
 		for j := 0; j < rounds; j++ {
 			randomUserAddress := crypto.PubkeyToAddress(node.TestBankKeys[rand.Intn(100)].PublicKey)
 			randAmount := rand.Float32()
-			tx, _ := types.SignTx(types.NewTransaction(baseNonce+uint64(j), randomUserAddress, shardID, big.NewInt(int64(denominations.One*randAmount)), params.TxGas, nil, nil), types.HomesteadSigner{}, node.TestBankKeys[i])
+			tx, _ := types.SignTx(types.NewTransaction(baseNonce+uint64(j), randomUserAddress, shardID, big.NewInt(int64(denominations.One*randAmount)), params.TxGas, nil, nil), signer, node.TestBankKeys[i])
 			txs[100*j+i] = tx
 		}
 		if i < remainder {
 			randomUserAddress := crypto.PubkeyToAddress(node.TestBankKeys[rand.Intn(100)].PublicKey)
 			randAmount := rand.Float32()
-			tx, _ := types.SignTx(types.NewTransaction(baseNonce+uint64(rounds), randomUserAddress, shardID, big.NewInt(int64(denominations.One*randAmount)), params.TxGas, nil, nil), types.HomesteadSigner{}, node.TestBankKeys[i])
+			tx, _ := types.SignTx(types.NewTransaction(baseNonce+uint64(rounds), randomUserAddress, shardID, big.NewInt(int64(denominations.One*randAmount)), params.TxGas, nil, nil), signer, node.TestBankKeys[i])
 			txs[100*rounds+i] = tx
 		}
 	}
@@ -31,6 +31,7 @@ rpc = 127.0.0.1:14555
 rpc = 127.0.0.1:14556
 
 [devnet]
+network = devnet
 bootnode = /ip4/100.26.90.187/tcp/9871/p2p/Qmdfjtk6hPoyrH1zVD9PEH4zfWLo38dP2mDvvKXfh3tnEv
 bootnode = /ip4/54.213.43.194/tcp/9871/p2p/QmRVbTpEYup8dSaURZfF6ByrMTSKa4UyUzJhSjahFzRqNj
 shards = 4
@@ -19,13 +19,16 @@ import (
 	"github.com/harmony-one/harmony/api/client"
 	clientService "github.com/harmony-one/harmony/api/client/service"
 	proto_node "github.com/harmony-one/harmony/api/proto/node"
+	"github.com/harmony-one/harmony/common/config"
 	"github.com/harmony-one/harmony/common/denominations"
 	"github.com/harmony-one/harmony/core"
 	"github.com/harmony-one/harmony/core/types"
 	"github.com/harmony-one/harmony/internal/blsgen"
 	common2 "github.com/harmony-one/harmony/internal/common"
 	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	shardingconfig "github.com/harmony-one/harmony/internal/configs/sharding"
 	"github.com/harmony-one/harmony/internal/ctxerror"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
 	"github.com/harmony-one/harmony/internal/shardchain"
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/node"
@@ -257,7 +260,22 @@ func createWalletNode() *node.Node {
 	// we need to understand the impact to bootnode DHT with this dummy host ip added
 	self := p2p.Peer{IP: "127.0.0.1", Port: "6999"}
 	priKey, _, _ := utils.GenKeyP2P("127.0.0.1", "6999")
-	host, err := p2pimpl.NewHost(&self, priKey)
+	network, err := config.ParseNetworkType(walletProfile.Network)
+	if err != nil {
+		panic(err)
+	}
+	config.Network = network
+	core.ShardingSchedule = shardingconfig.ScheduleForNetwork(config.Network)
+	if walletProfile.Genesis != "" {
+		if err := node.UseNetworkGenesisFile(walletProfile.Genesis); err != nil {
+			panic(err)
+		}
+	}
+	hostConfig := p2p.DefaultHostConfig
+	if hostConfig.Network, err = node.NetworkScope(config.Network); err != nil {
+		panic(err)
+	}
+	host, err := p2pimpl.NewHostWithConfig(&self, priKey, hostConfig)
 	if err != nil {
 		panic(err)
 	}
@@ -582,7 +600,7 @@ func processTransferCommand() {
 
 	fmt.Printf("Unlock account succeeded! '%v'\n", senderPass)
 
-	tx, err = ks.SignTx(account, tx, nil)
+	tx, err = ks.SignTx(account, tx, hmyparams.ChainConfigByNetwork(config.Network).ChainID)
 	if err != nil {
 		fmt.Printf("SignTx Error: %v\n", err)
 		return
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+
+	"github.com/harmony-one/harmony/api/service/explorer"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/internal/shardchain"
+)
+
+var (
+	// db bad-blocks subcommand
+	badBlocksCommand = flag.NewFlagSet("bad-blocks", flag.ExitOnError)
+	badBlocksDBDir   = badBlocksCommand.String("db_dir", "", "blockchain database directory")
+	badBlocksShardID = badBlocksCommand.Int("shard_id", 0, "the shard whose database to read")
+
+	// db inspect subcommand
+	inspectCommand = flag.NewFlagSet("inspect", flag.ExitOnError)
+	inspectDBDir   = inspectCommand.String("db_dir", "", "blockchain database directory")
+	inspectShardID = inspectCommand.Int("shard_id", 0, "the shard whose database to inspect")
+
+	// db rewind subcommand
+	rewindCommand      = flag.NewFlagSet("rewind", flag.ExitOnError)
+	rewindDBDir        = rewindCommand.String("db_dir", "", "blockchain database directory")
+	rewindShardID      = rewindCommand.Int("shard_id", 0, "the shard whose chain to rewind")
+	rewindNetworkType  = rewindCommand.String("network_type", "testnet", "type of the network: mainnet, testnet or devnet")
+	rewindTo           = rewindCommand.Int64("to", -1, "the block number to rewind the chain to")
+	rewindExplorerIP   = rewindCommand.String("explorer_ip", "", "ip of the node whose explorer storage to rewind as well (default: none)")
+	rewindExplorerPort = rewindCommand.String("explorer_port", "", "port of the node whose explorer storage to rewind as well")
+)
+
+// runDBCommand runs the db subcommand named by args[0].
+func runDBCommand(args []string) {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: harmony db <command> [flags]")
+		fmt.Fprintln(os.Stderr, "Commands:")
+		fmt.Fprintln(os.Stderr, "  bad-blocks  list the blocks that failed validation")
+		fmt.Fprintln(os.Stderr, "  inspect     report database usage and check the canonical chain")
+		fmt.Fprintln(os.Stderr, "  rewind      rewind the chain to an earlier block")
+		os.Exit(1)
+	}
+	switch args[0] {
+	case "bad-blocks":
+		_ = badBlocksCommand.Parse(args[1:])
+		printBadBlocks()
+	case "inspect":
+		_ = inspectCommand.Parse(args[1:])
+		inspectDB()
+	case "rewind":
+		_ = rewindCommand.Parse(args[1:])
+		rewindChain()
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown db command %q\n", args[0])
+		os.Exit(1)
+	}
+}
+
+// openShardChainDB opens the chain database of the given shard under dbDir.
+func openShardChainDB(dbDir string, shardID int) ethdb.Database {
+	if shardID < 0 {
+		fmt.Fprintf(os.Stderr, "Invalid shard ID %d\n", shardID)
+		os.Exit(1)
+	}
+	factory := &shardchain.LDBFactory{RootDir: dbDir}
+	db, err := factory.NewChainDB(uint32(shardID))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot open database of shard %d: %v\n", shardID, err)
+		os.Exit(1)
+	}
+	return db
+}
+
+// printBadBlocks lists the bad blocks stored in a shard chain database,
+// newest first.
+func printBadBlocks() {
+	db := openShardChainDB(*badBlocksDBDir, *badBlocksShardID)
+	defer db.Close()
+	badBlocks := rawdb.ReadAllBadBlocks(db)
+	if len(badBlocks) == 0 {
+		fmt.Println("No bad blocks")
+		return
+	}
+	for _, badBlock := range badBlocks {
+		source := badBlock.Source
+		if source == "" {
+			source = "unknown"
+		}
+		fmt.Printf("Block %d %s\n", badBlock.Block.NumberU64(), badBlock.Block.Hash().Hex())
+		fmt.Printf("  Time:   %s\n", time.Unix(int64(badBlock.Time), 0).UTC().Format(time.RFC3339))
+		fmt.Printf("  Source: %s\n", source)
+		fmt.Printf("  Error:  %s\n", badBlock.Error)
+	}
+}
+
+// inspectDB reports the number and size of the entries of a shard chain
+// database per category, and checks that its canonical chain is continuous.
+func inspectDB() {
+	db := openShardChainDB(*inspectDBDir, *inspectShardID)
+	defer db.Close()
+	iteratee, ok := db.(rawdb.DatabaseIteratee)
+	if !ok {
+		fmt.Fprintln(os.Stderr, "Database does not support iteration")
+		os.Exit(1)
+	}
+	stats, err := rawdb.InspectDatabase(iteratee)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot inspect database: %v\n", err)
+		db.Close()
+		os.Exit(1)
+	}
+	var totalCount uint64
+	var totalSize common.StorageSize
+	fmt.Printf("%-22s %12s %12s\n", "Category", "Count", "Size")
+	for _, stat := range stats {
+		fmt.Printf("%-22s %12d %12s\n", stat.Category, stat.Count, common.StorageSize(stat.Size))
+		totalCount += stat.Count
+		totalSize += common.StorageSize(stat.Size)
+	}
+	fmt.Printf("%-22s %12d %12s\n", "Total", totalCount, totalSize)
+
+	checked, err := rawdb.CheckCanonicalChain(db)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Canonical chain is broken after %d blocks: %v\n", checked, err)
+		db.Close()
+		os.Exit(1)
+	}
+	fmt.Printf("Canonical chain is continuous from genesis to block %d\n", checked-1)
+}
+
+// errNotInitialized is returned when an offline db command opens a shard chain
+// database that was never initialized.
+var errNotInitialized = errors.New("chain database is not initialized")
+
+// noDBInit refuses to initialize chain databases, so that offline db commands
+// never create a chain as a side effect.
+type noDBInit struct{}
+
+func (noDBInit) InitChainDB(db ethdb.Database, shardID uint32) error {
+	return errNotInitialized
+}
+
+// rewindChain rewinds a shard chain, and optionally the explorer storage, to
+// the requested block number. The node must not be running.
+func rewindChain() {
+	if *rewindTo < 0 {
+		fmt.Fprintln(os.Stderr, "Missing --to block number")
+		rewindCommand.PrintDefaults()
+		os.Exit(1)
+	}
+	if *rewindShardID < 0 {
+		fmt.Fprintf(os.Stderr, "Invalid shard ID %d\n", *rewindShardID)
+		os.Exit(1)
+	}
+	setUpNetworkType(*rewindNetworkType)
+	setUpNetworkGenesis(*rewindDBDir)
+
+	// Rewinding does not verify any block, so no consensus engine is needed.
+	collection := shardchain.NewCollection(
+		&shardchain.LDBFactory{RootDir: *rewindDBDir}, noDBInit{}, nil)
+	defer collection.Close()
+	bc, err := collection.ShardChain(uint32(*rewindShardID))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot open chain of shard %d: %v\n", *rewindShardID, err)
+		os.Exit(1)
+	}
+	from := bc.CurrentBlock().NumberU64()
+	if err := bc.RewindTo(uint64(*rewindTo)); err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot rewind chain of shard %d: %v\n", *rewindShardID, err)
+		collection.Close()
+		os.Exit(1)
+	}
+	fmt.Printf("Rewound chain of shard %d from block %d to block %d\n",
+		*rewindShardID, from, bc.CurrentBlock().NumberU64())
+
+	if *rewindExplorerIP != "" {
+		storage := explorer.GetStorageInstance(*rewindExplorerIP, *rewindExplorerPort, false)
+		if err := storage.Rewind(uint64(*rewindTo), bc.HarmonyConfig()); err != nil {
+			fmt.Fprintf(os.Stderr, "Cannot rewind explorer storage: %v\n", err)
+			collection.Close()
+			os.Exit(1)
+		}
+		fmt.Println("Rewound explorer storage")
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/harmony-one/harmony/common/config"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/internal/genesis"
+	"github.com/harmony-one/harmony/internal/shardchain"
+	"github.com/harmony-one/harmony/node"
+)
+
+var (
+	// init subcommand
+	initCommand     = flag.NewFlagSet("init", flag.ExitOnError)
+	initGenesisFile = initCommand.String("genesis", "", "the network genesis JSON file to initialize the databases from")
+	initDBDir       = initCommand.String("db_dir", "", "blockchain database directory")
+	initOnlyShardID = initCommand.Int("shard_id", -1, "initialize only the database of this shard and the beacon chain (default: all shards)")
+
+	// dumpgenesis subcommand
+	dumpGenesisCommand     = flag.NewFlagSet("dumpgenesis", flag.ExitOnError)
+	dumpGenesisNetworkType = dumpGenesisCommand.String("network_type", "testnet", "type of the network: mainnet, testnet or devnet")
+	dumpGenesisOut         = dumpGenesisCommand.String("out", "", "the file to write the network genesis JSON to (default: stdout)")
+)
+
+// runSubcommand runs the subcommand named by the first command line argument,
+// if any, and returns whether it did.
+func runSubcommand() bool {
+	if len(os.Args) < 2 {
+		return false
+	}
+	switch os.Args[1] {
+	case "init":
+		_ = initCommand.Parse(os.Args[2:])
+		initNetworkGenesis()
+	case "dumpgenesis":
+		_ = dumpGenesisCommand.Parse(os.Args[2:])
+		dumpNetworkGenesis()
+	case "db":
+		runDBCommand(os.Args[2:])
+	case "snapshot":
+		runSnapshotCommand(os.Args[2:])
+	default:
+		return false
+	}
+	return true
+}
+
+// initNetworkGenesis writes the genesis of a custom network into the shard
+// chain databases.
+func initNetworkGenesis() {
+	if *initGenesisFile == "" {
+		fmt.Fprintln(os.Stderr, "Missing --genesis file")
+		initCommand.PrintDefaults()
+		os.Exit(1)
+	}
+	ng, err := core.ReadNetworkGenesis(*initGenesisFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid genesis: %v\n", err)
+		os.Exit(1)
+	}
+	factory := &shardchain.LDBFactory{RootDir: *initDBDir}
+	for shardID := uint32(0); shardID < ng.Sharding.NumShards; shardID++ {
+		if *initOnlyShardID >= 0 && shardID != 0 && shardID != uint32(*initOnlyShardID) {
+			// The beacon chain is always needed.
+			continue
+		}
+		db, err := factory.NewChainDB(shardID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Cannot open database of shard %d: %v\n", shardID, err)
+			os.Exit(1)
+		}
+		if stored := rawdb.ReadCanonicalHash(db, 0); stored != (common.Hash{}) {
+			db.Close()
+			fmt.Fprintf(os.Stderr, "Database of shard %d is already initialized with genesis %s\n",
+				shardID, stored.Hex())
+			os.Exit(1)
+		}
+		block, err := ng.Commit(db, shardID)
+		if err == nil {
+			err = core.WriteStoredNetworkGenesis(db, block.Hash(), ng)
+		}
+		db.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Cannot write genesis of shard %d: %v\n", shardID, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Shard %d: genesis %s written to %s\n",
+			shardID, block.Hash().Hex(), factory.ChainDBDir(shardID))
+	}
+}
+
+// dumpNetworkGenesis exports the genesis of a built-in network as JSON.
+func dumpNetworkGenesis() {
+	setUpNetworkType(*dumpGenesisNetworkType)
+	ng, err := node.BuiltinNetworkGenesis(config.Network)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot build network genesis: %v\n", err)
+		os.Exit(1)
+	}
+	data, err := json.MarshalIndent(ng, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot encode network genesis: %v\n", err)
+		os.Exit(1)
+	}
+	data = append(data, '\n')
+	if *dumpGenesisOut == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := ioutil.WriteFile(*dumpGenesisOut, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot write %s: %v\n", *dumpGenesisOut, err)
+		os.Exit(1)
+	}
+}
+
+// setUpNetworkGenesis switches the node over to the custom network genesis
+// stored in the beacon chain database by "harmony init", if there is one.
+func setUpNetworkGenesis(dbDir string) {
+	factory := &shardchain.LDBFactory{RootDir: dbDir}
+	if _, err := os.Stat(factory.ChainDBDir(0)); err != nil {
+		// Fresh node; use the built-in genesis.
+		return
+	}
+	db, err := factory.NewChainDB(0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot open beacon chain database: %v\n", err)
+		os.Exit(1)
+	}
+	ng, err := core.ReadStoredNetworkGenesis(db)
+	db.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot read network genesis: %v\n", err)
+		os.Exit(1)
+	}
+	if ng == nil {
+		return
+	}
+	schedule, err := ng.Schedule()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid network genesis: %v\n", err)
+		os.Exit(1)
+	}
+	core.CustomNetworkGenesis = ng
+	core.ShardingSchedule = schedule
+}
+
+// findGenesisAccount looks up the genesis account of the given address, in the
+// custom network genesis if there is one.
+func findGenesisAccount(address string) (int, *genesis.DeployAccount) {
+	if core.CustomNetworkGenesis != nil {
+		return core.CustomNetworkGenesis.FindAccount(address)
+	}
+	return genesis.FindAccount(address)
+}
+
+// isBlsPublicKeyWhiteListed returns whether the given BLS public key belongs
+// to a genesis node, in the custom network genesis if there is one.
+func isBlsPublicKeyWhiteListed(blsPublicKey string) bool {
+	if core.CustomNetworkGenesis != nil {
+		return core.CustomNetworkGenesis.IsBlsPublicKeyWhiteListed(blsPublicKey)
+	}
+	return genesis.IsBlsPublicKeyWhiteListed(blsPublicKey)
+}
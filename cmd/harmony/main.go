@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"flag"
 	"fmt"
+	"math/big"
 	"math/rand"
 	"os"
 	"path"
@@ -15,11 +16,13 @@ import (
 
 	"github.com/harmony-one/harmony/accounts"
 	"github.com/harmony-one/harmony/accounts/keystore"
+	"github.com/harmony-one/harmony/common/config"
 	"github.com/harmony-one/harmony/consensus"
 	"github.com/harmony-one/harmony/core"
 	"github.com/harmony-one/harmony/internal/blsgen"
 	"github.com/harmony-one/harmony/internal/common"
 	nodeconfig "github.com/harmony-one/harmony/internal/configs/node"
+	shardingconfig "github.com/harmony-one/harmony/internal/configs/sharding"
 	"github.com/harmony-one/harmony/internal/ctxerror"
 	"github.com/harmony-one/harmony/internal/genesis"
 	hmykey "github.com/harmony-one/harmony/internal/keystore"
@@ -95,6 +98,12 @@ var (
 	isGenesis = flag.Bool("is_genesis", true, "true means this node is a genesis node")
 	// isArchival indicates this node is an archival node that will save and archive current blockchain
 	isArchival = flag.Bool("is_archival", false, "true means this node is a archival node")
+	// txHistoryIndex indicates this node maintains the address transaction history index
+	txHistoryIndex = flag.Bool("tx_history_index", false, "maintain the address transaction history index for hmy_getTransactionsHistory")
+	// adminRPC exposes the admin RPC namespace, e.g. admin_dropTransaction
+	adminRPC = flag.Bool("admin_rpc", false, "serve the admin RPC namespace on the HTTP RPC endpoint (never enable on a publicly reachable RPC port)")
+	// debugRPC exposes the debug RPC namespace, e.g. debug_getBadBlocks
+	debugRPC = flag.Bool("debug_rpc", false, "serve the debug RPC namespace on the HTTP RPC endpoint (never enable on a publicly reachable RPC port)")
 	// delayCommit is the commit-delay timer, used by Harmony nodes
 	delayCommit = flag.String("delay_commit", "0ms", "how long to delay sending commit messages in consensus, ex: 500ms, 1s")
 	//isNewNode indicates this node is a new node
@@ -119,6 +128,7 @@ var (
 
 	stakingAccounts = flag.String("accounts", "", "account addresses of the node")
 
+	p2pNetwork     p2p.NetworkScope // set up by initSetup
 	ks             *keystore.KeyStore
 	myAccount      accounts.Account
 	genesisAccount *genesis.DeployAccount
@@ -133,13 +143,84 @@ var (
 	// Disable view change.
 	disableViewChange = flag.Bool("disable_view_change", false,
 		"Do not propose view change (testing only)")
+
+	// networkType is the type of network the node joins; it selects the
+	// chain config and the sharding schedule.
+	networkType = flag.String("network_type", "testnet", "type of the network: mainnet, testnet or devnet")
+
+	// Group messaging; see p2p.PubSubConfig.
+	pubSubRouter       = flag.String("pubsub_router", p2p.DefaultHostConfig.PubSub.Router, "pubsub router of group messages: gossipsub or floodsub")
+	gossipSubD         = flag.Int("gossipsub_d", p2p.DefaultHostConfig.PubSub.D, "number of peers of the gossipsub mesh of a group")
+	gossipSubDlo       = flag.Int("gossipsub_dlo", p2p.DefaultHostConfig.PubSub.Dlo, "minimum number of peers of the gossipsub mesh of a group")
+	gossipSubDhi       = flag.Int("gossipsub_dhi", p2p.DefaultHostConfig.PubSub.Dhi, "maximum number of peers of the gossipsub mesh of a group")
+	gossipSubHeartbeat = flag.Duration("gossipsub_heartbeat", p2p.DefaultHostConfig.PubSub.HeartbeatInterval, "interval of the gossipsub heartbeat")
+
+	// persistPeers keeps the known peers next to the shard databases, to
+	// redial them on startup alongside the bootnodes.
+	persistPeers = flag.Bool("persist_peers", true, "save known peers in db_dir and redial them on startup")
+
+	// compressMessages sends block, sync and shard state messages compressed.
+	compressMessages = flag.Bool("compress_messages", false, "snappy-compress block, sync and shard state messages; nodes without compression support drop them")
+
+	// NAT traversal, for nodes behind a home gateway; see relayAddrs for relays.
+	natPortMap = flag.Bool("nat_portmap", false, "ask the gateway to forward the listen port with UPnP or NAT-PMP")
+	autoNAT    = flag.Bool("autonat", false, "ask peers to dial back to find out whether this node is reachable and at which public address")
+	natService = flag.Bool("nat_service", false, "serve AutoNAT dial-backs and relay connections for peers behind NATs; only for publicly reachable nodes")
+
+	// relayAddrs are the circuit relays advertised when this node is unreachable.
+	relayAddrs utils.AddrList
 )
 
+// hostConfigFromFlags returns the p2p host configuration given on the command
+// line, or an error if the gossipsub flags are inconsistent.
+func hostConfigFromFlags() (p2p.HostConfig, error) {
+	hostConfig := p2p.DefaultHostConfig
+	hostConfig.Network = p2pNetwork
+	hostConfig.PubSub.Router = *pubSubRouter
+	hostConfig.PubSub.D = *gossipSubD
+	hostConfig.PubSub.Dlo = *gossipSubDlo
+	hostConfig.PubSub.Dhi = *gossipSubDhi
+	hostConfig.PubSub.HeartbeatInterval = *gossipSubHeartbeat
+	if *persistPeers {
+		// Like the shard databases, the peers file goes to the working
+		// directory if db_dir is empty.
+		hostConfig.PeersFile = path.Join(*dbDir, "harmony_peers.json")
+	}
+	hostConfig.NAT.PortMap = *natPortMap
+	hostConfig.NAT.AutoNAT = *autoNAT
+	hostConfig.NAT.Relays = relayAddrs
+	hostConfig.NAT.Service = *natService
+	if err := hostConfig.PubSub.Validate(); err != nil {
+		return p2p.HostConfig{}, err
+	}
+	return hostConfig, nil
+}
+
+func setUpNetworkType(networkType string) {
+	network, err := config.ParseNetworkType(networkType)
+	if err != nil {
+		fmt.Printf("Unknown network type: %v\n", networkType)
+		os.Exit(1)
+	}
+	config.Network = network
+	core.ShardingSchedule = shardingconfig.ScheduleForNetwork(config.Network)
+}
+
 func initSetup() {
 	if *versionFlag {
 		printVersion(os.Args[0])
 	}
 
+	// Select the network before anything consults its parameters.
+	setUpNetworkType(*networkType)
+	setUpNetworkGenesis(*dbDir)
+	scope, err := node.NetworkScope(config.Network)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot compute the network genesis hash: %v\n", err)
+		os.Exit(1)
+	}
+	p2pNetwork = scope
+
 	// Set port and ip to global config.
 	nodeconfig.GetDefaultConfig().Port = *port
 	nodeconfig.GetDefaultConfig().IP = *ip
@@ -173,7 +254,7 @@ func initSetup() {
 	allAccounts := ks.Accounts()
 
 	// TODO: lc try to enable multiple staking accounts per node
-	accountIndex, genesisAccount = genesis.FindAccount(*stakingAccounts)
+	accountIndex, genesisAccount = findGenesisAccount(*stakingAccounts)
 
 	if genesisAccount == nil {
 		fmt.Printf("Can't find the account address: %v\n", *stakingAccounts)
@@ -194,7 +275,8 @@ func initSetup() {
 		os.Exit(101)
 	}
 
-	genesisAccount.ShardID = uint32(accountIndex % core.GenesisShardNum)
+	genesisShardingConfig := core.ShardingSchedule.InstanceForEpoch(big.NewInt(core.GenesisEpoch))
+	genesisAccount.ShardID = uint32(accountIndex) % genesisShardingConfig.NumShards()
 
 	fmt.Printf("My Account: %s\n", common.MustAddressToBech32(myAccount.Address))
 	fmt.Printf("Key URL: %s\n", myAccount.URL)
@@ -238,7 +320,7 @@ func setUpConsensusKey(nodeConfig *nodeconfig.ConfigType) {
 			fmt.Printf("error when loading bls key, err :%v\n", err)
 			os.Exit(100)
 		}
-		if !genesis.IsBlsPublicKeyWhiteListed(consensusPriKey.GetPublicKey().SerializeToHexStr()) {
+		if !isBlsPublicKeyWhiteListed(consensusPriKey.GetPublicKey().SerializeToHexStr()) {
 			fmt.Println("Your bls key is not whitelisted")
 			os.Exit(100)
 		}
@@ -291,14 +373,20 @@ func createGlobalConfig() *nodeconfig.ConfigType {
 
 	nodeConfig.SelfPeer = p2p.Peer{IP: *ip, Port: *port, ConsensusPubKey: nodeConfig.ConsensusPubKey}
 
-	if accountIndex < core.GenesisShardNum { // The first node in a shard is the leader at genesis
+	genesisShardingConfig := core.ShardingSchedule.InstanceForEpoch(big.NewInt(core.GenesisEpoch))
+	if uint32(accountIndex) < genesisShardingConfig.NumShards() { // The first node in a shard is the leader at genesis
 		nodeConfig.Leader = nodeConfig.SelfPeer
 		nodeConfig.StringRole = "leader"
 	} else {
 		nodeConfig.StringRole = "validator"
 	}
 
-	nodeConfig.Host, err = p2pimpl.NewHost(&nodeConfig.SelfPeer, nodeConfig.P2pPriKey)
+	hostConfig, err := hostConfigFromFlags()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid p2p flags: %v\n", err)
+		os.Exit(1)
+	}
+	nodeConfig.Host, err = p2pimpl.NewHostWithConfig(&nodeConfig.SelfPeer, nodeConfig.P2pPriKey, hostConfig)
 	if *logConn {
 		nodeConfig.Host.GetP2PHost().Network().Notify(utils.NewConnLogger(utils.GetLogInstance()))
 	}
@@ -339,6 +427,10 @@ func setUpConsensusAndNode(nodeConfig *nodeconfig.ConfigType) *node.Node {
 	// Current node.
 	chainDBFactory := &shardchain.LDBFactory{RootDir: nodeConfig.DBDir}
 	currentNode := node.New(nodeConfig.Host, currentConsensus, chainDBFactory, *isArchival)
+	if *txHistoryIndex {
+		currentNode.Blockchain().SetTxHistoryIndex(true)
+		currentNode.Beaconchain().SetTxHistoryIndex(true)
+	}
 	currentNode.NodeConfig.SetRole(nodeconfig.NewNode)
 	currentNode.StakingAccount = myAccount
 	utils.GetLogInstance().Info("node account set",
@@ -438,7 +530,12 @@ func setUpConsensusAndNode(nodeConfig *nodeconfig.ConfigType) *node.Node {
 }
 
 func main() {
+	if runSubcommand() {
+		return
+	}
+
 	flag.Var(&utils.BootNodes, "bootnodes", "a list of bootnode multiaddress (delimited by ,)")
+	flag.Var(&relayAddrs, "relays", "a list of circuit relay multiaddresses (delimited by ,) to be reached through when behind a NAT")
 	flag.Parse()
 
 	// Configure log parameters
@@ -477,6 +574,9 @@ func main() {
 	}
 	go currentNode.SupportSyncing()
 	currentNode.ServiceManagerSetup()
+	currentNode.SetAdminRPC(*adminRPC)
+	currentNode.SetDebugRPC(*debugRPC)
+	currentNode.SetCompressMessages(*compressMessages)
 	if err := currentNode.StartRPC(*port); err != nil {
 		ctxerror.Warn(utils.GetLogger(), err, "StartRPC failed")
 	}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/harmony-one/harmony/core"
+)
+
+var (
+	// snapshot export subcommand
+	snapshotExportCommand = flag.NewFlagSet("export", flag.ExitOnError)
+	snapshotExportDBDir   = snapshotExportCommand.String("db_dir", "", "blockchain database directory")
+	snapshotExportShardID = snapshotExportCommand.Int("shard_id", 0, "the shard whose state to export")
+	snapshotExportBlock   = snapshotExportCommand.Int64("block", -1, "the number of the block whose state to export")
+	snapshotExportOut     = snapshotExportCommand.String("out", "", "the snapshot file to write")
+
+	// snapshot import subcommand
+	snapshotImportCommand = flag.NewFlagSet("import", flag.ExitOnError)
+	snapshotImportDBDir   = snapshotImportCommand.String("db_dir", "", "blockchain database directory")
+	snapshotImportShardID = snapshotImportCommand.Int("shard_id", 0, "the shard whose database to seed")
+	snapshotImportIn      = snapshotImportCommand.String("in", "", "the snapshot file to read")
+)
+
+// runSnapshotCommand runs the snapshot subcommand named by args[0].
+func runSnapshotCommand(args []string) {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: harmony snapshot <command> [flags]")
+		fmt.Fprintln(os.Stderr, "Commands:")
+		fmt.Fprintln(os.Stderr, "  export  write the state at a block to a snapshot file")
+		fmt.Fprintln(os.Stderr, "  import  seed a fresh database from a snapshot file")
+		os.Exit(1)
+	}
+	switch args[0] {
+	case "export":
+		_ = snapshotExportCommand.Parse(args[1:])
+		exportSnapshot()
+	case "import":
+		_ = snapshotImportCommand.Parse(args[1:])
+		importSnapshot()
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown snapshot command %q\n", args[0])
+		os.Exit(1)
+	}
+}
+
+// exportSnapshot writes the state of a shard chain at the requested block to
+// a snapshot file. The node must not be running.
+func exportSnapshot() {
+	if *snapshotExportBlock < 0 || *snapshotExportOut == "" {
+		fmt.Fprintln(os.Stderr, "Missing --block or --out")
+		snapshotExportCommand.PrintDefaults()
+		os.Exit(1)
+	}
+	db := openShardChainDB(*snapshotExportDBDir, *snapshotExportShardID)
+	defer db.Close()
+	out, err := os.Create(*snapshotExportOut)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot create snapshot file: %v\n", err)
+		db.Close()
+		os.Exit(1)
+	}
+	defer out.Close()
+	if err := core.ExportSnapshot(db, uint64(*snapshotExportBlock), out); err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot export snapshot: %v\n", err)
+		out.Close()
+		os.Remove(*snapshotExportOut)
+		db.Close()
+		os.Exit(1)
+	}
+	fmt.Printf("Exported state of shard %d at block %d to %s\n",
+		*snapshotExportShardID, *snapshotExportBlock, *snapshotExportOut)
+}
+
+// importSnapshot seeds a fresh shard chain database from a snapshot file, so
+// that the node continues syncing from the snapshot block.
+func importSnapshot() {
+	if *snapshotImportIn == "" {
+		fmt.Fprintln(os.Stderr, "Missing --in snapshot file")
+		snapshotImportCommand.PrintDefaults()
+		os.Exit(1)
+	}
+	in, err := os.Open(*snapshotImportIn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot open snapshot file: %v\n", err)
+		os.Exit(1)
+	}
+	defer in.Close()
+	db := openShardChainDB(*snapshotImportDBDir, *snapshotImportShardID)
+	defer db.Close()
+	block, err := core.ImportSnapshot(db, uint32(*snapshotImportShardID), in)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot import snapshot: %v\n", err)
+		db.Close()
+		os.Exit(1)
+	}
+	fmt.Printf("Imported state of shard %d at block %d (%s)\n",
+		*snapshotImportShardID, block.NumberU64(), block.Hash().Hex())
+}
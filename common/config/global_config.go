@@ -1,5 +1,10 @@
 package config
 
+import (
+	"fmt"
+	"math/big"
+)
+
 // NetworkType describes the type of Harmony network
 type NetworkType int
 
@@ -12,3 +17,33 @@ const (
 
 // Network is the type of Harmony network
 var Network = Testnet
+
+// ChainID returns the chain ID committed to by replay-protected transactions
+// on the network, so that a transaction signed for one network cannot be
+// replayed on another.
+func (n NetworkType) ChainID() *big.Int {
+	switch n {
+	case Mainnet:
+		return big.NewInt(1)
+	case Testnet:
+		return big.NewInt(2)
+	case Devnet:
+		return big.NewInt(3)
+	default:
+		return big.NewInt(int64(n) + 1)
+	}
+}
+
+// ParseNetworkType returns the network type of the given name: mainnet,
+// testnet or devnet.
+func ParseNetworkType(name string) (NetworkType, error) {
+	switch name {
+	case "mainnet":
+		return Mainnet, nil
+	case "testnet":
+		return Testnet, nil
+	case "devnet":
+		return Devnet, nil
+	}
+	return 0, fmt.Errorf("unknown network type %q", name)
+}
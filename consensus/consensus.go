@@ -10,7 +10,6 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/harmony-one/bls/ffi/go/bls"
-	"github.com/harmony-one/harmony/common/denominations"
 	consensus_engine "github.com/harmony-one/harmony/consensus/engine"
 	"github.com/harmony-one/harmony/contracts/structs"
 	"github.com/harmony-one/harmony/core/state"
@@ -24,12 +23,6 @@ import (
 	"github.com/harmony-one/harmony/p2p"
 )
 
-// Block reward per block signature.
-// TODO ek – per sig per stake
-var (
-	BlockReward = big.NewInt(denominations.One / 10)
-)
-
 // Consensus is the main struct with all states and data related to consensus process.
 type Consensus struct {
 	// pbftLog stores the pbft messages and blocks during PBFT process
@@ -338,6 +331,8 @@ func accumulateRewards(
 	if err := mask.SetMask(parentHeader.CommitBitmap); err != nil {
 		return ctxerror.New("cannot set group sig mask bits").WithCause(err)
 	}
+	// TODO ek – per sig per stake
+	blockReward := bc.HarmonyConfig().SignerReward()
 	totalAmount := big.NewInt(0)
 	numAccounts := 0
 	signers := []string{}
@@ -352,8 +347,8 @@ func accumulateRewards(
 		numAccounts++
 		account := member.EcdsaAddress
 		signers = append(signers, account.Hex())
-		state.AddBalance(account, BlockReward)
-		totalAmount = new(big.Int).Add(totalAmount, BlockReward)
+		state.AddBalance(account, blockReward)
+		totalAmount = new(big.Int).Add(totalAmount, blockReward)
 	}
 	getLogger().Debug("【Block Reward] Successfully paid out block reward",
 		"NumAccounts", numAccounts,
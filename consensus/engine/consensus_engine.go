@@ -8,6 +8,7 @@ import (
 
 	"github.com/harmony-one/harmony/core/state"
 	"github.com/harmony-one/harmony/core/types"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
 )
 
 // ChainReader defines a small collection of methods needed to access the local
@@ -17,6 +18,9 @@ type ChainReader interface {
 	// Config retrieves the blockchain's chain configuration.
 	Config() *params.ChainConfig
 
+	// HarmonyConfig retrieves the blockchain's Harmony chain configuration.
+	HarmonyConfig() *hmyparams.ChainConfig
+
 	// CurrentHeader retrieves the current header from the local chain.
 	CurrentHeader() *types.Header
 
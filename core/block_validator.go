@@ -23,6 +23,7 @@ import (
 	consensus_engine "github.com/harmony-one/harmony/consensus/engine"
 	"github.com/harmony-one/harmony/core/state"
 	"github.com/harmony-one/harmony/core/types"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
 )
 
 // BlockValidator is responsible for validating block headers, uncles and
@@ -61,12 +62,27 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 	}
 	// Header validity is known at this point, check the uncles and transactions
 	header := block.Header()
+	if config := v.bc.HarmonyConfig(); config.IsGasTarget(header.Epoch) {
+		parent := v.bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
+		if err := ValidateGasLimit(header, parent, config.GasTargetPolicy(v.bc.ShardID())); err != nil {
+			return err
+		}
+	}
 	//if err := v.engine.VerifyUncles(v.bc, block); err != nil {
 	//	return err
 	//}
 	if hash := types.DeriveSha(block.Transactions()); hash != header.TxHash {
 		return fmt.Errorf("transaction root hash mismatch: have %x, want %x", hash, header.TxHash)
 	}
+	replayProtection := v.bc.HarmonyConfig().IsReplayProtection(header.Epoch)
+	for i, tx := range block.Transactions() {
+		if tx.ShardID() != v.bc.ShardID() {
+			return fmt.Errorf("transaction %d (%x) is for shard %d, not %d: %v", i, tx.Hash(), tx.ShardID(), v.bc.ShardID(), ErrInvalidShard)
+		}
+		if replayProtection && !tx.Protected() {
+			return fmt.Errorf("transaction %d (%x): %v", i, tx.Hash(), ErrUnprotectedTx)
+		}
+	}
 	return nil
 }
 
@@ -134,3 +150,61 @@ func CalcGasLimit(parent *types.Block, gasFloor, gasCeil uint64) uint64 {
 	}
 	return limit
 }
+
+// CalcGasLimitByPolicy computes the gas limit of the block after parent under
+// the gas target policy of its shard. The limit moves by 1/1024 of the
+// parent's limit towards keeping blocks at the target utilization; a parent
+// limit outside the policy bounds, as at the fork, is brought within them at
+// once.
+func CalcGasLimitByPolicy(parent *types.Block, policy hmyparams.GasTargetPolicy) uint64 {
+	limit := parent.GasLimit()
+	if limit < policy.MinGasLimit {
+		return policy.MinGasLimit
+	}
+	if limit > policy.MaxGasLimit {
+		return policy.MaxGasLimit
+	}
+	step := limit / params.GasLimitBoundDivisor
+	target := limit / 100 * policy.TargetUtilization
+	switch {
+	case parent.GasUsed() > target:
+		limit += step
+	case parent.GasUsed() < target:
+		limit -= step
+	}
+	if limit < policy.MinGasLimit {
+		limit = policy.MinGasLimit
+	} else if limit > policy.MaxGasLimit {
+		limit = policy.MaxGasLimit
+	}
+	return limit
+}
+
+// ValidateGasLimit checks that the gas limit of header is within the bounds of
+// the gas target policy of its shard, and, unless the parent's limit was
+// outside them, within 1/1024 of the parent's limit.
+func ValidateGasLimit(header, parent *types.Header, policy hmyparams.GasTargetPolicy) error {
+	limit := header.GasLimit
+	if limit < policy.MinGasLimit || limit > policy.MaxGasLimit {
+		return fmt.Errorf("gas limit %d outside of bounds [%d, %d]: %v",
+			limit, policy.MinGasLimit, policy.MaxGasLimit, ErrInvalidGasLimit)
+	}
+	if header.GasUsed > limit {
+		return fmt.Errorf("gas used %d above gas limit %d: %v", header.GasUsed, limit, ErrInvalidGasLimit)
+	}
+	if parent == nil {
+		return consensus_engine.ErrUnknownAncestor
+	}
+	if parent.GasLimit < policy.MinGasLimit || parent.GasLimit > policy.MaxGasLimit {
+		return nil
+	}
+	diff := limit - parent.GasLimit
+	if limit < parent.GasLimit {
+		diff = parent.GasLimit - limit
+	}
+	if bound := parent.GasLimit / params.GasLimitBoundDivisor; diff > bound {
+		return fmt.Errorf("gas limit %d changed by %d from parent gas limit %d, above %d: %v",
+			limit, diff, parent.GasLimit, bound, ErrInvalidGasLimit)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/harmony-one/harmony/core/types"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
+)
+
+var testGasTargetPolicy = hmyparams.GasTargetPolicy{
+	MinGasLimit:       1024000,
+	MaxGasLimit:       4096000,
+	TargetUtilization: 50,
+}
+
+func TestCalcGasLimitByPolicy(t *testing.T) {
+	tests := []struct {
+		parentLimit, parentUsed, want uint64
+	}{
+		{2048000, 1024000, 2048000}, // at target
+		{2048000, 2048000, 2050000}, // above target
+		{2048000, 0, 2046000},       // below target
+		{1024000, 0, 1024000},       // at the lower bound
+		{4096000, 4096000, 4096000}, // at the upper bound
+		{10000000000, 0, 4096000},   // above the bounds, as before the fork
+		{5000, 0, 1024000},          // below the bounds
+	}
+	for _, test := range tests {
+		parent := types.NewBlockWithHeader(&types.Header{
+			Number:   big.NewInt(1),
+			GasLimit: test.parentLimit,
+			GasUsed:  test.parentUsed,
+		})
+		if got := CalcGasLimitByPolicy(parent, testGasTargetPolicy); got != test.want {
+			t.Errorf("CalcGasLimitByPolicy(limit %d, used %d) = %d, expected = %d",
+				test.parentLimit, test.parentUsed, got, test.want)
+		}
+	}
+}
+
+func TestValidateGasLimit(t *testing.T) {
+	tests := []struct {
+		parentLimit, limit, used uint64
+		valid                    bool
+	}{
+		{2048000, 2048000, 0, true},
+		{2048000, 2050000, 0, true},
+		{2048000, 2050001, 0, false}, // changed too much
+		{2048000, 2046000, 0, true},
+		{2048000, 2045999, 0, false},       // changed too much
+		{2048000, 2048000, 2048001, false}, // used above limit
+		{10000000000, 4096000, 0, true},    // brought within the bounds at once
+		{10000000000, 4096001, 0, false},   // above the bounds
+		{1024000, 1023999, 0, false},       // below the bounds
+	}
+	for _, test := range tests {
+		parent := &types.Header{Number: big.NewInt(1), GasLimit: test.parentLimit}
+		header := &types.Header{Number: big.NewInt(2), GasLimit: test.limit, GasUsed: test.used}
+		err := ValidateGasLimit(header, parent, testGasTargetPolicy)
+		if (err == nil) != test.valid {
+			t.Errorf("ValidateGasLimit(parent limit %d, limit %d, used %d) = %v, expected valid = %v",
+				test.parentLimit, test.limit, test.used, err, test.valid)
+		}
+	}
+}
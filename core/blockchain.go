@@ -40,6 +40,7 @@ import (
 	"github.com/ethereum/go-ethereum/trie"
 	lru "github.com/hashicorp/golang-lru"
 
+	"github.com/harmony-one/harmony/common/config"
 	consensus_engine "github.com/harmony-one/harmony/consensus/engine"
 	"github.com/harmony-one/harmony/contracts/structs"
 	"github.com/harmony-one/harmony/core/rawdb"
@@ -47,6 +48,7 @@ import (
 	"github.com/harmony-one/harmony/core/types"
 	"github.com/harmony-one/harmony/core/vm"
 	"github.com/harmony-one/harmony/internal/ctxerror"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
 	"github.com/harmony-one/harmony/internal/utils"
 )
 
@@ -64,19 +66,11 @@ const (
 	receiptsCacheLimit  = 32
 	maxFutureBlocks     = 256
 	maxTimeFutureBlocks = 30
-	badBlockLimit       = 10
+	badBlockLimit       = 100
 	triesInMemory       = 128
 	shardCacheLimit     = 2
 	epochCacheLimit     = 10
 
-	// BlocksPerEpoch is the number of blocks in one epoch
-	// currently set to small number for testing
-	// in future, this need to be adjusted dynamically instead of constant
-	// TODO ek – inflate to disable resharding until we can 1) fix shard
-	//  state mutation bug and 2) implement key passphrase recycle across
-	//  process restart (exec) for shard migration
-	BlocksPerEpoch = 1000000000000
-
 	// BlockChainVersion ensures that an incompatible database forces a resync from scratch.
 	BlockChainVersion = 3
 )
@@ -104,8 +98,9 @@ type CacheConfig struct {
 // included in the canonical one where as GetBlockByNumber always represents the
 // canonical chain.
 type BlockChain struct {
-	chainConfig *params.ChainConfig // Chain & network configuration
-	cacheConfig *CacheConfig        // Cache configuration for pruning
+	chainConfig *params.ChainConfig    // Chain & network configuration
+	hmyConfig   *hmyparams.ChainConfig // Harmony epoch fork schedule
+	cacheConfig *CacheConfig           // Cache configuration for pruning
 
 	db     ethdb.Database // Low level persistent database to store final content in
 	triegc *prque.Prque   // Priority queue mapping block numbers to tries to gc
@@ -137,6 +132,8 @@ type BlockChain struct {
 	shardStateCache *lru.Cache
 	epochCache      *lru.Cache // Cache epoch number → first block number
 
+	txHistoryIndex bool // whether to maintain the address transaction history index
+
 	quit    chan struct{} // blockchain quit channel
 	running int32         // running must be called atomically
 	// procInterrupt must be atomically called
@@ -148,7 +145,7 @@ type BlockChain struct {
 	validator Validator // block and state validator interface
 	vmConfig  vm.Config
 
-	badBlocks      *lru.Cache              // Bad block cache
+	badBlocksMu    sync.Mutex              // Bad block list lock
 	shouldPreserve func(*types.Block) bool // Function used to determine whether should preserve the given block.
 }
 
@@ -167,7 +164,6 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	receiptsCache, _ := lru.New(receiptsCacheLimit)
 	blockCache, _ := lru.New(blockCacheLimit)
 	futureBlocks, _ := lru.New(maxFutureBlocks)
-	badBlocks, _ := lru.New(badBlockLimit)
 	shardCache, _ := lru.New(shardCacheLimit)
 	epochCache, _ := lru.New(epochCacheLimit)
 
@@ -188,7 +184,6 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 		epochCache:      epochCache,
 		engine:          engine,
 		vmConfig:        vmConfig,
-		badBlocks:       badBlocks,
 	}
 	bc.SetValidator(NewBlockValidator(chainConfig, bc, engine))
 	bc.SetProcessor(NewStateProcessor(chainConfig, bc, engine))
@@ -202,6 +197,14 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	if bc.genesisBlock == nil {
 		return nil, ErrNoGenesis
 	}
+	bc.hmyConfig = rawdb.ReadChainConfig(db, bc.genesisBlock.Hash())
+	if bc.hmyConfig == nil {
+		// Databases of earlier releases only hold a go-ethereum config, whose
+		// chain ID is the shard ID; store the network's config in its place.
+		bc.hmyConfig = hmyparams.ChainConfigByNetwork(config.Network)
+		log.Warn("Found genesis block without chain config, using network default", "config", bc.hmyConfig)
+		rawdb.WriteChainConfig(db, bc.genesisBlock.Hash(), bc.hmyConfig)
+	}
 	if err := bc.loadLastState(); err != nil {
 		return nil, err
 	}
@@ -210,8 +213,14 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	return bc, nil
 }
 
-// ValidateNewBlock validates new block.
-func (bc *BlockChain) ValidateNewBlock(block *types.Block) error {
+// ValidateNewBlock validates new block. The source identifies the leader or
+// peer that sent the block and is recorded if the block turns out to be bad.
+func (bc *BlockChain) ValidateNewBlock(block *types.Block, source string) error {
+	// Check the body, including the gas limit, before executing the block.
+	if err := bc.Validator().ValidateBody(block); err != nil && err != ErrKnownBlock {
+		bc.reportBlock(block, nil, err, source)
+		return err
+	}
 	state, err := state.New(bc.CurrentBlock().Root(), bc.stateCache)
 
 	if err != nil {
@@ -221,13 +230,13 @@ func (bc *BlockChain) ValidateNewBlock(block *types.Block) error {
 	// Process block using the parent state as reference point.
 	receipts, _, usedGas, err := bc.processor.Process(block, state, bc.vmConfig)
 	if err != nil {
-		bc.reportBlock(block, receipts, err)
+		bc.reportBlock(block, receipts, err, source)
 		return err
 	}
 
 	err = bc.Validator().ValidateState(block, bc.CurrentBlock(), state, receipts, usedGas)
 	if err != nil {
-		bc.reportBlock(block, receipts, err)
+		bc.reportBlock(block, receipts, err, source)
 		return err
 	}
 	return nil
@@ -235,12 +244,12 @@ func (bc *BlockChain) ValidateNewBlock(block *types.Block) error {
 
 // IsEpochBlock returns whether this block is the first block of an epoch.
 func IsEpochBlock(block *types.Block) bool {
-	return block.NumberU64()%BlocksPerEpoch == 0
+	return ShardingSchedule.IsFirstBlock(block.NumberU64())
 }
 
 // IsEpochLastBlock returns whether this block is the last block of an epoch.
 func IsEpochLastBlock(block *types.Block) bool {
-	return block.NumberU64()%BlocksPerEpoch == BlocksPerEpoch-1
+	return ShardingSchedule.IsLastBlock(block.NumberU64())
 }
 
 func (bc *BlockChain) getProcInterrupt() bool {
@@ -316,9 +325,25 @@ func (bc *BlockChain) SetHead(head uint64) error {
 	bc.mu.Lock()
 	defer bc.mu.Unlock()
 
-	// Rewind the header chain, deleting all block bodies until then
+	// Roll back the transaction lookups and address transaction history of
+	// the blocks being discarded
+	signer := types.NewHarmonySigner(bc.hmyConfig.ChainID, bc.ShardID())
+	batch := bc.db.NewBatch()
+	counts := make(map[common.Address]uint64)
+	for block := bc.CurrentBlock(); block != nil && block.NumberU64() > head; block = bc.GetBlock(block.ParentHash(), block.NumberU64()-1) {
+		rawdb.DeleteAddressTxHistory(bc.db, batch, block, signer, counts)
+		for _, tx := range block.Transactions() {
+			rawdb.DeleteTxLookupEntry(batch, tx.Hash())
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	// Rewind the header chain, deleting all block bodies and receipts until then
 	delFn := func(db rawdb.DatabaseDeleter, hash common.Hash, num uint64) {
 		rawdb.DeleteBody(db, hash, num)
+		rawdb.DeleteReceipts(db, hash, num)
 	}
 	bc.hc.SetHead(head, delFn)
 	currentHeader := bc.hc.CurrentHeader()
@@ -361,6 +386,55 @@ func (bc *BlockChain) SetHead(head uint64) error {
 	return bc.loadLastState()
 }
 
+// RewindTo safely rewinds the canonical chain to the given block number. Unlike
+// SetHead, it refuses to rewind to a block whose state is no longer available,
+// or past the block that committed the shard state of the current epoch.
+func (bc *BlockChain) RewindTo(head uint64) error {
+	current := bc.CurrentBlock()
+	if head >= current.NumberU64() {
+		return ctxerror.New("rewind target is not below the current head",
+			"target", head,
+			"head", current.NumberU64(),
+		)
+	}
+	target := bc.GetBlockByNumber(head)
+	if target == nil {
+		return ctxerror.New("rewind target block not found", "target", head)
+	}
+	if _, err := state.New(target.Root(), bc.stateCache); err != nil {
+		return ctxerror.New("state of rewind target is not available",
+			"target", head,
+			"root", target.Root(),
+		).WithCause(err)
+	}
+	if minHead := bc.MinRewindHead(); head < minHead {
+		return ctxerror.New("cannot rewind past the block that committed the shard state of the current epoch",
+			"target", head,
+			"minTarget", minHead,
+			"epoch", current.Header().Epoch,
+		)
+	}
+	return bc.SetHead(head)
+}
+
+// MinRewindHead returns the lowest block number the chain can be rewound to:
+// the last block of the previous epoch, which committed the shard state of
+// the current epoch.
+func (bc *BlockChain) MinRewindHead() uint64 {
+	epoch := bc.CurrentBlock().Header().Epoch
+	if epoch == nil || epoch.Sign() == 0 {
+		return 0
+	}
+	firstBlock := ShardingSchedule.EpochFirstBlock(epoch.Uint64())
+	if blockNum, err := bc.GetEpochBlockNumber(epoch); err == nil {
+		firstBlock = blockNum.Uint64()
+	}
+	if firstBlock == 0 {
+		return 0
+	}
+	return firstBlock - 1
+}
+
 // FastSyncCommitHead sets the current head block to the one defined by the hash
 // irrelevant what the chain contents were prior.
 func (bc *BlockChain) FastSyncCommitHead(hash common.Hash) error {
@@ -386,6 +460,20 @@ func (bc *BlockChain) ShardID() uint32 {
 	return uint32(bc.chainConfig.ChainID.Int64())
 }
 
+// HarmonyConfig returns the Harmony chain configuration, which schedules the
+// epoch-activated forks of the blockchain.
+func (bc *BlockChain) HarmonyConfig() *hmyparams.ChainConfig {
+	return bc.hmyConfig
+}
+
+// SetTxHistoryIndex turns the address transaction history index on or off.
+// The index covers the canonical blocks written while it is on.
+func (bc *BlockChain) SetTxHistoryIndex(enabled bool) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	bc.txHistoryIndex = enabled
+}
+
 // GasLimit returns the gas limit of the current HEAD block.
 func (bc *BlockChain) GasLimit() uint64 {
 	return bc.CurrentBlock().GasLimit()
@@ -460,7 +548,7 @@ func (bc *BlockChain) ResetWithGenesisBlock(genesis *types.Block) error {
 	rawdb.WriteBlock(bc.db, genesis)
 
 	bc.genesisBlock = genesis
-	bc.insert(bc.genesisBlock)
+	bc.insert(bc.db, bc.genesisBlock)
 	bc.currentBlock.Store(bc.genesisBlock)
 	bc.hc.SetGenesis(bc.genesisBlock.Header())
 	bc.hc.SetCurrentHeader(bc.genesisBlock.Header())
@@ -523,23 +611,24 @@ func (bc *BlockChain) ExportN(w io.Writer, first uint64, last uint64) error {
 // insert injects a new head block into the current block chain. This method
 // assumes that the block is indeed a true head. It will also reset the head
 // header and the head fast sync block to this very same block if they are older
-// or if they are on a different side chain.
+// or if they are on a different side chain. The canonical hash and head
+// markers are written to db, which may be a batch.
 //
 // Note, this function assumes that the `mu` mutex is held!
-func (bc *BlockChain) insert(block *types.Block) {
+func (bc *BlockChain) insert(db rawdb.DatabaseWriter, block *types.Block) {
 	// If the block is on a side chain or an unknown one, force other heads onto it too
 	updateHeads := rawdb.ReadCanonicalHash(bc.db, block.NumberU64()) != block.Hash()
 
 	// Add the block to the canonical chain number scheme and mark as the head
-	rawdb.WriteCanonicalHash(bc.db, block.Hash(), block.NumberU64())
-	rawdb.WriteHeadBlockHash(bc.db, block.Hash())
+	rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+	rawdb.WriteHeadBlockHash(db, block.Hash())
 
 	bc.currentBlock.Store(block)
 
 	// If the block is better than our head or is on a different chain, force update heads
 	if updateHeads {
 		bc.hc.SetCurrentHeader(block.Header())
-		rawdb.WriteHeadFastBlockHash(bc.db, block.Hash())
+		rawdb.WriteHeadFastBlockHash(db, block.Hash())
 
 		bc.currentFastBlock.Store(block)
 	}
@@ -798,8 +887,8 @@ func (bc *BlockChain) Rollback(chain []common.Hash) {
 }
 
 // SetReceiptsData computes all the non-consensus fields of the receipts
-func SetReceiptsData(config *params.ChainConfig, block *types.Block, receipts types.Receipts) error {
-	signer := types.MakeSigner(config, block.Number())
+func SetReceiptsData(config *params.ChainConfig, hmyConfig *hmyparams.ChainConfig, block *types.Block, receipts types.Receipts) error {
+	signer := types.NewHarmonySigner(hmyConfig.ChainID, block.ShardID())
 
 	transactions, logIndex := block.Transactions(), uint(0)
 	if len(transactions) != len(receipts) {
@@ -873,7 +962,7 @@ func (bc *BlockChain) InsertReceiptChain(blockChain types.Blocks, receiptChain [
 			continue
 		}
 		// Compute all the non-consensus fields of the receipts
-		if err := SetReceiptsData(bc.chainConfig, block, receipts); err != nil {
+		if err := SetReceiptsData(bc.chainConfig, bc.hmyConfig, block, receipts); err != nil {
 			return i, fmt.Errorf("failed to set receipts data: %v", err)
 		}
 		// Write all the data out into the database
@@ -1044,9 +1133,12 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 	if reorg {
 		// Reorganise the chain if the parent is not the head block
 		if block.ParentHash() != currentBlock.Hash() {
+			// reorg also indexes the address transaction history of block
 			if err := bc.reorg(currentBlock, block); err != nil {
 				return NonStatTy, err
 			}
+		} else if bc.txHistoryIndex {
+			rawdb.WriteAddressTxHistory(bc.db, batch, block, types.NewHarmonySigner(bc.hmyConfig.ChainID, bc.ShardID()), nil)
 		}
 		// Write the positional metadata for transaction/receipt lookups and preimages
 		rawdb.WriteTxLookupEntries(batch, block)
@@ -1062,7 +1154,7 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 
 	// Set new head.
 	if status == CanonStatTy {
-		bc.insert(block)
+		bc.insert(bc.db, block)
 	}
 	bc.futureBlocks.Remove(block.Hash())
 	return status, nil
@@ -1075,7 +1167,13 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 //
 // After insertion is done, all accumulated events will be fired.
 func (bc *BlockChain) InsertChain(chain types.Blocks) (int, error) {
-	n, events, logs, err := bc.insertChain(chain)
+	return bc.InsertChainFrom(chain, "")
+}
+
+// InsertChainFrom is like InsertChain, but also records the leader or peer the
+// blocks came from, so that bad blocks can be attributed to their sender.
+func (bc *BlockChain) InsertChainFrom(chain types.Blocks, source string) (int, error) {
+	n, events, logs, err := bc.insertChain(chain, source)
 	bc.PostChainEvents(events, logs)
 	// TODO ek – make this a post-chain event
 	if err == nil {
@@ -1100,7 +1198,7 @@ func (bc *BlockChain) InsertChain(chain types.Blocks) (int, error) {
 // insertChain will execute the actual chain insertion and event aggregation. The
 // only reason this method exists as a separate one is to make locking cleaner
 // with deferred statements.
-func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*types.Log, error) {
+func (bc *BlockChain) insertChain(chain types.Blocks, source string) (int, []interface{}, []*types.Log, error) {
 	// Sanity check that we have something meaningful to import
 	if len(chain) == 0 {
 		return 0, nil, nil, nil
@@ -1211,7 +1309,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*ty
 			}
 			// Import all the pruned blocks to make the state available
 			bc.chainmu.Unlock()
-			_, evs, logs, err := bc.insertChain(winner)
+			_, evs, logs, err := bc.insertChain(winner, source)
 			bc.chainmu.Lock()
 			events, coalescedLogs = evs, logs
 
@@ -1220,7 +1318,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*ty
 			}
 
 		case err != nil:
-			bc.reportBlock(block, nil, err)
+			bc.reportBlock(block, nil, err, source)
 			return i, events, coalescedLogs, err
 		}
 		// Create a new statedb using the parent block and report an
@@ -1238,13 +1336,13 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*ty
 		// Process block using the parent state as reference point.
 		receipts, logs, usedGas, err := bc.processor.Process(block, state, bc.vmConfig)
 		if err != nil {
-			bc.reportBlock(block, receipts, err)
+			bc.reportBlock(block, receipts, err, source)
 			return i, events, coalescedLogs, err
 		}
 		// Validate the state using the default validator
 		err = bc.Validator().ValidateState(block, parent, state, receipts, usedGas)
 		if err != nil {
-			bc.reportBlock(block, receipts, err)
+			bc.reportBlock(block, receipts, err, source)
 			return i, events, coalescedLogs, err
 		}
 		proctime := time.Since(bstart)
@@ -1425,24 +1523,36 @@ func (bc *BlockChain) reorg(oldBlock, newBlock *types.Block) error {
 	} else {
 		log.Error("Impossible reorg, please file an issue", "oldnum", oldBlock.Number(), "oldhash", oldBlock.Hash(), "newnum", newBlock.Number(), "newhash", newBlock.Hash())
 	}
+	// Roll back the address transaction history of the old chain, newest
+	// first, in the same batch as the new canonical chain is written to
+	signer := types.NewHarmonySigner(bc.hmyConfig.ChainID, bc.ShardID())
+	batch := bc.db.NewBatch()
+	counts := make(map[common.Address]uint64)
+	for _, block := range oldChain {
+		rawdb.DeleteAddressTxHistory(bc.db, batch, block, signer, counts)
+	}
 	// Insert the new chain, taking care of the proper incremental order
 	var addedTxs types.Transactions
 	for i := len(newChain) - 1; i >= 0; i-- {
 		// insert the block in the canonical way, re-writing history
-		bc.insert(newChain[i])
+		bc.insert(batch, newChain[i])
 		// write lookup entries for hash based transaction/receipt searches
-		rawdb.WriteTxLookupEntries(bc.db, newChain[i])
+		rawdb.WriteTxLookupEntries(batch, newChain[i])
+		if bc.txHistoryIndex {
+			rawdb.WriteAddressTxHistory(bc.db, batch, newChain[i], signer, counts)
+		}
 		addedTxs = append(addedTxs, newChain[i].Transactions()...)
 	}
 	// calculate the difference between deleted and added transactions
 	diff := types.TxDifference(deletedTxs, addedTxs)
 	// When transactions get deleted from the database that means the
 	// receipts that were created in the fork must also be deleted
-	batch := bc.db.NewBatch()
 	for _, tx := range diff {
 		rawdb.DeleteTxLookupEntry(batch, tx.Hash())
 	}
-	batch.Write()
+	if err := batch.Write(); err != nil {
+		return err
+	}
 
 	if len(deletedLogs) > 0 {
 		go bc.rmLogsFeed.Send(RemovedLogsEvent{deletedLogs})
@@ -1493,26 +1603,27 @@ func (bc *BlockChain) update() {
 	}
 }
 
-// BadBlocks returns a list of the last 'bad blocks' that the client has seen on the network
-func (bc *BlockChain) BadBlocks() []*types.Block {
-	blocks := make([]*types.Block, 0, bc.badBlocks.Len())
-	for _, hash := range bc.badBlocks.Keys() {
-		if blk, exist := bc.badBlocks.Peek(hash); exist {
-			block := blk.(*types.Block)
-			blocks = append(blocks, block)
-		}
-	}
-	return blocks
+// BadBlocks returns the last 'bad blocks' that the client has seen on the
+// network, newest first, along with why they were rejected and who sent them.
+func (bc *BlockChain) BadBlocks() []*rawdb.BadBlock {
+	return rawdb.ReadAllBadBlocks(bc.db)
 }
 
-// addBadBlock adds a bad block to the bad-block LRU cache
-func (bc *BlockChain) addBadBlock(block *types.Block) {
-	bc.badBlocks.Add(block.Hash(), block)
+// addBadBlock persists a bad block, keeping at most badBlockLimit of them.
+func (bc *BlockChain) addBadBlock(block *types.Block, err error, source string) {
+	bc.badBlocksMu.Lock()
+	defer bc.badBlocksMu.Unlock()
+	rawdb.WriteBadBlock(bc.db, &rawdb.BadBlock{
+		Block:  block,
+		Error:  err.Error(),
+		Source: source,
+		Time:   uint64(time.Now().Unix()),
+	}, badBlockLimit)
 }
 
-// reportBlock logs a bad block error.
-func (bc *BlockChain) reportBlock(block *types.Block, receipts types.Receipts, err error) {
-	bc.addBadBlock(block)
+// reportBlock logs and persists a bad block error.
+func (bc *BlockChain) reportBlock(block *types.Block, receipts types.Receipts, err error, source string) {
+	bc.addBadBlock(block, err, source)
 
 	var receiptString string
 	for _, receipt := range receipts {
@@ -1527,8 +1638,9 @@ Hash: 0x%x
 %v
 
 Error: %v
+Source: %v
 ##############################
-`, bc.chainConfig, block.Number(), block.Hash(), receiptString, err))
+`, bc.chainConfig, block.Number(), block.Hash(), receiptString, err, source))
 }
 
 // InsertHeaderChain attempts to insert the given header chain in to the local
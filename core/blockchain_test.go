@@ -0,0 +1,50 @@
+package core
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/harmony-one/harmony/common/config"
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/vm"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
+)
+
+// TestNewBlockChainLegacyChainConfig opens a database laid out by earlier
+// releases, which stored only the go-ethereum config, with the shard ID as
+// chain ID, under the "ethereum-config-" key.
+func TestNewBlockChainLegacyChainConfig(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	legacyConfig := *params.TestChainConfig
+	legacyConfig.ChainID = big.NewInt(1)
+	gspec := Genesis{Config: &legacyConfig, ShardID: 1}
+	genesis := gspec.MustCommit(db)
+	if err := db.Delete(append([]byte("harmony-config-"), genesis.Hash().Bytes()...)); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(&legacyConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put(append([]byte("ethereum-config-"), genesis.Hash().Bytes()...), data); err != nil {
+		t.Fatal(err)
+	}
+
+	bc, err := NewBlockChain(db, nil, &legacyConfig, nil, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("cannot open legacy chain: %v", err)
+	}
+	defer bc.Stop()
+	want := hmyparams.ChainConfigByNetwork(config.Network).ChainID
+	if got := bc.HarmonyConfig().ChainID; got.Cmp(want) != 0 {
+		t.Errorf("ChainID = %v, expected = %v", got, want)
+	}
+	stored := rawdb.ReadChainConfig(db, genesis.Hash())
+	if stored == nil || stored.ChainID.Cmp(want) != 0 {
+		t.Errorf("stored chain config = %+v, expected chain ID %v", stored, want)
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/harmony-one/harmony/core/state"
 	"github.com/harmony-one/harmony/core/types"
 	"github.com/harmony-one/harmony/core/vm"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
 )
 
 // BlockGen creates blocks for testing.
@@ -96,7 +97,11 @@ func (b *BlockGen) AddTxWithChain(bc *BlockChain, tx *types.Transaction) {
 		b.SetCoinbase(common.Address{})
 	}
 	b.statedb.Prepare(tx.Hash(), common.Hash{}, len(b.txs))
-	receipt, _, err := ApplyTransaction(b.config, bc, &b.header.Coinbase, b.gasPool, b.statedb, b.header, tx, &b.header.GasUsed, vm.Config{})
+	hmyConfig := hmyparams.TestChainConfig
+	if bc != nil {
+		hmyConfig = bc.HarmonyConfig()
+	}
+	receipt, _, err := ApplyTransaction(b.config, hmyConfig, bc, &b.header.Coinbase, b.gasPool, b.statedb, b.header, tx, &b.header.GasUsed, vm.Config{})
 	if err != nil {
 		panic(err)
 	}
@@ -267,6 +272,11 @@ func (cr *fakeChainReader) Config() *params.ChainConfig {
 	return cr.config
 }
 
+// HarmonyConfig returns the Harmony chain configuration.
+func (cr *fakeChainReader) HarmonyConfig() *hmyparams.ChainConfig {
+	return hmyparams.TestChainConfig
+}
+
 func (cr *fakeChainReader) CurrentHeader() *types.Header                            { return nil }
 func (cr *fakeChainReader) GetHeaderByNumber(number uint64) *types.Header           { return nil }
 func (cr *fakeChainReader) GetHeaderByHash(hash common.Hash) *types.Header          { return nil }
@@ -33,6 +33,10 @@ var (
 	// next one expected based on the local chain.
 	ErrNonceTooHigh = errors.New("nonce too high")
 
+	// ErrInvalidGasLimit is returned if the gas limit of a block is outside of
+	// the gas target policy of its shard.
+	ErrInvalidGasLimit = errors.New("invalid gas limit")
+
 	// ErrShardStateNotMatch is returned if the calculated shardState hash not equal that in the block header
 	ErrShardStateNotMatch = errors.New("shard state root hash not match")
 )
@@ -11,6 +11,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/params"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
 )
 
 var _ = (*genesisSpecMarshaling)(nil)
@@ -19,6 +20,7 @@ var _ = (*genesisSpecMarshaling)(nil)
 func (g Genesis) MarshalJSON() ([]byte, error) {
 	type Genesis struct {
 		Config         *params.ChainConfig                         `json:"config"`
+		ChainConfig    *hmyparams.ChainConfig                      `json:"chainConfig"`
 		Nonce          math.HexOrDecimal64                         `json:"nonce"`
 		ShardID        uint32                                      `json:"shardID"`
 		Timestamp      math.HexOrDecimal64                         `json:"timestamp"`
@@ -35,6 +37,7 @@ func (g Genesis) MarshalJSON() ([]byte, error) {
 	}
 	var enc Genesis
 	enc.Config = g.Config
+	enc.ChainConfig = g.ChainConfig
 	enc.Nonce = math.HexOrDecimal64(g.Nonce)
 	enc.ShardID = g.ShardID
 	enc.Timestamp = math.HexOrDecimal64(g.Timestamp)
@@ -60,6 +63,7 @@ func (g Genesis) MarshalJSON() ([]byte, error) {
 func (g *Genesis) UnmarshalJSON(input []byte) error {
 	type Genesis struct {
 		Config         *params.ChainConfig                         `json:"config"`
+		ChainConfig    *hmyparams.ChainConfig                      `json:"chainConfig"`
 		Nonce          *math.HexOrDecimal64                        `json:"nonce"`
 		ShardID        *uint32                                     `json:"shardID"`
 		Timestamp      *math.HexOrDecimal64                        `json:"timestamp"`
@@ -81,6 +85,9 @@ func (g *Genesis) UnmarshalJSON(input []byte) error {
 	if dec.Config != nil {
 		g.Config = dec.Config
 	}
+	if dec.ChainConfig != nil {
+		g.ChainConfig = dec.ChainConfig
+	}
 	if dec.Nonce != nil {
 		g.Nonce = uint64(*dec.Nonce)
 	}
@@ -34,6 +34,7 @@ import (
 	"github.com/harmony-one/harmony/core/rawdb"
 	"github.com/harmony-one/harmony/core/state"
 	"github.com/harmony-one/harmony/core/types"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
 	"github.com/harmony-one/harmony/internal/utils"
 )
 
@@ -43,20 +44,21 @@ import (
 var errGenesisNoConfig = errors.New("genesis has no chain configuration")
 
 // Genesis specifies the header fields, state of a genesis block. It also defines hard
-// fork switch-over blocks through the chain configuration.
+// fork switch-over epochs through the Harmony chain configuration.
 type Genesis struct {
-	Config         *params.ChainConfig `json:"config"`
-	Nonce          uint64              `json:"nonce"`
-	ShardID        uint32              `json:"shardID"`
-	Timestamp      uint64              `json:"timestamp"`
-	ExtraData      []byte              `json:"extraData"`
-	GasLimit       uint64              `json:"gasLimit"   gencodec:"required"`
-	Difficulty     *big.Int            `json:"difficulty" gencodec:"required"`
-	Mixhash        common.Hash         `json:"mixHash"`
-	Coinbase       common.Address      `json:"coinbase"`
-	Alloc          GenesisAlloc        `json:"alloc"      gencodec:"required"`
-	ShardStateHash common.Hash         `json:"shardStateHash"`
-	ShardState     types.ShardState    `json:"shardState"`
+	Config         *params.ChainConfig    `json:"config"`      // EVM rules
+	ChainConfig    *hmyparams.ChainConfig `json:"chainConfig"` // Harmony epoch fork schedule
+	Nonce          uint64                 `json:"nonce"`
+	ShardID        uint32                 `json:"shardID"`
+	Timestamp      uint64                 `json:"timestamp"`
+	ExtraData      []byte                 `json:"extraData"`
+	GasLimit       uint64                 `json:"gasLimit"   gencodec:"required"`
+	Difficulty     *big.Int               `json:"difficulty" gencodec:"required"`
+	Mixhash        common.Hash            `json:"mixHash"`
+	Coinbase       common.Address         `json:"coinbase"`
+	Alloc          GenesisAlloc           `json:"alloc"      gencodec:"required"`
+	ShardStateHash common.Hash            `json:"shardStateHash"`
+	ShardState     types.ShardState       `json:"shardState"`
 
 	// These fields are used for consensus tests. Please don't use them
 	// in actual genesis blocks.
@@ -149,8 +151,8 @@ func (e *GenesisMismatchError) Error() string {
 //     db has genesis    |  from DB           |  genesis (if compatible)
 //
 // The stored chain configuration will be updated if it is compatible (i.e. does not
-// specify a fork block below the local head block). In case of a conflict, the
-// error is a *params.ConfigCompatError and the new, unwritten config is returned.
+// specify a fork epoch below the local head epoch). In case of a conflict, the
+// error is a *hmyparams.ConfigCompatError and the new, unwritten config is returned.
 //
 // The returned chain configuration is never nil.
 func SetupGenesisBlock(db ethdb.Database, genesis *Genesis) (*params.ChainConfig, common.Hash, error) {
@@ -180,44 +182,51 @@ func SetupGenesisBlock(db ethdb.Database, genesis *Genesis) (*params.ChainConfig
 	}
 
 	// Get the existing chain configuration.
+	evmcfg := params.AllEthashProtocolChanges
+	if genesis != nil {
+		evmcfg = genesis.Config
+	}
 	newcfg := genesis.configOrDefault(stored)
 	storedcfg := rawdb.ReadChainConfig(db, stored)
 	if storedcfg == nil {
 		log.Warn("Found genesis block without chain config")
 		rawdb.WriteChainConfig(db, stored, newcfg)
-		return newcfg, stored, nil
+		return evmcfg, stored, nil
 	}
 	// Special case: don't change the existing config of a non-mainnet chain if no new
-	// config is supplied. These chains would get AllProtocolChanges (and a compat error)
-	// if we just continued here.
+	// config is supplied.
 	if genesis == nil && stored != params.MainnetGenesisHash {
-		return storedcfg, stored, nil
+		return evmcfg, stored, nil
 	}
 
 	// Check config compatibility and write the config. Compatibility errors
-	// are returned to the caller unless we're already at block zero.
-	height := rawdb.ReadHeaderNumber(db, rawdb.ReadHeadHeaderHash(db))
+	// are returned to the caller unless we're already at epoch zero.
+	head := rawdb.ReadHeadHeaderHash(db)
+	height := rawdb.ReadHeaderNumber(db, head)
 	if height == nil {
-		return newcfg, stored, fmt.Errorf("missing block number for head header hash")
+		return evmcfg, stored, fmt.Errorf("missing block number for head header hash")
+	}
+	header := rawdb.ReadHeader(db, head, *height)
+	if header == nil {
+		return evmcfg, stored, fmt.Errorf("missing head header")
 	}
-	compatErr := storedcfg.CheckCompatible(newcfg, *height)
-	if compatErr != nil && *height != 0 && compatErr.RewindTo != 0 {
-		return newcfg, stored, compatErr
+	epoch := header.Epoch.Uint64()
+	compatErr := storedcfg.CheckCompatible(newcfg, epoch)
+	if compatErr != nil && epoch != 0 && compatErr.RewindTo != 0 {
+		return evmcfg, stored, compatErr
 	}
 	rawdb.WriteChainConfig(db, stored, newcfg)
-	return newcfg, stored, nil
+	return evmcfg, stored, nil
 }
 
-func (g *Genesis) configOrDefault(ghash common.Hash) *params.ChainConfig {
+func (g *Genesis) configOrDefault(ghash common.Hash) *hmyparams.ChainConfig {
 	switch {
-	case g != nil:
-		return g.Config
+	case g != nil && g.ChainConfig != nil:
+		return g.ChainConfig
 	case ghash == params.MainnetGenesisHash:
-		return params.MainnetChainConfig
-	case ghash == params.TestnetGenesisHash:
-		return params.TestnetChainConfig
+		return hmyparams.MainnetChainConfig
 	default:
-		return params.AllEthashProtocolChanges
+		return hmyparams.TestnetChainConfig
 	}
 }
 
@@ -276,9 +285,9 @@ func (g *Genesis) Commit(db ethdb.Database) (*types.Block, error) {
 	rawdb.WriteHeadBlockHash(db, block.Hash())
 	rawdb.WriteHeadHeaderHash(db, block.Hash())
 
-	config := g.Config
+	config := g.ChainConfig
 	if config == nil {
-		config = params.AllEthashProtocolChanges
+		config = hmyparams.TestnetChainConfig
 	}
 	rawdb.WriteChainConfig(db, block.Hash(), config)
 	return block, nil
@@ -303,12 +312,13 @@ func GenesisBlockForTesting(db ethdb.Database, addr common.Address, balance *big
 // DefaultGenesisBlock returns the Ethereum main net genesis block.
 func DefaultGenesisBlock() *Genesis {
 	return &Genesis{
-		Config:     params.MainnetChainConfig,
-		Nonce:      66,
-		ExtraData:  hexutil.MustDecode("0x11bbe8db4e347b4e8c937c1c8370e4b5ed33adb3db69cbdb7a38e1e50b1b82fa"),
-		GasLimit:   5000,
-		Difficulty: big.NewInt(17179869184),
-		Alloc:      decodePrealloc("empty"),
+		Config:      params.MainnetChainConfig,
+		ChainConfig: hmyparams.MainnetChainConfig,
+		Nonce:       66,
+		ExtraData:   hexutil.MustDecode("0x11bbe8db4e347b4e8c937c1c8370e4b5ed33adb3db69cbdb7a38e1e50b1b82fa"),
+		GasLimit:    5000,
+		Difficulty:  big.NewInt(17179869184),
+		Alloc:       decodePrealloc("empty"),
 	}
 }
 
@@ -0,0 +1,345 @@
+package core
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/types"
+	common2 "github.com/harmony-one/harmony/internal/common"
+	shardingconfig "github.com/harmony-one/harmony/internal/configs/sharding"
+	"github.com/harmony-one/harmony/internal/ctxerror"
+	"github.com/harmony-one/harmony/internal/genesis"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
+)
+
+// NetworkGenesis specifies the genesis of a whole network: the chain
+// configuration, the shard layout, the initial committees and the initial
+// allocation of every shard.  It is the JSON document accepted by
+// "harmony init --genesis" and produced by "harmony dumpgenesis".
+type NetworkGenesis struct {
+	Config      *params.ChainConfig     `json:"config"`      // EVM rules
+	ChainConfig *hmyparams.ChainConfig  `json:"chainConfig"` // Harmony epoch fork schedule
+	Sharding    GenesisSharding         `json:"sharding"`
+	Committees  []GenesisCommittee      `json:"committees"`
+	Alloc       map[uint32]GenesisAlloc `json:"alloc"` // shard ID -> allocation
+	Timestamp   uint64                  `json:"timestamp"`
+	ExtraData   hexutil.Bytes           `json:"extraData"`
+	GasLimit    uint64                  `json:"gasLimit"`
+}
+
+// GenesisSharding specifies the shard layout of the genesis epoch.
+type GenesisSharding struct {
+	NumShards                       uint32 `json:"numShards"`
+	NumNodesPerShard                int    `json:"numNodesPerShard"`
+	NumHarmonyOperatedNodesPerShard int    `json:"numHarmonyOperatedNodesPerShard"`
+	BlocksPerEpoch                  uint64 `json:"blocksPerEpoch"`
+}
+
+// GenesisCommittee is the initial committee of one shard.  The first
+// NumHarmonyOperatedNodesPerShard nodes are operated by Harmony, the rest are
+// foundational nodes.
+type GenesisCommittee struct {
+	ShardID uint32        `json:"shardID"`
+	Nodes   []GenesisNode `json:"nodes"`
+}
+
+// GenesisNode is a member of a genesis committee.
+type GenesisNode struct {
+	Address      string `json:"address"`      // bech32 or hex account address
+	BlsPublicKey string `json:"blsPublicKey"` // hex-encoded serialized BLS public key
+}
+
+// ReadNetworkGenesis reads and validates a network genesis JSON file.
+func ReadNetworkGenesis(path string) (*NetworkGenesis, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, ctxerror.New("cannot read genesis file", "path", path).
+			WithCause(err)
+	}
+	ng := &NetworkGenesis{}
+	if err := json.Unmarshal(data, ng); err != nil {
+		return nil, ctxerror.New("cannot parse genesis file", "path", path).
+			WithCause(err)
+	}
+	if err := ng.Validate(); err != nil {
+		return nil, err
+	}
+	return ng, nil
+}
+
+// Validate checks that the network genesis is complete and self-consistent.
+func (ng *NetworkGenesis) Validate() error {
+	if ng.Config == nil {
+		return errGenesisNoConfig
+	}
+	if ng.ChainConfig == nil || ng.ChainConfig.ChainID == nil {
+		return ctxerror.New("genesis has no Harmony chain configuration")
+	}
+	if _, err := ng.instance(); err != nil {
+		return err
+	}
+	if _, err := ng.ShardState(); err != nil {
+		return err
+	}
+	for shardID := range ng.Alloc {
+		if shardID >= ng.Sharding.NumShards {
+			return ctxerror.New("allocation for unknown shard",
+				"shardID", shardID, "numShards", ng.Sharding.NumShards)
+		}
+	}
+	return nil
+}
+
+// ShardState returns the genesis committees as the epoch-0 shard state.
+func (ng *NetworkGenesis) ShardState() (types.ShardState, error) {
+	if uint32(len(ng.Committees)) != ng.Sharding.NumShards {
+		return nil, ctxerror.New("number of committees does not match number of shards",
+			"committees", len(ng.Committees), "numShards", ng.Sharding.NumShards)
+	}
+	shardState := types.ShardState{}
+	for i, committee := range ng.Committees {
+		if committee.ShardID != uint32(i) {
+			return nil, ctxerror.New("committees must be listed in shard order",
+				"index", i, "shardID", committee.ShardID)
+		}
+		if len(committee.Nodes) != ng.Sharding.NumNodesPerShard {
+			return nil, ctxerror.New("wrong committee size",
+				"shardID", committee.ShardID, "have", len(committee.Nodes),
+				"want", ng.Sharding.NumNodesPerShard)
+		}
+		com := types.Committee{ShardID: committee.ShardID}
+		for _, n := range committee.Nodes {
+			pubKey, err := parseGenesisBlsPublicKey(n.BlsPublicKey)
+			if err != nil {
+				return nil, ctxerror.New("invalid BLS public key",
+					"shardID", committee.ShardID, "address", n.Address).
+					WithCause(err)
+			}
+			com.NodeList = append(com.NodeList,
+				types.NodeID{EcdsaAddress: common2.ParseAddr(n.Address), BlsPublicKey: pubKey})
+		}
+		shardState = append(shardState, com)
+	}
+	return shardState, nil
+}
+
+// Schedule returns the sharding schedule described by the network genesis.
+func (ng *NetworkGenesis) Schedule() (shardingconfig.Schedule, error) {
+	instance, err := ng.instance()
+	if err != nil {
+		return nil, err
+	}
+	return shardingconfig.NewFixedSchedule(instance), nil
+}
+
+// instance returns the genesis sharding instance.  Harmony and foundational
+// node accounts are laid out the way GetInitShardState expects them, i.e. the
+// j-th node of shard i is at index i + j*numShards.
+func (ng *NetworkGenesis) instance() (shardingconfig.Instance, error) {
+	s := ng.Sharding
+	var hmyAccounts, fnAccounts []genesis.DeployAccount
+	if s.NumShards > 0 && uint32(len(ng.Committees)) == s.NumShards &&
+		s.NumHarmonyOperatedNodesPerShard >= 0 &&
+		s.NumHarmonyOperatedNodesPerShard <= s.NumNodesPerShard {
+		numShards := int(s.NumShards)
+		numFnNodes := s.NumNodesPerShard - s.NumHarmonyOperatedNodesPerShard
+		hmyAccounts = make([]genesis.DeployAccount, numShards*s.NumHarmonyOperatedNodesPerShard)
+		fnAccounts = make([]genesis.DeployAccount, numShards*numFnNodes)
+		for i, committee := range ng.Committees {
+			for j, n := range committee.Nodes {
+				account := genesis.DeployAccount{
+					Address:      n.Address,
+					BlsPublicKey: n.BlsPublicKey,
+					ShardID:      committee.ShardID,
+				}
+				switch {
+				case j < s.NumHarmonyOperatedNodesPerShard:
+					hmyAccounts[i+j*numShards] = account
+				case j < s.NumNodesPerShard:
+					fnAccounts[i+(j-s.NumHarmonyOperatedNodesPerShard)*numShards] = account
+				}
+			}
+		}
+	}
+	return shardingconfig.NewInstance(
+		s.NumShards, s.NumNodesPerShard, s.NumHarmonyOperatedNodesPerShard,
+		s.BlocksPerEpoch, hmyAccounts, fnAccounts)
+}
+
+// FindAccount returns the index and the genesis account of the given address
+// among the genesis committee members, or nil if the address is not a member.
+// Harmony-operated accounts come first; the index modulo the number of shards
+// is the shard of the account.
+func (ng *NetworkGenesis) FindAccount(address string) (int, *genesis.DeployAccount) {
+	instance, err := ng.instance()
+	if err != nil {
+		return 0, nil
+	}
+	addr := common2.ParseAddr(address)
+	for i, acc := range instance.HmyAccounts() {
+		if addr == common2.ParseAddr(acc.Address) {
+			return i, &acc
+		}
+	}
+	for i, acc := range instance.FnAccounts() {
+		if addr == common2.ParseAddr(acc.Address) {
+			return i + len(instance.HmyAccounts()), &acc
+		}
+	}
+	return 0, nil
+}
+
+// IsBlsPublicKeyWhiteListed returns true if the given hex-encoded BLS public
+// key belongs to a genesis committee member.
+func (ng *NetworkGenesis) IsBlsPublicKeyWhiteListed(blsPublicKey string) bool {
+	for _, committee := range ng.Committees {
+		for _, n := range committee.Nodes {
+			if n.BlsPublicKey == blsPublicKey {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ToGenesis returns the genesis block specification of the given shard.
+func (ng *NetworkGenesis) ToGenesis(shardID uint32) *Genesis {
+	// TODO: add ShardID into chainconfig and change ChainID to NetworkID
+	chainConfig := *ng.Config
+	chainConfig.ChainID = big.NewInt(int64(shardID)) // Use ChainID as piggybacked ShardID
+	alloc := ng.Alloc[shardID]
+	if alloc == nil {
+		alloc = GenesisAlloc{}
+	}
+	return &Genesis{
+		Config:      &chainConfig,
+		ChainConfig: ng.ChainConfig,
+		ShardID:     shardID,
+		Timestamp:   ng.Timestamp,
+		ExtraData:   ng.ExtraData,
+		GasLimit:    ng.GasLimit,
+		Alloc:       alloc,
+	}
+}
+
+// Commit writes the genesis shard state and genesis block of the given shard
+// into db.  Shard chains other than the beacon chain only store their own
+// committee.
+func (ng *NetworkGenesis) Commit(db ethdb.Database, shardID uint32) (*types.Block, error) {
+	shardState, err := ng.ShardState()
+	if err != nil {
+		return nil, err
+	}
+	if shardID != 0 {
+		// store only the local shard
+		c := shardState.FindCommitteeByID(shardID)
+		if c == nil {
+			return nil, ctxerror.New("cannot find local shard in genesis",
+				"shardID", shardID)
+		}
+		shardState = types.ShardState{*c}
+	}
+	if err := rawdb.WriteShardState(db, common.Big0, shardState); err != nil {
+		return nil, ctxerror.New("cannot store epoch shard state").WithCause(err)
+	}
+	return ng.ToGenesis(shardID).Commit(db)
+}
+
+// WriteStoredNetworkGenesis stores the custom network genesis ng alongside the
+// genesis block of the given hash, so that the node recovers the shard layout
+// of the network on restart.  Built-in networks are not stored; their sharding
+// schedule comes with the binary.
+func WriteStoredNetworkGenesis(db ethdb.Database, hash common.Hash, ng *NetworkGenesis) error {
+	data, err := json.Marshal(ng)
+	if err != nil {
+		return ctxerror.New("cannot encode network genesis").WithCause(err)
+	}
+	if err := rawdb.WriteNetworkGenesis(db, hash, data); err != nil {
+		return ctxerror.New("cannot store network genesis").WithCause(err)
+	}
+	return nil
+}
+
+// ReadStoredNetworkGenesis returns the network genesis stored in db along with
+// its genesis block, or nil if db was not initialized from a network genesis.
+func ReadStoredNetworkGenesis(db ethdb.Database) (*NetworkGenesis, error) {
+	hash := rawdb.ReadCanonicalHash(db, 0)
+	if hash == (common.Hash{}) {
+		return nil, nil
+	}
+	data := rawdb.ReadNetworkGenesis(db, hash)
+	if len(data) == 0 {
+		return nil, nil
+	}
+	ng := &NetworkGenesis{}
+	if err := json.Unmarshal(data, ng); err != nil {
+		return nil, ctxerror.New("cannot decode stored network genesis",
+			"hash", hash).WithCause(err)
+	}
+	return ng, nil
+}
+
+// NewNetworkGenesis assembles a network genesis from the epoch-0 instance of
+// the given sharding schedule, using the same committee layout as
+// GetInitShardState.  Every shard gets a copy of alloc.
+func NewNetworkGenesis(
+	config *params.ChainConfig, chainConfig *hmyparams.ChainConfig,
+	schedule shardingconfig.Schedule, alloc GenesisAlloc,
+) *NetworkGenesis {
+	instance := schedule.InstanceForEpoch(big.NewInt(GenesisEpoch))
+	numShards := int(instance.NumShards())
+	numHmyNodes := instance.NumHarmonyOperatedNodesPerShard()
+	hmyAccounts := instance.HmyAccounts()
+	fnAccounts := instance.FnAccounts()
+	ng := &NetworkGenesis{
+		Config:      config,
+		ChainConfig: chainConfig,
+		Sharding: GenesisSharding{
+			NumShards:                       instance.NumShards(),
+			NumNodesPerShard:                instance.NumNodesPerShard(),
+			NumHarmonyOperatedNodesPerShard: numHmyNodes,
+			BlocksPerEpoch:                  instance.BlocksPerEpoch(),
+		},
+		Alloc: make(map[uint32]GenesisAlloc, numShards),
+	}
+	for i := 0; i < numShards; i++ {
+		committee := GenesisCommittee{ShardID: uint32(i)}
+		for j := 0; j < instance.NumNodesPerShard(); j++ {
+			var account genesis.DeployAccount
+			if j < numHmyNodes {
+				account = hmyAccounts[i+j*numShards]
+			} else {
+				account = fnAccounts[i+(j-numHmyNodes)*numShards]
+			}
+			committee.Nodes = append(committee.Nodes, GenesisNode{
+				Address:      account.Address,
+				BlsPublicKey: account.BlsPublicKey,
+			})
+		}
+		ng.Committees = append(ng.Committees, committee)
+		ng.Alloc[uint32(i)] = alloc
+	}
+	return ng
+}
+
+func parseGenesisBlsPublicKey(s string) (types.BlsPublicKey, error) {
+	var pubKey types.BlsPublicKey
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return pubKey, err
+	}
+	if len(b) != len(pubKey) {
+		return pubKey, ctxerror.New("BLS public key size mismatch",
+			"expected", len(pubKey), "actual", len(b))
+	}
+	copy(pubKey[:], b)
+	return pubKey, nil
+}
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/harmony-one/harmony/core/rawdb"
+	shardingconfig "github.com/harmony-one/harmony/internal/configs/sharding"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
+)
+
+func testNetworkGenesis() *NetworkGenesis {
+	alloc := GenesisAlloc{
+		common.HexToAddress("0x1"): {Balance: big.NewInt(100)},
+	}
+	return NewNetworkGenesis(params.TestChainConfig, hmyparams.DevnetChainConfig,
+		shardingconfig.DevnetSchedule, alloc)
+}
+
+func TestNetworkGenesisJSONRoundTrip(t *testing.T) {
+	ng := testNetworkGenesis()
+	data, err := json.Marshal(ng)
+	if err != nil {
+		t.Fatalf("cannot encode network genesis: %v", err)
+	}
+	decoded := &NetworkGenesis{}
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("cannot decode network genesis: %v", err)
+	}
+	if err := decoded.Validate(); err != nil {
+		t.Fatalf("decoded network genesis is invalid: %v", err)
+	}
+	if decoded.Sharding != ng.Sharding {
+		t.Errorf("Sharding = %+v, expected = %+v", decoded.Sharding, ng.Sharding)
+	}
+	for shardID := uint32(0); shardID < ng.Sharding.NumShards; shardID++ {
+		want := ng.ToGenesis(shardID).ToBlock(ethdb.NewMemDatabase()).Hash()
+		got := decoded.ToGenesis(shardID).ToBlock(ethdb.NewMemDatabase()).Hash()
+		if got != want {
+			t.Errorf("shard %d genesis hash = %x, expected = %x", shardID, got, want)
+		}
+	}
+}
+
+func TestNetworkGenesisCommit(t *testing.T) {
+	ng := testNetworkGenesis()
+	db := ethdb.NewMemDatabase()
+	block, err := ng.Commit(db, 1)
+	if err != nil {
+		t.Fatalf("cannot commit network genesis: %v", err)
+	}
+	if block.ShardID() != 1 {
+		t.Errorf("ShardID = %v, expected = %v", block.ShardID(), 1)
+	}
+	shardState, err := rawdb.ReadShardState(db, common.Big0)
+	if err != nil {
+		t.Fatalf("cannot read genesis shard state: %v", err)
+	}
+	if len(shardState) != 1 || shardState[0].ShardID != 1 {
+		t.Errorf("non-beacon shard should store only its own committee, got %v", shardState)
+	}
+	if stored, err := ReadStoredNetworkGenesis(db); err != nil || stored != nil {
+		t.Fatalf("network genesis stored by Commit: %v, %v", stored, err)
+	}
+	if err := WriteStoredNetworkGenesis(db, block.Hash(), ng); err != nil {
+		t.Fatalf("cannot store network genesis: %v", err)
+	}
+	stored, err := ReadStoredNetworkGenesis(db)
+	if err != nil || stored == nil {
+		t.Fatalf("cannot read stored network genesis: %v", err)
+	}
+	schedule, err := stored.Schedule()
+	if err != nil {
+		t.Fatalf("stored network genesis has invalid schedule: %v", err)
+	}
+	if n := schedule.InstanceForEpoch(big.NewInt(GenesisEpoch)).NumShards(); n != ng.Sharding.NumShards {
+		t.Errorf("NumShards = %v, expected = %v", n, ng.Sharding.NumShards)
+	}
+}
+
+func TestNetworkGenesisFindAccount(t *testing.T) {
+	ng := testNetworkGenesis()
+	for _, committee := range ng.Committees {
+		for _, n := range committee.Nodes {
+			index, account := ng.FindAccount(n.Address)
+			if account == nil {
+				t.Errorf("cannot find account %v", n.Address)
+				continue
+			}
+			if shardID := uint32(index) % ng.Sharding.NumShards; shardID != committee.ShardID {
+				t.Errorf("account %v: shard = %v, expected = %v", n.Address, shardID, committee.ShardID)
+			}
+		}
+	}
+	if _, account := ng.FindAccount("0x0000000000000000000000000000000000000001"); account != nil {
+		t.Error("non-member account should not be found")
+	}
+}
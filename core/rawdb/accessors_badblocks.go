@@ -0,0 +1,96 @@
+package rawdb
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+// BadBlock is a block that failed validation, along with why it failed and
+// where it came from.
+type BadBlock struct {
+	Block  *types.Block
+	Error  string // validation error
+	Source string // leader or peer that sent the block, if known
+	Time   uint64 // unix time at which the block was rejected
+}
+
+// ReadBadBlockHashes retrieves the hashes of the stored bad blocks, oldest first.
+func ReadBadBlockHashes(db DatabaseReader) []common.Hash {
+	data, _ := db.Get(badBlocksKey)
+	if len(data) == 0 {
+		return nil
+	}
+	var hashes []common.Hash
+	if err := rlp.DecodeBytes(data, &hashes); err != nil {
+		log.Error("Invalid bad block list RLP", "err", err)
+		return nil
+	}
+	return hashes
+}
+
+// ReadBadBlock retrieves the bad block with the given hash.
+func ReadBadBlock(db DatabaseReader, hash common.Hash) *BadBlock {
+	data, _ := db.Get(badBlockKey(hash))
+	if len(data) == 0 {
+		return nil
+	}
+	badBlock := new(BadBlock)
+	if err := rlp.DecodeBytes(data, badBlock); err != nil {
+		log.Error("Invalid bad block RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return badBlock
+}
+
+// ReadAllBadBlocks retrieves all stored bad blocks, newest first.
+func ReadAllBadBlocks(db DatabaseReader) []*BadBlock {
+	hashes := ReadBadBlockHashes(db)
+	badBlocks := make([]*BadBlock, 0, len(hashes))
+	for i := len(hashes) - 1; i >= 0; i-- {
+		if badBlock := ReadBadBlock(db, hashes[i]); badBlock != nil {
+			badBlocks = append(badBlocks, badBlock)
+		}
+	}
+	return badBlocks
+}
+
+// WriteBadBlock stores a bad block, evicting the oldest ones so that at most
+// limit bad blocks are kept.
+func WriteBadBlock(db interface {
+	DatabaseReader
+	DatabaseWriter
+	DatabaseDeleter
+}, badBlock *BadBlock, limit int) {
+	hash := badBlock.Block.Hash()
+	data, err := rlp.EncodeToBytes(badBlock)
+	if err != nil {
+		log.Crit("Failed to RLP encode bad block", "err", err)
+	}
+	if err := db.Put(badBlockKey(hash), data); err != nil {
+		log.Crit("Failed to store bad block", "err", err)
+	}
+	hashes := ReadBadBlockHashes(db)
+	for i, h := range hashes {
+		if h == hash {
+			// Seen again; move it to the end of the list.
+			hashes = append(hashes[:i], hashes[i+1:]...)
+			break
+		}
+	}
+	hashes = append(hashes, hash)
+	for len(hashes) > limit {
+		if err := db.Delete(badBlockKey(hashes[0])); err != nil {
+			log.Crit("Failed to delete bad block", "err", err)
+		}
+		hashes = hashes[1:]
+	}
+	data, err = rlp.EncodeToBytes(hashes)
+	if err != nil {
+		log.Crit("Failed to RLP encode bad block list", "err", err)
+	}
+	if err := db.Put(badBlocksKey, data); err != nil {
+		log.Crit("Failed to store bad block list", "err", err)
+	}
+}
@@ -0,0 +1,46 @@
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+func TestBadBlockStorage(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	if badBlocks := ReadAllBadBlocks(db); len(badBlocks) != 0 {
+		t.Fatalf("non existent bad blocks returned: %v", badBlocks)
+	}
+	var blocks []*types.Block
+	for i := 0; i < 4; i++ {
+		blocks = append(blocks, types.NewBlockWithHeader(&types.Header{Number: big.NewInt(int64(i))}))
+	}
+	for i, block := range blocks[:3] {
+		WriteBadBlock(db, &BadBlock{Block: block, Error: "invalid", Source: "peer 127.0.0.1:6000", Time: uint64(i)}, 3)
+	}
+	badBlock := ReadBadBlock(db, blocks[1].Hash())
+	if badBlock == nil {
+		t.Fatalf("stored bad block not found")
+	}
+	if badBlock.Block.Hash() != blocks[1].Hash() || badBlock.Error != "invalid" ||
+		badBlock.Source != "peer 127.0.0.1:6000" || badBlock.Time != 1 {
+		t.Errorf("bad block = %+v, expected block %x", badBlock, blocks[1].Hash())
+	}
+
+	// Writing past the limit evicts the oldest bad block.
+	WriteBadBlock(db, &BadBlock{Block: blocks[3], Error: "invalid"}, 3)
+	if ReadBadBlock(db, blocks[0].Hash()) != nil {
+		t.Errorf("oldest bad block not evicted")
+	}
+	badBlocks := ReadAllBadBlocks(db)
+	if len(badBlocks) != 3 {
+		t.Fatalf("len(badBlocks) = %v, expected = %v", len(badBlocks), 3)
+	}
+	for i, badBlock := range badBlocks {
+		if want := blocks[3-i].Hash(); badBlock.Block.Hash() != want {
+			t.Errorf("badBlocks[%d] = %x, expected = %x", i, badBlock.Block.Hash(), want)
+		}
+	}
+}
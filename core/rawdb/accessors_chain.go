@@ -143,7 +143,8 @@ func HasHeader(db DatabaseReader, hash common.Hash, number uint64) bool {
 	return true
 }
 
-// ReadHeader retrieves the block header corresponding to the hash.
+// ReadHeader retrieves the block header corresponding to the hash. Headers of
+// any known version are decoded in the layout of their version.
 func ReadHeader(db DatabaseReader, hash common.Hash, number uint64) *types.Header {
 	data := ReadHeaderRLP(db, hash, number)
 	if len(data) == 0 {
@@ -157,8 +158,8 @@ func ReadHeader(db DatabaseReader, hash common.Hash, number uint64) *types.Heade
 	return header
 }
 
-// WriteHeader stores a block header into the database and also stores the hash-
-// to-number mapping.
+// WriteHeader stores a block header, encoded in the layout of its version, into
+// the database and also stores the hash-to-number mapping.
 func WriteHeader(db DatabaseWriter, header *types.Header) {
 	// Write the hash -> number mapping
 	var (
@@ -17,6 +17,8 @@
 package rawdb
 
 import (
+	"encoding/binary"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -92,6 +94,121 @@ func ReadReceipt(db DatabaseReader, hash common.Hash) (*types.Receipt, common.Ha
 	return receipts[receiptIndex], blockHash, blockNumber, receiptIndex
 }
 
+// ReadAddressTxCount retrieves the number of transactions sent from or to the
+// given address that are in the transaction history index.
+func ReadAddressTxCount(db DatabaseReader, addr common.Address) uint64 {
+	data, _ := db.Get(addressTxCountKey(addr))
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// ReadAddressTxEntry retrieves the positional metadata of the seq-th
+// transaction, in chain order, sent from or to the given address.
+func ReadAddressTxEntry(db DatabaseReader, addr common.Address, seq uint64) *TxLookupEntry {
+	data, _ := db.Get(addressTxKey(addr, seq))
+	if len(data) == 0 {
+		return nil
+	}
+	entry := new(TxLookupEntry)
+	if err := rlp.DecodeBytes(data, entry); err != nil {
+		log.Error("Invalid address transaction entry RLP", "address", addr, "seq", seq, "err", err)
+		return nil
+	}
+	return entry
+}
+
+// WriteAddressTxHistory appends every transaction of a canonical block to the
+// transaction history of its sender and recipient.  Counts are read from db
+// and the new entries are written to batch.  counts holds the history lengths
+// already updated in batch, if any, and must be shared by every block written
+// to the same batch.
+func WriteAddressTxHistory(db DatabaseReader, batch DatabaseWriter, block *types.Block, signer types.Signer, counts map[common.Address]uint64) {
+	if counts == nil {
+		counts = make(map[common.Address]uint64)
+	}
+	for i, tx := range block.Transactions() {
+		entry := TxLookupEntry{
+			BlockHash:  block.Hash(),
+			BlockIndex: block.NumberU64(),
+			Index:      uint64(i),
+		}
+		data, err := rlp.EncodeToBytes(entry)
+		if err != nil {
+			log.Crit("Failed to encode address transaction entry", "err", err)
+		}
+		for _, addr := range txAddresses(tx, signer) {
+			count, ok := counts[addr]
+			if !ok {
+				count = ReadAddressTxCount(db, addr)
+			}
+			if err := batch.Put(addressTxKey(addr, count), data); err != nil {
+				log.Crit("Failed to store address transaction entry", "err", err)
+			}
+			counts[addr] = count + 1
+		}
+	}
+	for addr, count := range counts {
+		if err := batch.Put(addressTxCountKey(addr), encodeBlockNumber(count)); err != nil {
+			log.Crit("Failed to store address transaction count", "err", err)
+		}
+	}
+}
+
+// DeleteAddressTxHistory removes the transactions of a block that is no longer
+// canonical from the transaction history of their senders and recipients.
+// Blocks must be removed newest first.  Entries that do not belong to the
+// block, e.g. because the block was written while the index was off, are kept.
+// counts is shared with WriteAddressTxHistory across the blocks of a batch.
+func DeleteAddressTxHistory(db DatabaseReader, batch interface {
+	DatabaseWriter
+	DatabaseDeleter
+}, block *types.Block, signer types.Signer, counts map[common.Address]uint64) {
+	if counts == nil {
+		counts = make(map[common.Address]uint64)
+	}
+	txs := block.Transactions()
+	for i := len(txs) - 1; i >= 0; i-- {
+		for _, addr := range txAddresses(txs[i], signer) {
+			count, ok := counts[addr]
+			if !ok {
+				count = ReadAddressTxCount(db, addr)
+			}
+			if count == 0 {
+				continue
+			}
+			entry := ReadAddressTxEntry(db, addr, count-1)
+			if entry == nil || entry.BlockHash != block.Hash() || entry.Index != uint64(i) {
+				continue
+			}
+			if err := batch.Delete(addressTxKey(addr, count-1)); err != nil {
+				log.Crit("Failed to delete address transaction entry", "err", err)
+			}
+			counts[addr] = count - 1
+		}
+	}
+	for addr, count := range counts {
+		if err := batch.Put(addressTxCountKey(addr), encodeBlockNumber(count)); err != nil {
+			log.Crit("Failed to store address transaction count", "err", err)
+		}
+	}
+}
+
+// txAddresses returns the sender and the recipient of tx, without duplicates.
+func txAddresses(tx *types.Transaction, signer types.Signer) []common.Address {
+	var addrs []common.Address
+	if from, err := types.Sender(signer, tx); err == nil {
+		addrs = append(addrs, from)
+	} else {
+		log.Warn("Cannot index transaction sender", "hash", tx.Hash(), "err", err)
+	}
+	if to := tx.To(); to != nil && (len(addrs) == 0 || *to != addrs[0]) {
+		addrs = append(addrs, *to)
+	}
+	return addrs
+}
+
 // ReadBloomBits retrieves the compressed bloom bit vector belonging to the given
 // section and bit index from the.
 func ReadBloomBits(db DatabaseReader, bit uint, section uint64, head common.Hash) ([]byte, error) {
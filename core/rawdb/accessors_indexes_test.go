@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/harmony-one/harmony/core/types"
 )
@@ -66,3 +67,76 @@ func TestLookupStorage(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the address transaction history is appended and rolled back.
+func TestAddressTxHistory(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.BytesToAddress([]byte{0x11})
+	signer := types.NewHarmonySigner(big.NewInt(2), 0)
+
+	newBlock := func(number int64, nonces ...uint64) *types.Block {
+		var txs []*types.Transaction
+		for _, nonce := range nonces {
+			tx, err := types.SignTx(types.NewTransaction(nonce, to, 0, big.NewInt(1), 21000, big.NewInt(1), nil), signer, key)
+			if err != nil {
+				t.Fatalf("cannot sign transaction: %v", err)
+			}
+			txs = append(txs, tx)
+		}
+		return types.NewBlock(&types.Header{Number: big.NewInt(number)}, txs, nil)
+	}
+	block1 := newBlock(1, 0, 1)
+	block2 := newBlock(2, 2)
+
+	WriteAddressTxHistory(db, db, block1, signer, nil)
+	WriteAddressTxHistory(db, db, block2, signer, nil)
+	for _, addr := range []common.Address{from, to} {
+		if count := ReadAddressTxCount(db, addr); count != 3 {
+			t.Fatalf("count of %x = %d, expected = %d", addr, count, 3)
+		}
+		entry := ReadAddressTxEntry(db, addr, 2)
+		if entry == nil || entry.BlockHash != block2.Hash() || entry.Index != 0 {
+			t.Fatalf("entry #2 of %x = %+v, expected block %x index 0", addr, entry, block2.Hash())
+		}
+	}
+
+	// Rolling back a block that is not the latest one keeps the history intact.
+	DeleteAddressTxHistory(db, db, block1, signer, nil)
+	if count := ReadAddressTxCount(db, from); count != 3 {
+		t.Fatalf("count after out-of-order rollback = %d, expected = %d", count, 3)
+	}
+	DeleteAddressTxHistory(db, db, block2, signer, nil)
+	DeleteAddressTxHistory(db, db, block1, signer, nil)
+	for _, addr := range []common.Address{from, to} {
+		if count := ReadAddressTxCount(db, addr); count != 0 {
+			t.Errorf("count of %x after rollback = %d, expected = %d", addr, count, 0)
+		}
+		if entry := ReadAddressTxEntry(db, addr, 0); entry != nil {
+			t.Errorf("entry #0 of %x after rollback = %+v, expected none", addr, entry)
+		}
+	}
+
+	// Rolling back and rewriting history in one batch, as a reorg does.
+	WriteAddressTxHistory(db, db, block1, signer, nil)
+	WriteAddressTxHistory(db, db, block2, signer, nil)
+	fork := newBlock(2, 2, 3)
+	batch := db.NewBatch()
+	counts := make(map[common.Address]uint64)
+	DeleteAddressTxHistory(db, batch, block2, signer, counts)
+	WriteAddressTxHistory(db, batch, fork, signer, counts)
+	if count := ReadAddressTxCount(db, from); count != 3 {
+		t.Fatalf("count before batch write = %d, expected = %d", count, 3)
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatalf("cannot write batch: %v", err)
+	}
+	if count := ReadAddressTxCount(db, from); count != 4 {
+		t.Fatalf("count after reorg = %d, expected = %d", count, 4)
+	}
+	entry := ReadAddressTxEntry(db, from, 3)
+	if entry == nil || entry.BlockHash != fork.Hash() || entry.Index != 1 {
+		t.Errorf("entry #3 of %x = %+v, expected block %x index 1", from, entry, fork.Hash())
+	}
+}
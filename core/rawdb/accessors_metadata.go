@@ -21,8 +21,8 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
-	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/harmony/internal/params"
 )
 
 // ReadDatabaseVersion retrieves the version number of the database.
@@ -43,7 +43,8 @@ func WriteDatabaseVersion(db DatabaseWriter, version int) {
 	}
 }
 
-// ReadChainConfig retrieves the consensus settings based on the given genesis hash.
+// ReadChainConfig retrieves the Harmony chain settings, including the epoch
+// fork schedule, based on the given genesis hash.
 func ReadChainConfig(db DatabaseReader, hash common.Hash) *params.ChainConfig {
 	data, _ := db.Get(configKey(hash))
 	if len(data) == 0 {
@@ -71,6 +72,19 @@ func WriteChainConfig(db DatabaseWriter, hash common.Hash, cfg *params.ChainConf
 	}
 }
 
+// ReadNetworkGenesis retrieves the JSON-encoded network genesis the chain
+// with the given genesis hash was initialized from.
+func ReadNetworkGenesis(db DatabaseReader, hash common.Hash) []byte {
+	data, _ := db.Get(networkGenesisKey(hash))
+	return data
+}
+
+// WriteNetworkGenesis stores the JSON-encoded network genesis of the chain
+// with the given genesis hash.
+func WriteNetworkGenesis(db DatabaseWriter, hash common.Hash, data []byte) error {
+	return db.Put(networkGenesisKey(hash), data)
+}
+
 // ReadPreimage retrieves a single preimage of the provided hash.
 func ReadPreimage(db DatabaseReader, hash common.Hash) []byte {
 	data, _ := db.Get(preimageKey(hash))
@@ -0,0 +1,186 @@
+package rawdb
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+
+	"github.com/harmony-one/harmony/internal/ctxerror"
+)
+
+// DatabaseIteratee is a database that can iterate over its whole keyspace,
+// such as *ethdb.LDBDatabase.
+type DatabaseIteratee interface {
+	NewIterator() iterator.Iterator
+}
+
+// DatabaseStat is the number and total size of the entries of one category of
+// database keys.
+type DatabaseStat struct {
+	Category string
+	Count    uint64
+	Size     uint64 // total size of keys and values, in bytes
+}
+
+// Categories of database keys reported by InspectDatabase.
+const (
+	statHeaders           = "Headers"
+	statTotalDifficulties = "Total difficulties"
+	statCanonicalHashes   = "Canonical hashes"
+	statHeaderNumbers     = "Header numbers"
+	statBodies            = "Bodies"
+	statReceipts          = "Receipts"
+	statTxLookups         = "Tx lookups"
+	statBloomBits         = "Bloom bits"
+	statShardStates       = "Shard states"
+	statEpochBlockNumbers = "Epoch block numbers"
+	statAddressTxHistory  = "Address tx history"
+	statTrieNodes         = "Trie nodes and code"
+	statPreimages         = "Preimages"
+	statChainIndexers     = "Chain indexers"
+	statConfigs           = "Configs and genesis"
+	statBadBlocks         = "Bad blocks"
+	statMetadata          = "Metadata"
+	statUnaccounted       = "Unaccounted"
+)
+
+// Lengths of the fixed-size keys in schema.go.
+const (
+	headerKeyLength         = 1 + 8 + common.HashLength
+	bloomBitsKeyLength      = 1 + 2 + 8 + common.HashLength
+	addressTxKeyLength      = 2 + common.AddressLength + 8
+	addressTxCountKeyLength = 2 + common.AddressLength
+)
+
+var statCategories = []string{
+	statHeaders, statTotalDifficulties, statCanonicalHashes, statHeaderNumbers,
+	statBodies, statReceipts, statTxLookups, statBloomBits, statShardStates,
+	statEpochBlockNumbers, statAddressTxHistory, statTrieNodes, statPreimages,
+	statChainIndexers, statConfigs, statBadBlocks, statMetadata, statUnaccounted,
+}
+
+var metadataKeys = [][]byte{
+	databaseVerisionKey, headHeaderKey, headBlockKey, headFastBlockKey,
+	fastTrieProgressKey, badBlocksKey,
+}
+
+// categorizeKey returns the category of a database key, based on the layout
+// described in schema.go. Trie nodes and contract code are keyed by their bare
+// 32-byte hash, which may start with any prefix, so they are checked before
+// the prefixed keys. Longer, more specific prefixes are checked first as some
+// of them start with the single-byte prefixes.
+func categorizeKey(key []byte) string {
+	for _, metadataKey := range metadataKeys {
+		if bytes.Equal(key, metadataKey) {
+			return statMetadata
+		}
+	}
+	switch {
+	case len(key) == common.HashLength:
+		return statTrieNodes
+	case bytes.HasPrefix(key, badBlockPrefix):
+		return statBadBlocks
+	case bytes.HasPrefix(key, networkGenesisPrefix), bytes.HasPrefix(key, configPrefix),
+		bytes.HasPrefix(key, legacyConfigPrefix):
+		return statConfigs
+	case bytes.HasPrefix(key, epochBlockNumberPrefix):
+		return statEpochBlockNumbers
+	case bytes.HasPrefix(key, preimagePrefix) && len(key) == len(preimagePrefix)+common.HashLength:
+		return statPreimages
+	case bytes.HasPrefix(key, BloomBitsIndexPrefix):
+		return statChainIndexers
+	case bytes.HasPrefix(key, shardStatePrefix):
+		return statShardStates
+	case bytes.HasPrefix(key, addressTxPrefix) && len(key) == addressTxKeyLength,
+		bytes.HasPrefix(key, addressTxCountPrefix) && len(key) == addressTxCountKeyLength:
+		return statAddressTxHistory
+	case bytes.HasPrefix(key, headerPrefix) && len(key) == headerKeyLength:
+		return statHeaders
+	case bytes.HasPrefix(key, headerPrefix) && len(key) == headerKeyLength+len(headerTDSuffix) && bytes.HasSuffix(key, headerTDSuffix):
+		return statTotalDifficulties
+	case bytes.HasPrefix(key, headerPrefix) && len(key) == 1+8+len(headerHashSuffix) && bytes.HasSuffix(key, headerHashSuffix):
+		return statCanonicalHashes
+	case bytes.HasPrefix(key, headerNumberPrefix) && len(key) == 1+common.HashLength:
+		return statHeaderNumbers
+	case bytes.HasPrefix(key, blockBodyPrefix) && len(key) == headerKeyLength:
+		return statBodies
+	case bytes.HasPrefix(key, blockReceiptsPrefix) && len(key) == headerKeyLength:
+		return statReceipts
+	case bytes.HasPrefix(key, txLookupPrefix) && len(key) == 1+common.HashLength:
+		return statTxLookups
+	case bytes.HasPrefix(key, bloomBitsPrefix) && len(key) == bloomBitsKeyLength:
+		return statBloomBits
+	}
+	return statUnaccounted
+}
+
+// InspectDatabase walks the whole keyspace of db and returns the number and
+// size of its entries per category, in a fixed order.
+func InspectDatabase(db DatabaseIteratee) ([]DatabaseStat, error) {
+	stats := make(map[string]*DatabaseStat, len(statCategories))
+	for _, category := range statCategories {
+		stats[category] = &DatabaseStat{Category: category}
+	}
+	it := db.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		stat := stats[categorizeKey(it.Key())]
+		stat.Count++
+		stat.Size += uint64(len(it.Key()) + len(it.Value()))
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	result := make([]DatabaseStat, len(statCategories))
+	for i, category := range statCategories {
+		result[i] = *stats[category]
+	}
+	return result, nil
+}
+
+// CheckCanonicalChain verifies that the canonical chain is continuous from
+// the genesis block up to the head block: every block number has a canonical
+// hash, a header whose parent is the previous canonical block, and a body.
+// It returns the number of blocks checked.
+func CheckCanonicalChain(db DatabaseReader) (uint64, error) {
+	headHash := ReadHeadBlockHash(db)
+	if headHash == (common.Hash{}) {
+		return 0, ctxerror.New("head block hash not found")
+	}
+	headNumber := ReadHeaderNumber(db, headHash)
+	if headNumber == nil {
+		return 0, ctxerror.New("number of head block not found", "hash", headHash)
+	}
+	var parentHash common.Hash
+	for number := uint64(0); number <= *headNumber; number++ {
+		hash := ReadCanonicalHash(db, number)
+		if hash == (common.Hash{}) {
+			return number, ctxerror.New("canonical hash not found", "number", number)
+		}
+		header := ReadHeader(db, hash, number)
+		if header == nil {
+			return number, ctxerror.New("header not found", "number", number, "hash", hash)
+		}
+		if number > 0 && header.ParentHash != parentHash {
+			return number, ctxerror.New("parent is not the previous canonical block",
+				"number", number,
+				"hash", hash,
+				"parentHash", header.ParentHash,
+				"canonicalParentHash", parentHash,
+			)
+		}
+		if !HasBody(db, hash, number) {
+			return number, ctxerror.New("body not found", "number", number, "hash", hash)
+		}
+		parentHash = hash
+	}
+	if parentHash != headHash {
+		return *headNumber + 1, ctxerror.New("head block is not canonical",
+			"headHash", headHash,
+			"number", *headNumber,
+			"canonicalHash", parentHash,
+		)
+	}
+	return *headNumber + 1, nil
+}
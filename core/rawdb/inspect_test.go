@@ -0,0 +1,70 @@
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+func TestCategorizeKey(t *testing.T) {
+	hash := common.HexToHash("0x1234")
+	address := common.HexToAddress("0x5678")
+	tests := []struct {
+		key  []byte
+		want string
+	}{
+		{headerKey(1, hash), statHeaders},
+		{headerTDKey(1, hash), statTotalDifficulties},
+		{headerHashKey(1), statCanonicalHashes},
+		{headerNumberKey(hash), statHeaderNumbers},
+		{blockBodyKey(1, hash), statBodies},
+		{blockReceiptsKey(1, hash), statReceipts},
+		{txLookupKey(hash), statTxLookups},
+		{bloomBitsKey(1, 1, hash), statBloomBits},
+		{shardStateKey(big.NewInt(1)), statShardStates},
+		{epochBlockNumberKey(big.NewInt(1)), statEpochBlockNumbers},
+		{addressTxKey(address, 1), statAddressTxHistory},
+		{addressTxCountKey(address), statAddressTxHistory},
+		{hash.Bytes(), statTrieNodes},
+		{append([]byte("iB"), hash[2:]...), statTrieNodes},
+		{append([]byte("ss"), hash[2:]...), statTrieNodes},
+		{preimageKey(hash), statPreimages},
+		{configKey(hash), statConfigs},
+		{append(legacyConfigPrefix, hash.Bytes()...), statConfigs},
+		{networkGenesisKey(hash), statConfigs},
+		{badBlockKey(hash), statBadBlocks},
+		{badBlocksKey, statMetadata},
+		{headBlockKey, statMetadata},
+		{[]byte("unknown"), statUnaccounted},
+	}
+	for _, test := range tests {
+		if got := categorizeKey(test.key); got != test.want {
+			t.Errorf("categorizeKey(%q) = %v, expected = %v", test.key, got, test.want)
+		}
+	}
+}
+
+func TestCheckCanonicalChain(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	var blocks []*types.Block
+	parentHash := common.Hash{}
+	for i := 0; i < 3; i++ {
+		block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(int64(i)), ParentHash: parentHash})
+		WriteBlock(db, block)
+		WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		blocks = append(blocks, block)
+		parentHash = block.Hash()
+	}
+	WriteHeadBlockHash(db, blocks[2].Hash())
+	if n, err := CheckCanonicalChain(db); err != nil || n != 3 {
+		t.Errorf("CheckCanonicalChain() = %v, %v, expected = 3, nil", n, err)
+	}
+
+	DeleteBody(db, blocks[1].Hash(), 1)
+	if n, err := CheckCanonicalChain(db); err == nil || n != 1 {
+		t.Errorf("CheckCanonicalChain() = %v, %v, expected a missing body at 1", n, err)
+	}
+}
@@ -42,6 +42,9 @@ var (
 	// fastTrieProgressKey tracks the number of trie entries imported during fast sync.
 	fastTrieProgressKey = []byte("TrieSync")
 
+	// badBlocksKey tracks the hashes of the stored bad blocks, oldest first.
+	badBlocksKey = []byte("BadBlocks")
+
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
 	headerTDSuffix     = []byte("t") // headerPrefix + num (uint64 big endian) + hash + headerTDSuffix -> td
@@ -56,8 +59,18 @@ var (
 
 	shardStatePrefix = []byte("ss") // shardStatePrefix + num (uint64 big endian) + hash -> shardState
 
-	preimagePrefix = []byte("secure-key-")      // preimagePrefix + hash -> preimage
-	configPrefix   = []byte("ethereum-config-") // config prefix for the db
+	addressTxCountPrefix = []byte("ac") // addressTxCountPrefix + address -> number of indexed transactions (uint64 big endian)
+	addressTxPrefix      = []byte("at") // addressTxPrefix + address + seq (uint64 big endian) -> transaction lookup metadata
+
+	preimagePrefix = []byte("secure-key-")     // preimagePrefix + hash -> preimage
+	configPrefix   = []byte("harmony-config-") // configPrefix + hash -> Harmony chain config JSON
+
+	// legacyConfigPrefix keys the go-ethereum chain config that earlier
+	// releases stored with the shard ID as chain ID. It is no longer read.
+	legacyConfigPrefix = []byte("ethereum-config-")
+
+	networkGenesisPrefix = []byte("harmony-network-genesis-") // networkGenesisPrefix + hash -> network genesis JSON
+	badBlockPrefix       = []byte("harmony-bad-block-")       // badBlockPrefix + hash -> bad block, error and source
 
 	// epochBlockNumberPrefix + epoch (big.Int.Bytes())
 	// -> epoch block number (big.Int.Bytes())
@@ -120,6 +133,16 @@ func txLookupKey(hash common.Hash) []byte {
 	return append(txLookupPrefix, hash.Bytes()...)
 }
 
+// addressTxCountKey = addressTxCountPrefix + address
+func addressTxCountKey(addr common.Address) []byte {
+	return append(addressTxCountPrefix, addr.Bytes()...)
+}
+
+// addressTxKey = addressTxPrefix + address + seq (uint64 big endian)
+func addressTxKey(addr common.Address, seq uint64) []byte {
+	return append(append(addressTxPrefix, addr.Bytes()...), encodeBlockNumber(seq)...)
+}
+
 // bloomBitsKey = bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash
 func bloomBitsKey(bit uint, section uint64, hash common.Hash) []byte {
 	key := append(append(bloomBitsPrefix, make([]byte, 10)...), hash.Bytes()...)
@@ -140,6 +163,16 @@ func configKey(hash common.Hash) []byte {
 	return append(configPrefix, hash.Bytes()...)
 }
 
+// networkGenesisKey = networkGenesisPrefix + hash
+func networkGenesisKey(hash common.Hash) []byte {
+	return append(networkGenesisPrefix, hash.Bytes()...)
+}
+
+// badBlockKey = badBlockPrefix + hash
+func badBlockKey(hash common.Hash) []byte {
+	return append(badBlockPrefix, hash.Bytes()...)
+}
+
 func shardStateKey(epoch *big.Int) []byte {
 	return append(shardStatePrefix, epoch.Bytes()...)
 }
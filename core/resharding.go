@@ -12,8 +12,8 @@ import (
 	"github.com/harmony-one/harmony/contracts/structs"
 	"github.com/harmony-one/harmony/core/types"
 	common2 "github.com/harmony-one/harmony/internal/common"
+	shardingconfig "github.com/harmony-one/harmony/internal/configs/sharding"
 	"github.com/harmony-one/harmony/internal/ctxerror"
-	"github.com/harmony-one/harmony/internal/genesis"
 	"github.com/harmony-one/harmony/internal/utils"
 )
 
@@ -22,16 +22,20 @@ const (
 	GenesisEpoch = 0
 	// FirstEpoch is the number of the first epoch.
 	FirstEpoch = 1
-	// GenesisShardNum is the number of shard at genesis
-	GenesisShardNum = 4
-	// GenesisShardSize is the size of each shard at genesis
-	GenesisShardSize = 100
-	// GenesisShardHarmonyNodes is the number of harmony node at each shard
-	GenesisShardHarmonyNodes = 72
 	// CuckooRate is the percentage of nodes getting reshuffled in the second step of cuckoo resharding.
 	CuckooRate = 0.1
 )
 
+// ShardingSchedule is the sharding configuration schedule of the network the
+// node runs on; it determines the epoch length and shard layout of each epoch.
+// Node setup replaces it according to the network type.
+var ShardingSchedule = shardingconfig.TestnetSchedule
+
+// CustomNetworkGenesis is the network genesis of a custom network, as given to
+// "harmony init --genesis".  It is nil on the built-in networks.  Node setup
+// also replaces ShardingSchedule with the schedule of the custom network.
+var CustomNetworkGenesis *NetworkGenesis
+
 // ShardingState is data structure hold the sharding state
 type ShardingState struct {
 	epoch      uint64 // current epoch
@@ -142,19 +146,18 @@ func Shuffle(list []types.NodeID) {
 
 // GetBlockNumberFromEpoch calculates the block number where epoch sharding information is stored
 func GetBlockNumberFromEpoch(epoch uint64) uint64 {
-	number := epoch * uint64(BlocksPerEpoch) // currently we use the first block in each epoch
-	return number
+	return ShardingSchedule.EpochFirstBlock(epoch) // currently we use the first block in each epoch
 }
 
 // GetLastBlockNumberFromEpoch calculates the last block number for the given
-// epoch.  TODO ek – this is a temp hack.
+// epoch.
 func GetLastBlockNumberFromEpoch(epoch uint64) uint64 {
-	return (epoch+1)*BlocksPerEpoch - 1
+	return ShardingSchedule.EpochLastBlock(epoch)
 }
 
 // GetEpochFromBlockNumber calculates the epoch number the block belongs to
 func GetEpochFromBlockNumber(blockNumber uint64) uint64 {
-	return blockNumber / uint64(BlocksPerEpoch)
+	return ShardingSchedule.CalcEpochNumber(blockNumber).Uint64()
 }
 
 // GetShardingStateFromBlockChain will retrieve random seed and shard map from beacon chain for given a epoch
@@ -221,32 +224,39 @@ func (ss *ShardingState) UpdateShardingState(stakeInfo *map[common.Address]*stru
 
 // GetInitShardState returns the initial shard state at genesis.
 func GetInitShardState() types.ShardState {
+	initShardingConfig := ShardingSchedule.InstanceForEpoch(big.NewInt(GenesisEpoch))
+	genesisShardNum := int(initShardingConfig.NumShards())
+	genesisShardHarmonyNodes := initShardingConfig.NumHarmonyOperatedNodesPerShard()
+	genesisShardSize := initShardingConfig.NumNodesPerShard()
+	hmyAccounts := initShardingConfig.HmyAccounts()
+	fnAccounts := initShardingConfig.FnAccounts()
+
 	shardState := types.ShardState{}
-	for i := 0; i < GenesisShardNum; i++ {
+	for i := 0; i < genesisShardNum; i++ {
 		com := types.Committee{ShardID: uint32(i)}
-		for j := 0; j < GenesisShardHarmonyNodes; j++ {
-			index := i + j*GenesisShardNum // The initial account to use for genesis nodes
+		for j := 0; j < genesisShardHarmonyNodes; j++ {
+			index := i + j*genesisShardNum // The initial account to use for genesis nodes
 
 			pub := &bls.PublicKey{}
-			pub.DeserializeHexStr(genesis.GenesisAccounts[index].BlsPublicKey)
+			pub.DeserializeHexStr(hmyAccounts[index].BlsPublicKey)
 			pubKey := types.BlsPublicKey{}
 			pubKey.FromLibBLSPublicKey(pub)
 			// TODO: directly read address for bls too
-			curNodeID := types.NodeID{common2.ParseAddr(genesis.GenesisAccounts[index].Address), pubKey}
+			curNodeID := types.NodeID{common2.ParseAddr(hmyAccounts[index].Address), pubKey}
 			com.NodeList = append(com.NodeList, curNodeID)
 		}
 
 		// add FN runner's key
-		for j := GenesisShardHarmonyNodes; j < GenesisShardSize; j++ {
-			index := i + (j-GenesisShardHarmonyNodes)*GenesisShardNum
+		for j := genesisShardHarmonyNodes; j < genesisShardSize; j++ {
+			index := i + (j-genesisShardHarmonyNodes)*genesisShardNum
 
 			pub := &bls.PublicKey{}
-			pub.DeserializeHexStr(genesis.GenesisFNAccounts[index].BlsPublicKey)
+			pub.DeserializeHexStr(fnAccounts[index].BlsPublicKey)
 
 			pubKey := types.BlsPublicKey{}
 			pubKey.FromLibBLSPublicKey(pub)
 			// TODO: directly read address for bls too
-			curNodeID := types.NodeID{common2.ParseAddr(genesis.GenesisFNAccounts[index].Address), pubKey}
+			curNodeID := types.NodeID{common2.ParseAddr(fnAccounts[index].Address), pubKey}
 			com.NodeList = append(com.NodeList, curNodeID)
 		}
 		shardState = append(shardState, com)
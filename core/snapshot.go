@@ -0,0 +1,276 @@
+package core
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/internal/ctxerror"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
+)
+
+// snapshotVersion is the version of the state snapshot format.
+const snapshotVersion = 1
+
+// emptyCodeHash is the code hash of accounts without code.
+var emptyCodeHash = crypto.Keccak256(nil)
+
+// snapshotHeader starts a state snapshot. It carries what a fresh database
+// needs, besides the state itself, to continue the chain from the snapshot
+// block. It is followed by the snapshotNodes of the state, until EOF.
+type snapshotHeader struct {
+	Version          uint
+	Genesis          *types.Block
+	GenesisTd        *big.Int
+	Block            *types.Block
+	Td               *big.Int
+	ShardState       types.ShardState // of the epoch of Block
+	EpochBlockNumber *big.Int         // first block number of the epoch of Block
+	ChainConfig      []byte           // JSON, may be empty
+	NetworkGenesis   []byte           // JSON, may be empty
+}
+
+// snapshotNode is a state trie node or contract code, keyed by its hash.
+type snapshotNode struct {
+	Hash common.Hash
+	Blob []byte
+}
+
+// ExportSnapshot writes the full state (account and storage tries and
+// contract code) at the given canonical block of db to w, along with the
+// block, its shard state and the chain metadata, as a gzipped RLP stream.
+func ExportSnapshot(db ethdb.Database, number uint64, w io.Writer) error {
+	genesis := rawdb.ReadBlock(db, rawdb.ReadCanonicalHash(db, 0), 0)
+	if genesis == nil {
+		return ErrNoGenesis
+	}
+	genesisTd := rawdb.ReadTd(db, genesis.Hash(), 0)
+	if genesisTd == nil {
+		genesisTd = new(big.Int)
+	}
+	hash := rawdb.ReadCanonicalHash(db, number)
+	block := rawdb.ReadBlock(db, hash, number)
+	if block == nil {
+		return ctxerror.New("snapshot block not found", "number", number)
+	}
+	td := rawdb.ReadTd(db, hash, number)
+	if td == nil {
+		return ctxerror.New("total difficulty of snapshot block not found", "number", number)
+	}
+	epoch := block.Header().Epoch
+	shardState, err := rawdb.ReadShardState(db, epoch)
+	if err != nil {
+		return ctxerror.New("cannot read shard state of snapshot block", "epoch", epoch).WithCause(err)
+	}
+	epochBlockNumber, err := rawdb.ReadEpochBlockNumber(db, epoch)
+	if err != nil {
+		return ctxerror.New("cannot read epoch block number of snapshot block", "epoch", epoch).WithCause(err)
+	}
+	var chainConfig []byte
+	if config := rawdb.ReadChainConfig(db, genesis.Hash()); config != nil {
+		if chainConfig, err = json.Marshal(config); err != nil {
+			return ctxerror.New("cannot encode chain config").WithCause(err)
+		}
+	}
+
+	zw := gzip.NewWriter(w)
+	err = rlp.Encode(zw, &snapshotHeader{
+		Version:          snapshotVersion,
+		Genesis:          genesis,
+		GenesisTd:        genesisTd,
+		Block:            block,
+		Td:               td,
+		ShardState:       shardState,
+		EpochBlockNumber: epochBlockNumber,
+		ChainConfig:      chainConfig,
+		NetworkGenesis:   rawdb.ReadNetworkGenesis(db, genesis.Hash()),
+	})
+	if err != nil {
+		return ctxerror.New("cannot write snapshot header").WithCause(err)
+	}
+	err = walkState(trie.NewDatabase(db), block.Root(), func(hash common.Hash, blob []byte) error {
+		return rlp.Encode(zw, &snapshotNode{Hash: hash, Blob: blob})
+	})
+	if err != nil {
+		return ctxerror.New("cannot export state", "root", block.Root()).WithCause(err)
+	}
+	return zw.Close()
+}
+
+// ImportSnapshot seeds db, the database of the given shard, from a snapshot
+// written by ExportSnapshot, and makes the snapshot block the head of the
+// chain. db must be empty or hold only the genesis block of the snapshot's
+// chain, and the snapshot must be of the same shard; nothing is written
+// otherwise. Every trie node is checked against its hash, and the state is
+// checked to be complete under the block's root.
+func ImportSnapshot(db ethdb.Database, shardID uint32, r io.Reader) (*types.Block, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, ctxerror.New("cannot read snapshot").WithCause(err)
+	}
+	defer zr.Close()
+	stream := rlp.NewStream(zr, 0)
+	var header snapshotHeader
+	if err := stream.Decode(&header); err != nil {
+		return nil, ctxerror.New("cannot read snapshot header").WithCause(err)
+	}
+	if header.Version != snapshotVersion {
+		return nil, ctxerror.New("unsupported snapshot version", "version", header.Version)
+	}
+	if header.Block.ShardID() != shardID || header.Genesis.ShardID() != shardID {
+		return nil, ctxerror.New("snapshot is of another shard",
+			"shardID", shardID,
+			"snapshotShardID", header.Block.ShardID(),
+		)
+	}
+	genesisHash := header.Genesis.Hash()
+	if stored := rawdb.ReadCanonicalHash(db, 0); stored != (common.Hash{}) {
+		if stored != genesisHash {
+			return nil, ctxerror.New("database belongs to another chain",
+				"genesis", stored,
+				"snapshotGenesis", genesisHash,
+			)
+		}
+		if head := rawdb.ReadHeadBlockHash(db); head != (common.Hash{}) && head != genesisHash {
+			return nil, ctxerror.New("database is not fresh", "head", head)
+		}
+	}
+
+	// Import the state, checking every node against its hash.
+	batch := db.NewBatch()
+	for {
+		var node snapshotNode
+		if err := stream.Decode(&node); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, ctxerror.New("cannot read snapshot state").WithCause(err)
+		}
+		if crypto.Keccak256Hash(node.Blob) != node.Hash {
+			return nil, ctxerror.New("snapshot node does not match its hash", "hash", node.Hash)
+		}
+		if err := batch.Put(node.Hash.Bytes(), node.Blob); err != nil {
+			return nil, err
+		}
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return nil, err
+			}
+			batch.Reset()
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return nil, err
+	}
+	block := header.Block
+	err = walkState(trie.NewDatabase(db), block.Root(), func(common.Hash, []byte) error { return nil })
+	if err != nil {
+		return nil, ctxerror.New("snapshot state is incomplete", "root", block.Root()).WithCause(err)
+	}
+
+	// Seed the chain with the genesis and snapshot blocks.
+	if rawdb.ReadCanonicalHash(db, 0) == (common.Hash{}) {
+		rawdb.WriteBlock(db, header.Genesis)
+		rawdb.WriteTd(db, genesisHash, 0, header.GenesisTd)
+		rawdb.WriteCanonicalHash(db, genesisHash, 0)
+		if len(header.ChainConfig) > 0 {
+			config := new(hmyparams.ChainConfig)
+			if err := json.Unmarshal(header.ChainConfig, config); err != nil {
+				return nil, ctxerror.New("invalid snapshot chain config").WithCause(err)
+			}
+			rawdb.WriteChainConfig(db, genesisHash, config)
+		}
+		if len(header.NetworkGenesis) > 0 {
+			if err := rawdb.WriteNetworkGenesis(db, genesisHash, header.NetworkGenesis); err != nil {
+				return nil, err
+			}
+		}
+	}
+	epoch := block.Header().Epoch
+	if err := rawdb.WriteShardState(db, epoch, header.ShardState); err != nil {
+		return nil, err
+	}
+	if err := rawdb.WriteEpochBlockNumber(db, epoch, header.EpochBlockNumber); err != nil {
+		return nil, err
+	}
+	if shardState := block.Header().ShardState; len(shardState) > 0 {
+		// Last block of the epoch; it also carries the next epoch's shard state.
+		if err := rawdb.WriteShardState(db, new(big.Int).Add(epoch, common.Big1), shardState); err != nil {
+			return nil, err
+		}
+	}
+	rawdb.WriteBlock(db, block)
+	rawdb.WriteTd(db, block.Hash(), block.NumberU64(), header.Td)
+	rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+	rawdb.WriteHeadBlockHash(db, block.Hash())
+	rawdb.WriteHeadHeaderHash(db, block.Hash())
+	rawdb.WriteHeadFastBlockHash(db, block.Hash())
+	return block, nil
+}
+
+// walkState calls fn with every trie node and contract code reachable from
+// the state root, failing if any of them is missing. Storage tries and code
+// shared by several accounts are visited once.
+func walkState(triedb *trie.Database, root common.Hash, fn func(hash common.Hash, blob []byte) error) error {
+	seen := make(map[common.Hash]struct{})
+	return walkTrie(triedb, root, func(leaf []byte) error {
+		var account state.Account
+		if err := rlp.DecodeBytes(leaf, &account); err != nil {
+			return err
+		}
+		if _, ok := seen[account.Root]; !ok && account.Root != types.EmptyRootHash {
+			seen[account.Root] = struct{}{}
+			if err := walkTrie(triedb, account.Root, nil, fn); err != nil {
+				return err
+			}
+		}
+		codeHash := common.BytesToHash(account.CodeHash)
+		if _, ok := seen[codeHash]; !ok && !bytes.Equal(account.CodeHash, emptyCodeHash) {
+			seen[codeHash] = struct{}{}
+			code, err := triedb.Node(codeHash)
+			if err != nil {
+				return err
+			}
+			return fn(codeHash, code)
+		}
+		return nil
+	}, fn)
+}
+
+// walkTrie calls fn with every node of the trie at root, and onLeaf, if not
+// nil, with every leaf value.
+func walkTrie(triedb *trie.Database, root common.Hash, onLeaf func(leaf []byte) error, fn func(hash common.Hash, blob []byte) error) error {
+	tr, err := trie.New(root, triedb)
+	if err != nil {
+		return err
+	}
+	it := tr.NodeIterator(nil)
+	for it.Next(true) {
+		if hash := it.Hash(); hash != (common.Hash{}) {
+			// Nodes embedded in their parent have no hash of their own.
+			blob, err := triedb.Node(hash)
+			if err != nil {
+				return err
+			}
+			if err := fn(hash, blob); err != nil {
+				return err
+			}
+		}
+		if it.Leaf() && onLeaf != nil {
+			if err := onLeaf(it.LeafBlob()); err != nil {
+				return err
+			}
+		}
+	}
+	return it.Error()
+}
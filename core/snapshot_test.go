@@ -0,0 +1,91 @@
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/harmony-one/harmony/core/rawdb"
+	"github.com/harmony-one/harmony/core/state"
+	shardingconfig "github.com/harmony-one/harmony/internal/configs/sharding"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
+)
+
+func TestSnapshotExportImport(t *testing.T) {
+	account := common.HexToAddress("0x1")
+	contract := common.HexToAddress("0x2")
+	code := []byte{0x60, 0x00, 0x60, 0x00}
+	key, value := common.HexToHash("0x10"), common.HexToHash("0x20")
+	alloc := GenesisAlloc{
+		account:  {Balance: big.NewInt(100)},
+		contract: {Balance: big.NewInt(1), Code: code, Storage: map[common.Hash]common.Hash{key: value}},
+	}
+	ng := NewNetworkGenesis(params.TestChainConfig, hmyparams.DevnetChainConfig,
+		shardingconfig.DevnetSchedule, alloc)
+	db := ethdb.NewMemDatabase()
+	genesis, err := ng.Commit(db, 0)
+	if err != nil {
+		t.Fatalf("cannot commit network genesis: %v", err)
+	}
+
+	var snapshot bytes.Buffer
+	if err := ExportSnapshot(db, 0, &snapshot); err != nil {
+		t.Fatalf("cannot export snapshot: %v", err)
+	}
+	fresh := ethdb.NewMemDatabase()
+	block, err := ImportSnapshot(fresh, 0, bytes.NewReader(snapshot.Bytes()))
+	if err != nil {
+		t.Fatalf("cannot import snapshot: %v", err)
+	}
+	if block.Hash() != genesis.Hash() {
+		t.Errorf("imported block = %x, expected = %x", block.Hash(), genesis.Hash())
+	}
+	if head := rawdb.ReadHeadBlockHash(fresh); head != genesis.Hash() {
+		t.Errorf("head block = %x, expected = %x", head, genesis.Hash())
+	}
+	if _, err := rawdb.ReadShardState(fresh, common.Big0); err != nil {
+		t.Errorf("shard state not imported: %v", err)
+	}
+
+	statedb, err := state.New(block.Root(), state.NewDatabase(fresh))
+	if err != nil {
+		t.Fatalf("cannot open imported state: %v", err)
+	}
+	if balance := statedb.GetBalance(account); balance.Cmp(big.NewInt(100)) != 0 {
+		t.Errorf("balance = %v, expected = %v", balance, 100)
+	}
+	if got := statedb.GetCode(contract); !bytes.Equal(got, code) {
+		t.Errorf("code = %x, expected = %x", got, code)
+	}
+	if got := statedb.GetState(contract, key); got != value {
+		t.Errorf("storage = %x, expected = %x", got, value)
+	}
+
+	// Importing into a database holding only the same genesis is fine, but a
+	// database of another chain is refused.
+	if _, err := ImportSnapshot(db, 0, bytes.NewReader(snapshot.Bytes())); err != nil {
+		t.Errorf("importing into a database with only the same genesis should succeed: %v", err)
+	}
+	other := ethdb.NewMemDatabase()
+	otherGenesis := testNetworkGenesis()
+	otherGenesis.Timestamp++
+	if _, err := otherGenesis.Commit(other, 0); err != nil {
+		t.Fatalf("cannot commit network genesis: %v", err)
+	}
+	if _, err := ImportSnapshot(other, 0, bytes.NewReader(snapshot.Bytes())); err == nil {
+		t.Errorf("importing into a database of another chain should fail")
+	}
+
+	// A snapshot of another shard is refused before anything is written.
+	wrongShard := ethdb.NewMemDatabase()
+	if _, err := ImportSnapshot(wrongShard, 1, bytes.NewReader(snapshot.Bytes())); err == nil {
+		t.Errorf("importing into a database of another shard should fail")
+	}
+	if n := len(wrongShard.Keys()); n != 0 {
+		t.Errorf("database of another shard has %d keys after a refused import, expected none", n)
+	}
+}
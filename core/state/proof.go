@@ -0,0 +1,67 @@
+package state
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+
+	"github.com/harmony-one/harmony/core/types"
+)
+
+// VerifyAccountProof checks a Merkle proof, as returned by DB.GetProof, of the
+// account at address against the state root of a block header. It returns the
+// proven account, or an empty account if the proof shows that the account does
+// not exist.
+func VerifyAccountProof(root common.Hash, address common.Address, proof [][]byte) (*Account, error) {
+	value, err := verifyProof(root, crypto.Keccak256(address.Bytes()), proof)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return &Account{Balance: new(big.Int), Root: types.EmptyRootHash, CodeHash: emptyCodeHash}, nil
+	}
+	account := new(Account)
+	if err := rlp.DecodeBytes(value, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// VerifyStorageProof checks a Merkle proof, as returned by DB.GetStorageProof,
+// of a storage slot against the storage root of an account, and returns the
+// proven value of the slot.
+func VerifyStorageProof(storageRoot common.Hash, key common.Hash, proof [][]byte) (common.Hash, error) {
+	if storageRoot == types.EmptyRootHash && len(proof) == 0 {
+		return common.Hash{}, nil
+	}
+	value, err := verifyProof(storageRoot, crypto.Keccak256(key.Bytes()), proof)
+	if err != nil || value == nil {
+		return common.Hash{}, err
+	}
+	_, content, _, err := rlp.Split(value)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(content), nil
+}
+
+// verifyProof returns the value of key proven by proof under root, or nil if
+// the proof shows that the key is absent.
+func verifyProof(root common.Hash, key []byte, proof [][]byte) ([]byte, error) {
+	if len(proof) == 0 {
+		return nil, errors.New("empty proof")
+	}
+	proofDB := ethdb.NewMemDatabase()
+	for _, node := range proof {
+		if err := proofDB.Put(crypto.Keccak256(node), node); err != nil {
+			return nil, err
+		}
+	}
+	value, _, err := trie.VerifyProof(root, key, proofDB)
+	return value, err
+}
@@ -0,0 +1,65 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+
+	"github.com/harmony-one/harmony/core/types"
+)
+
+func TestVerifyProof(t *testing.T) {
+	stateDB, _ := New(common.Hash{}, NewDatabase(ethdb.NewMemDatabase()))
+	address := common.HexToAddress("0x1")
+	key, value := common.HexToHash("0x10"), common.HexToHash("0x20")
+	stateDB.AddBalance(address, big.NewInt(100))
+	stateDB.SetNonce(address, 3)
+	stateDB.SetState(address, key, value)
+	for i := byte(2); i < 20; i++ {
+		stateDB.AddBalance(common.BytesToAddress([]byte{i}), big.NewInt(int64(i)))
+	}
+	root, err := stateDB.Commit(false)
+	if err != nil {
+		t.Fatalf("cannot commit state: %v", err)
+	}
+
+	proof, err := stateDB.GetProof(address)
+	if err != nil {
+		t.Fatalf("cannot prove account: %v", err)
+	}
+	account, err := VerifyAccountProof(root, address, proof)
+	if err != nil {
+		t.Fatalf("cannot verify account proof: %v", err)
+	}
+	if account.Balance.Cmp(big.NewInt(100)) != 0 || account.Nonce != 3 {
+		t.Errorf("proven account = %+v, expected balance 100 and nonce 3", account)
+	}
+	if _, err := VerifyAccountProof(common.HexToHash("0xbad"), address, proof); err == nil {
+		t.Errorf("verifying against another root should fail")
+	}
+
+	storageProof, err := stateDB.GetStorageProof(address, key)
+	if err != nil {
+		t.Fatalf("cannot prove storage: %v", err)
+	}
+	got, err := VerifyStorageProof(account.Root, key, storageProof)
+	if err != nil || got != value {
+		t.Errorf("VerifyStorageProof() = %x, %v, expected = %x, nil", got, err, value)
+	}
+
+	// A proof of an absent account proves an empty account.
+	absent := common.HexToAddress("0xabcdef")
+	proof, err = stateDB.GetProof(absent)
+	if err != nil {
+		t.Fatalf("cannot prove absent account: %v", err)
+	}
+	account, err = VerifyAccountProof(root, absent, proof)
+	if err != nil {
+		t.Fatalf("cannot verify absent account proof: %v", err)
+	}
+	if account.Balance.Sign() != 0 || account.Root != types.EmptyRootHash {
+		t.Errorf("proven absent account = %+v, expected an empty account", account)
+	}
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/harmony-one/harmony/core/state"
 	"github.com/harmony-one/harmony/core/types"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
 )
 
 const (
@@ -45,6 +46,13 @@ var (
 	// ErrInvalidSender is returned if the transaction contains an invalid signature.
 	ErrInvalidSender = errors.New("invalid sender")
 
+	// ErrInvalidShard is returned if the transaction is for the wrong shard.
+	ErrInvalidShard = errors.New("invalid shard")
+
+	// ErrUnprotectedTx is returned if the transaction is not replay-protected
+	// while the replay protection fork is active.
+	ErrUnprotectedTx = types.ErrUnprotectedTx
+
 	// ErrNonceTooLow is returned if the nonce of a transaction is lower than the
 	// one present in the local chain.
 	ErrNonceTooLow = errors.New("nonce too low")
@@ -119,6 +127,8 @@ type blockChain interface {
 	CurrentBlock() *types.Block
 	GetBlock(hash common.Hash, number uint64) *types.Block
 	StateAt(root common.Hash) (*state.DB, error)
+	ShardID() uint32
+	HarmonyConfig() *hmyparams.ChainConfig
 
 	SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) event.Subscription
 }
@@ -225,7 +235,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		config:      config,
 		chainconfig: chainconfig,
 		chain:       chain,
-		signer:      types.NewEIP155Signer(chainconfig.ChainID),
+		signer:      types.NewHarmonySigner(chain.HarmonyConfig().ChainID, chain.ShardID()),
 		pending:     make(map[common.Address]*txList),
 		queue:       make(map[common.Address]*txList),
 		beats:       make(map[common.Address]time.Time),
@@ -281,7 +291,7 @@ func (pool *TxPool) loop() {
 	defer journal.Stop()
 
 	// Track the previous head headers for transaction reorgs
-	//head := pool.chain.CurrentBlock()
+	head := pool.chain.CurrentBlock()
 
 	// Keep waiting for and reacting to the various events
 	for {
@@ -293,8 +303,8 @@ func (pool *TxPool) loop() {
 				//if pool.chainconfig.IsHomestead(ev.Block.Number()) {
 				//	pool.homestead = true
 				//}
-				//pool.reset(head.Header(), ev.Block.Header())
-				//head = ev.Block
+				pool.reset(head.Header(), ev.Block.Header())
+				head = ev.Block
 
 				pool.mu.Unlock()
 			}
@@ -364,16 +374,19 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 		oldNum := oldHead.Number.Uint64()
 		newNum := newHead.Number.Uint64()
 
+		var (
+			rem = pool.chain.GetBlock(oldHead.Hash(), oldHead.Number.Uint64())
+			add = pool.chain.GetBlock(newHead.Hash(), newHead.Number.Uint64())
+		)
 		if depth := uint64(math.Abs(float64(oldNum) - float64(newNum))); depth > 64 {
 			log.Debug("Skipping deep transaction reorg", "depth", depth)
+		} else if rem == nil || add == nil {
+			// The old head is gone if the chain was rewound past it.
+			log.Debug("Skipping transaction reorg of missing head", "old", oldNum, "new", newNum)
 		} else {
 			// Reorg seems shallow enough to pull in all transactions into memory
 			var discarded, included types.Transactions
 
-			var (
-				rem = pool.chain.GetBlock(oldHead.Hash(), oldHead.Number.Uint64())
-				add = pool.chain.GetBlock(newHead.Hash(), newHead.Number.Uint64())
-			)
 			for rem.NumberU64() > add.NumberU64() {
 				discarded = append(discarded, rem.Transactions()...)
 				if rem = pool.chain.GetBlock(rem.ParentHash(), rem.NumberU64()-1); rem == nil {
@@ -568,6 +581,14 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
+	// Reject transactions meant for another shard
+	if tx.ShardID() != pool.chain.ShardID() {
+		return ErrInvalidShard
+	}
+	// Reject unprotected transactions once replay protection is enforced
+	if pool.chain.HarmonyConfig().IsReplayProtection(pool.chain.CurrentBlock().Header().Epoch) && !tx.Protected() {
+		return ErrUnprotectedTx
+	}
 	// Heuristic limit, reject transactions over 32KB to prevent DOS attacks
 	if tx.Size() > 32*1024 {
 		return ErrOversizedData
@@ -883,6 +904,20 @@ func (pool *TxPool) Get(hash common.Hash) *types.Transaction {
 	return pool.all.Get(hash)
 }
 
+// RemoveTx removes a transaction from the pool, moving the subsequent pending
+// transactions of its sender back to the future queue. It reports whether the
+// transaction was in the pool.
+func (pool *TxPool) RemoveTx(hash common.Hash) bool {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if pool.all.Get(hash) == nil {
+		return false
+	}
+	pool.removeTx(hash, true)
+	return true
+}
+
 // removeTx removes a single transaction from the queue, moving all subsequent
 // transactions back to the future queue.
 func (pool *TxPool) removeTx(hash common.Hash, outofbound bool) {
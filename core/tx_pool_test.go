@@ -34,6 +34,7 @@ import (
 	"github.com/harmony-one/harmony/common/denominations"
 	"github.com/harmony-one/harmony/core/state"
 	"github.com/harmony-one/harmony/core/types"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
 )
 
 // testTxPoolConfig is a transaction pool configuration without stateful disk
@@ -65,6 +66,14 @@ func (bc *testBlockChain) StateAt(common.Hash) (*state.DB, error) {
 	return bc.statedb, nil
 }
 
+func (bc *testBlockChain) ShardID() uint32 {
+	return 0
+}
+
+func (bc *testBlockChain) HarmonyConfig() *hmyparams.ChainConfig {
+	return hmyparams.TestChainConfig
+}
+
 func (bc *testBlockChain) SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) event.Subscription {
 	return bc.chainHeadFeed.Subscribe(ch)
 }
@@ -260,6 +269,20 @@ func TestInvalidTransactions(t *testing.T) {
 	}
 }
 
+func TestInvalidShardTransaction(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	tx, _ := types.SignTx(types.NewTransaction(0, common.Address{}, 1, big.NewInt(100), 100000, big.NewInt(1), nil), types.NewHarmonySigner(big.NewInt(2), 1), key)
+	from, _ := types.Sender(types.NewHarmonySigner(big.NewInt(2), 1), tx)
+	pool.currentState.AddBalance(from, big.NewInt(0xffffffffffffff))
+	if err := pool.AddRemote(tx); err != ErrInvalidShard {
+		t.Error("expected", ErrInvalidShard, "got", err)
+	}
+}
+
 func TestTransactionQueue(t *testing.T) {
 	t.Parallel()
 
@@ -1261,6 +1284,43 @@ func TestTransactionStatusCheck(t *testing.T) {
 	}
 }
 
+// Tests that removing a pending transaction by hash moves the later transactions
+// of its sender back to the future queue.
+func TestTransactionRemoveTx(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	pool.currentState.AddBalance(addr, big.NewInt(1000000000))
+	txs := types.Transactions{
+		transaction(0, 100000, key),
+		transaction(1, 100000, key),
+		transaction(2, 100000, key),
+	}
+	pool.AddRemotes(txs)
+	if pending, queued := pool.Stats(); pending != 3 || queued != 0 {
+		t.Fatalf("pool stats = %d pending, %d queued, expected = 3, 0", pending, queued)
+	}
+
+	if !pool.RemoveTx(txs[1].Hash()) {
+		t.Fatalf("expected the transaction to be removed")
+	}
+	if pool.RemoveTx(txs[1].Hash()) {
+		t.Errorf("expected a removed transaction not to be found")
+	}
+	if pending, queued := pool.Stats(); pending != 1 || queued != 1 {
+		t.Errorf("pool stats = %d pending, %d queued, expected = 1, 1", pending, queued)
+	}
+	if pool.Get(txs[1].Hash()) != nil {
+		t.Errorf("removed transaction still in the pool")
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Benchmarks the speed of validating the contents of the pending queue of the
 // transaction pool.
 func BenchmarkPendingDemotion100(b *testing.B)   { benchmarkPendingDemotion(b, 100) }
@@ -70,7 +70,11 @@ func (n *BlockNonce) UnmarshalText(input []byte) error {
 }
 
 // Header represents a block header in the Ethereum blockchain.
+//
+// Header holds the fields of all header versions; which of them are encoded,
+// and so hashed, depends on Version. See HeaderVersion.
 type Header struct {
+	Version     HeaderVersion  `json:"-"`
 	ParentHash  common.Hash    `json:"parentHash"       gencodec:"required"`
 	Coinbase    common.Address `json:"miner"            gencodec:"required"`
 	Root        common.Hash    `json:"stateRoot"        gencodec:"required"`
@@ -111,7 +115,7 @@ type headerMarshaling struct {
 }
 
 // Hash returns the block hash of the header, which is simply the keccak256 hash of its
-// RLP encoding.
+// RLP encoding in the layout of its version.
 func (h *Header) Hash() common.Hash {
 	return rlpHash(h)
 }
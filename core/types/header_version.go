@@ -0,0 +1,205 @@
+package types
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// HeaderVersion identifies the RLP layout of a block header.
+//
+// Header holds the fields of every version in memory; each version encodes
+// only its own subset of them. To add or remove header fields at a fork, add
+// the fields to Header, define the layout of the new version and register it
+// in headerLayouts; headers of earlier versions keep their encoding and hash.
+type HeaderVersion uint8
+
+// Header versions.
+const (
+	// HeaderV0 is the original header layout. It is encoded as a bare RLP
+	// list without a version tag, so that blocks stored before headers were
+	// versioned keep their encoding and hash.
+	HeaderV0 HeaderVersion = iota
+)
+
+// ErrUnknownHeaderVersion is returned when encoding or decoding a header of a
+// version this node does not know.
+var ErrUnknownHeaderVersion = errors.New("unknown header version")
+
+// headerLayout is the RLP layout of one header version.
+type headerLayout interface {
+	// fromHeader copies the fields of this version from h.
+	fromHeader(h *Header)
+	// toHeader copies the fields of this version into h.
+	toHeader(h *Header)
+}
+
+// headerLayouts returns a new, empty layout of each known header version.
+var headerLayouts = map[HeaderVersion]func() headerLayout{
+	HeaderV0: func() headerLayout { return new(headerV0) },
+}
+
+// headerEnvelope is the encoding of the headers of versions other than
+// HeaderV0: the version tag followed by the RLP of its layout.
+type headerEnvelope struct {
+	Version HeaderVersion
+	Fields  rlp.RawValue
+}
+
+// EncodeRLP encodes h in the layout of its version.
+func (h *Header) EncodeRLP(w io.Writer) error {
+	newLayout, ok := headerLayouts[h.Version]
+	if !ok {
+		return ErrUnknownHeaderVersion
+	}
+	layout := newLayout()
+	layout.fromHeader(h)
+	if h.Version == HeaderV0 {
+		return rlp.Encode(w, layout)
+	}
+	fields, err := rlp.EncodeToBytes(layout)
+	if err != nil {
+		return err
+	}
+	return rlp.Encode(w, headerEnvelope{Version: h.Version, Fields: fields})
+}
+
+// DecodeRLP decodes a header of any known version.
+func (h *Header) DecodeRLP(s *rlp.Stream) error {
+	raw, err := s.Raw()
+	if err != nil {
+		return err
+	}
+	version, fields, err := splitHeaderRLP(raw)
+	if err != nil {
+		return err
+	}
+	newLayout, ok := headerLayouts[version]
+	if !ok {
+		return ErrUnknownHeaderVersion
+	}
+	layout := newLayout()
+	if err := rlp.DecodeBytes(fields, layout); err != nil {
+		return err
+	}
+	*h = Header{Version: version}
+	layout.toHeader(h)
+	return nil
+}
+
+// splitHeaderRLP returns the version of an RLP-encoded header and the RLP of
+// its layout. A HeaderV0 header starts with the 32-byte parent hash, whereas
+// an envelope starts with a small integer version tag.
+func splitHeaderRLP(raw []byte) (HeaderVersion, []byte, error) {
+	content, _, err := rlp.SplitList(raw)
+	if err != nil {
+		return 0, nil, err
+	}
+	kind, first, _, err := rlp.Split(content)
+	if err != nil {
+		return 0, nil, err
+	}
+	if kind == rlp.String && len(first) == common.HashLength {
+		return HeaderV0, raw, nil
+	}
+	var envelope headerEnvelope
+	if err := rlp.DecodeBytes(raw, &envelope); err != nil {
+		return 0, nil, err
+	}
+	if envelope.Version == HeaderV0 {
+		// HeaderV0 is never wrapped in an envelope.
+		return 0, nil, ErrUnknownHeaderVersion
+	}
+	return envelope.Version, envelope.Fields, nil
+}
+
+// headerV0 is the layout of HeaderV0 headers.
+type headerV0 struct {
+	ParentHash       common.Hash
+	Coinbase         common.Address
+	Root             common.Hash
+	TxHash           common.Hash
+	ReceiptHash      common.Hash
+	Bloom            ethtypes.Bloom
+	Number           *big.Int
+	GasLimit         uint64
+	GasUsed          uint64
+	Time             *big.Int
+	Extra            []byte
+	MixDigest        common.Hash
+	Epoch            *big.Int
+	ShardID          uint32
+	PrepareSignature [96]byte
+	PrepareBitmap    []byte
+	CommitSignature  [96]byte
+	CommitBitmap     []byte
+	Vrf              [32]byte
+	VrfProof         [96]byte
+	Vdf              [258]byte
+	VdfProof         [258]byte
+	ShardStateHash   common.Hash
+	ShardState       ShardState
+	CrossLinks       [][]byte
+}
+
+func (l *headerV0) fromHeader(h *Header) {
+	*l = headerV0{
+		ParentHash:       h.ParentHash,
+		Coinbase:         h.Coinbase,
+		Root:             h.Root,
+		TxHash:           h.TxHash,
+		ReceiptHash:      h.ReceiptHash,
+		Bloom:            h.Bloom,
+		Number:           h.Number,
+		GasLimit:         h.GasLimit,
+		GasUsed:          h.GasUsed,
+		Time:             h.Time,
+		Extra:            h.Extra,
+		MixDigest:        h.MixDigest,
+		Epoch:            h.Epoch,
+		ShardID:          h.ShardID,
+		PrepareSignature: h.PrepareSignature,
+		PrepareBitmap:    h.PrepareBitmap,
+		CommitSignature:  h.CommitSignature,
+		CommitBitmap:     h.CommitBitmap,
+		Vrf:              h.Vrf,
+		VrfProof:         h.VrfProof,
+		Vdf:              h.Vdf,
+		VdfProof:         h.VdfProof,
+		ShardStateHash:   h.ShardStateHash,
+		ShardState:       h.ShardState,
+		CrossLinks:       h.CrossLinks,
+	}
+}
+
+func (l *headerV0) toHeader(h *Header) {
+	h.ParentHash = l.ParentHash
+	h.Coinbase = l.Coinbase
+	h.Root = l.Root
+	h.TxHash = l.TxHash
+	h.ReceiptHash = l.ReceiptHash
+	h.Bloom = l.Bloom
+	h.Number = l.Number
+	h.GasLimit = l.GasLimit
+	h.GasUsed = l.GasUsed
+	h.Time = l.Time
+	h.Extra = l.Extra
+	h.MixDigest = l.MixDigest
+	h.Epoch = l.Epoch
+	h.ShardID = l.ShardID
+	h.PrepareSignature = l.PrepareSignature
+	h.PrepareBitmap = l.PrepareBitmap
+	h.CommitSignature = l.CommitSignature
+	h.CommitBitmap = l.CommitBitmap
+	h.Vrf = l.Vrf
+	h.VrfProof = l.VrfProof
+	h.Vdf = l.Vdf
+	h.VdfProof = l.VdfProof
+	h.ShardStateHash = l.ShardStateHash
+	h.ShardState = l.ShardState
+	h.CrossLinks = l.CrossLinks
+}
@@ -0,0 +1,100 @@
+package types
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func testHeader() *Header {
+	return &Header{
+		ParentHash: common.HexToHash("0x01"),
+		Number:     big.NewInt(10),
+		Time:       big.NewInt(1000),
+		Epoch:      big.NewInt(1),
+		ShardID:    2,
+		Extra:      []byte("extra"),
+	}
+}
+
+func TestHeaderV0EncodingIsUntagged(t *testing.T) {
+	h := testHeader()
+	layout := new(headerV0)
+	layout.fromHeader(h)
+	want, err := rlp.EncodeToBytes(layout)
+	if err != nil {
+		t.Fatalf("cannot encode header layout: %v", err)
+	}
+	got, err := rlp.EncodeToBytes(h)
+	if err != nil {
+		t.Fatalf("cannot encode header: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("HeaderV0 encoding = %x, expected = %x", got, want)
+	}
+	decoded := new(Header)
+	if err := rlp.DecodeBytes(got, decoded); err != nil {
+		t.Fatalf("cannot decode header: %v", err)
+	}
+	if decoded.Version != HeaderV0 || decoded.Hash() != h.Hash() {
+		t.Errorf("decoded header = version %v hash %x, expected = version %v hash %x",
+			decoded.Version, decoded.Hash(), HeaderV0, h.Hash())
+	}
+}
+
+// testHeaderV1 is a layout that drops most HeaderV0 fields.
+type testHeaderV1 struct {
+	ParentHash common.Hash
+	Number     *big.Int
+	Epoch      *big.Int
+	ShardID    uint32
+}
+
+func (l *testHeaderV1) fromHeader(h *Header) {
+	*l = testHeaderV1{h.ParentHash, h.Number, h.Epoch, h.ShardID}
+}
+
+func (l *testHeaderV1) toHeader(h *Header) {
+	h.ParentHash, h.Number, h.Epoch, h.ShardID = l.ParentHash, l.Number, l.Epoch, l.ShardID
+}
+
+func TestHeaderVersionEnvelope(t *testing.T) {
+	const testVersion HeaderVersion = 1
+	headerLayouts[testVersion] = func() headerLayout { return new(testHeaderV1) }
+	defer delete(headerLayouts, testVersion)
+
+	h := testHeader()
+	h.Version = testVersion
+	data, err := rlp.EncodeToBytes(h)
+	if err != nil {
+		t.Fatalf("cannot encode header: %v", err)
+	}
+	decoded := new(Header)
+	if err := rlp.DecodeBytes(data, decoded); err != nil {
+		t.Fatalf("cannot decode header: %v", err)
+	}
+	if decoded.Version != testVersion {
+		t.Errorf("Version = %v, expected = %v", decoded.Version, testVersion)
+	}
+	if decoded.ShardID != h.ShardID || decoded.Number.Cmp(h.Number) != 0 {
+		t.Errorf("decoded header = %+v, expected fields of %+v", decoded, h)
+	}
+	if decoded.Extra != nil {
+		t.Errorf("Extra = %x, expected it not to be encoded in version %v", decoded.Extra, testVersion)
+	}
+	if decoded.Hash() != h.Hash() {
+		t.Errorf("Hash = %x, expected = %x", decoded.Hash(), h.Hash())
+	}
+	v0 := testHeader()
+	if v0.Hash() == h.Hash() {
+		t.Errorf("headers of different versions should hash differently")
+	}
+
+	delete(headerLayouts, testVersion)
+	if err := rlp.DecodeBytes(data, new(Header)); err != ErrUnknownHeaderVersion {
+		t.Errorf("decoding an unknown version: err = %v, expected = %v", err, ErrUnknownHeaderVersion)
+	}
+}
@@ -25,11 +25,14 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
 )
 
 // Constants for transaction signing.
 var (
 	ErrInvalidChainID = errors.New("invalid chain id for signer")
+	ErrInvalidShardID = errors.New("invalid shard id for signer")
+	ErrUnprotectedTx  = errors.New("transaction is not replay-protected")
 )
 
 // sigCache is used to cache the derived sender and contains
@@ -53,6 +56,15 @@ func MakeSigner(config *params.ChainConfig, blockNumber *big.Int) Signer {
 	return signer
 }
 
+// MakeShardSigner returns the signer of the transactions of the given shard in
+// the given epoch, on the chain of the given configuration. Once its replay
+// protection fork is active, the signer rejects unprotected transactions.
+func MakeShardSigner(config *hmyparams.ChainConfig, shardID uint32, epoch *big.Int) HarmonySigner {
+	signer := NewHarmonySigner(config.ChainID, shardID)
+	signer.protectedOnly = config.IsReplayProtection(epoch)
+	return signer
+}
+
 // SignTx signs the transaction using the given signer and private key
 func SignTx(tx *Transaction, s Signer, prv *ecdsa.PrivateKey) (*Transaction, error) {
 	h := s.Hash(tx)
@@ -103,6 +115,88 @@ type Signer interface {
 	Equal(Signer) bool
 }
 
+// HarmonySigner implements Signer using the Harmony replay protection rules.
+// On top of EIP155, the signing hash commits to the shard ID, so a transaction
+// can neither be replayed on another network nor included on another shard.
+// Unprotected transactions are accepted as homestead ones, unless the signer
+// only accepts protected transactions.
+type HarmonySigner struct {
+	chainID, chainIDMul *big.Int
+	shardID             uint32
+	protectedOnly       bool
+}
+
+// NewHarmonySigner creates a HarmonySigner given chainID and shardID.
+func NewHarmonySigner(chainID *big.Int, shardID uint32) HarmonySigner {
+	if chainID == nil {
+		chainID = new(big.Int)
+	}
+	return HarmonySigner{
+		chainID:    chainID,
+		chainIDMul: new(big.Int).Mul(chainID, big.NewInt(2)),
+		shardID:    shardID,
+	}
+}
+
+// Equal checks if the given HarmonySigner is equal to another Signer.
+func (s HarmonySigner) Equal(s2 Signer) bool {
+	harmony, ok := s2.(HarmonySigner)
+	return ok && harmony.chainID.Cmp(s.chainID) == 0 && harmony.shardID == s.shardID &&
+		harmony.protectedOnly == s.protectedOnly
+}
+
+// Sender returns the sender address of the given signer.
+func (s HarmonySigner) Sender(tx *Transaction) (common.Address, error) {
+	if !tx.Protected() {
+		if s.protectedOnly {
+			return common.Address{}, ErrUnprotectedTx
+		}
+		return HomesteadSigner{}.Sender(tx)
+	}
+	if tx.ChainID().Cmp(s.chainID) != 0 {
+		return common.Address{}, ErrInvalidChainID
+	}
+	if tx.ShardID() != s.shardID {
+		return common.Address{}, ErrInvalidShardID
+	}
+	V := new(big.Int).Sub(tx.data.V, s.chainIDMul)
+	V.Sub(V, big8)
+	return recoverPlain(s.Hash(tx), tx.data.R, tx.data.S, V, true)
+}
+
+// SignatureValues returns signature values. This signature
+// needs to be in the [R || S || V] format where V is 0 or 1.
+func (s HarmonySigner) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big.Int, err error) {
+	if tx.ShardID() != s.shardID {
+		return nil, nil, nil, ErrInvalidShardID
+	}
+	R, S, V, err = HomesteadSigner{}.SignatureValues(tx, sig)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if s.chainID.Sign() != 0 {
+		V = big.NewInt(int64(sig[64] + 35))
+		V.Add(V, s.chainIDMul)
+	}
+	return R, S, V, nil
+}
+
+// Hash returns the hash to be signed by the sender.
+// It does not uniquely identify the transaction.
+func (s HarmonySigner) Hash(tx *Transaction) common.Hash {
+	return rlpHash([]interface{}{
+		tx.data.AccountNonce,
+		tx.data.Price,
+		tx.data.GasLimit,
+		s.shardID,
+		tx.data.ToShardID,
+		tx.data.Recipient,
+		tx.data.Amount,
+		tx.data.Payload,
+		s.chainID, uint(0), uint(0),
+	})
+}
+
 // EIP155Signer implements Signer using the EIP155 rules.
 type EIP155Signer struct {
 	chainID, chainIDMul *big.Int
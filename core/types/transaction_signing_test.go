@@ -22,6 +22,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
 )
 
 func TestEIP155Signing(t *testing.T) {
@@ -96,3 +97,81 @@ func TestChainID(t *testing.T) {
 		t.Error("expected no error")
 	}
 }
+
+func TestHarmonySigning(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	signer := NewHarmonySigner(big.NewInt(2), 1)
+	tx, err := SignTx(NewTransaction(0, addr, 1, new(big.Int), 0, new(big.Int), nil), signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tx.Protected() {
+		t.Fatal("expected tx to be protected")
+	}
+
+	from, err := Sender(signer, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != addr {
+		t.Errorf("exected from and address to be equal. Got %x want %x", from, addr)
+	}
+}
+
+func TestHarmonyReplayProtection(t *testing.T) {
+	key, _ := defaultTestKey()
+
+	tx := NewTransaction(0, common.Address{}, 1, new(big.Int), 0, new(big.Int), nil)
+
+	var err error
+	tx, err = SignTx(tx, NewHarmonySigner(big.NewInt(2), 1), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Sender(NewHarmonySigner(big.NewInt(1), 1), tx)
+	if err != ErrInvalidChainID {
+		t.Error("expected error:", ErrInvalidChainID)
+	}
+
+	_, err = Sender(NewHarmonySigner(big.NewInt(2), 0), tx)
+	if err != ErrInvalidShardID {
+		t.Error("expected error:", ErrInvalidShardID)
+	}
+
+	_, err = Sender(NewHarmonySigner(big.NewInt(2), 1), tx)
+	if err != nil {
+		t.Error("expected no error")
+	}
+
+	_, err = SignTx(NewTransaction(0, common.Address{}, 0, new(big.Int), 0, new(big.Int), nil), NewHarmonySigner(big.NewInt(2), 1), key)
+	if err != ErrInvalidShardID {
+		t.Error("expected error:", ErrInvalidShardID)
+	}
+}
+
+func TestShardSignerReplayProtection(t *testing.T) {
+	key, _ := defaultTestKey()
+	config := &hmyparams.ChainConfig{ChainID: big.NewInt(2), ReplayProtectionEpoch: big.NewInt(1)}
+
+	tx, err := SignTx(NewTransaction(0, common.Address{}, 0, new(big.Int), 0, new(big.Int), nil), HomesteadSigner{}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Sender(MakeShardSigner(config, 0, big.NewInt(0)), tx); err != nil {
+		t.Error("expected unprotected transaction to be accepted before the fork, got", err)
+	}
+	if _, err := Sender(MakeShardSigner(config, 0, big.NewInt(1)), tx); err != ErrUnprotectedTx {
+		t.Error("expected error:", ErrUnprotectedTx)
+	}
+
+	tx, err = SignTx(NewTransaction(0, common.Address{}, 0, new(big.Int), 0, new(big.Int), nil), MakeShardSigner(config, 0, big.NewInt(1)), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Sender(MakeShardSigner(config, 0, big.NewInt(1)), tx); err != nil {
+		t.Error("expected protected transaction to be accepted after the fork, got", err)
+	}
+}
@@ -3,6 +3,7 @@ package hmy
 import (
 	"context"
 	"errors"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -16,11 +17,13 @@ import (
 	"github.com/harmony-one/harmony/core"
 	"github.com/harmony-one/harmony/core/state"
 	"github.com/harmony-one/harmony/core/types"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
 )
 
 // APIBackend An implementation of internal/hmyapi/Backend. Full client.
 type APIBackend struct {
 	hmy *Harmony
+	gpo *Oracle
 }
 
 // ChainDb ...
@@ -86,9 +89,12 @@ func (b *APIBackend) GetPoolNonce(ctx context.Context, addr common.Address) (uin
 
 // SendTx ...
 func (b *APIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
-	// return b.hmy.txPool.Add(ctx, signedTx)
-	b.hmy.nodeAPI.AddPendingTransaction(signedTx)
-	return nil // TODO(ricl): AddPendingTransaction should return error
+	return b.hmy.nodeAPI.AddPendingTransaction(signedTx)
+}
+
+// SuggestPrice returns the gas price recommended by the gas price oracle.
+func (b *APIBackend) SuggestPrice(ctx context.Context) (*big.Int, error) {
+	return b.gpo.SuggestPrice(ctx)
 }
 
 // ChainConfig ...
@@ -96,6 +102,11 @@ func (b *APIBackend) ChainConfig() *params.ChainConfig {
 	return b.hmy.blockchain.Config()
 }
 
+// HarmonyConfig returns the Harmony chain configuration of the blockchain.
+func (b *APIBackend) HarmonyConfig() *hmyparams.ChainConfig {
+	return b.hmy.blockchain.HarmonyConfig()
+}
+
 // CurrentBlock ...
 func (b *APIBackend) CurrentBlock() *types.Block {
 	return types.NewBlockWithHeader(b.hmy.blockchain.CurrentHeader())
@@ -196,6 +207,27 @@ func (b *APIBackend) GetPoolTransactions() (types.Transactions, error) {
 	return txs, nil
 }
 
+// Stats returns the number of pending and queued transactions in the pool.
+func (b *APIBackend) Stats() (pending int, queued int) {
+	return b.hmy.txPool.Stats()
+}
+
+// TxPoolContent returns the pending and queued transactions in the pool,
+// grouped by sender.
+func (b *APIBackend) TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions) {
+	return b.hmy.txPool.Content()
+}
+
+// RemovePoolTransaction removes a transaction from the pool.
+func (b *APIBackend) RemovePoolTransaction(txHash common.Hash) bool {
+	return b.hmy.txPool.RemoveTx(txHash)
+}
+
+// SetHead rewinds the blockchain to the given block number.
+func (b *APIBackend) SetHead(number uint64) error {
+	return b.hmy.nodeAPI.RewindBlockchain(number)
+}
+
 // GetBalance returns balance of an given address.
 func (b *APIBackend) GetBalance(address common.Address) (*hexutil.Big, error) {
 	balance, err := b.hmy.nodeAPI.GetBalanceOfAddress(address)
@@ -37,11 +37,12 @@ type Harmony struct {
 
 // NodeAPI is the list of functions from node used to call rpc apis.
 type NodeAPI interface {
-	AddPendingTransaction(newTx *types.Transaction)
+	AddPendingTransaction(newTx *types.Transaction) error
 	Blockchain() *core.BlockChain
 	AccountManager() *accounts.Manager
 	GetBalanceOfAddress(address common.Address) (*big.Int, error)
 	GetNonceOfAddress(address common.Address) uint64
+	RewindBlockchain(head uint64) error
 }
 
 // New creates a new Harmony object (including the
@@ -61,7 +62,8 @@ func New(nodeAPI NodeAPI, txPool *core.TxPool, eventMux *event.TypeMux) (*Harmon
 		networkID:      1, // TODO(ricl): this should be from config
 	}
 
-	hmy.APIBackend = &APIBackend{hmy}
+	hmy.APIBackend = &APIBackend{hmy: hmy}
+	hmy.APIBackend.gpo = NewOracle(hmy.APIBackend, DefaultGasPriceConfig)
 
 	return hmy, nil
 }
@@ -0,0 +1,201 @@
+package hmy
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/harmony-one/harmony/common/denominations"
+	"github.com/harmony-one/harmony/core/types"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
+)
+
+// GasPriceConfig is the configuration of the gas price oracle.
+type GasPriceConfig struct {
+	Blocks     int      // number of recent blocks to sample
+	Percentile int      // percentile of the sampled prices to suggest
+	Default    *big.Int // price suggested before any transaction was seen
+	Min        *big.Int // lower bound of the suggested price
+	Max        *big.Int // upper bound of the suggested price
+}
+
+// DefaultGasPriceConfig is the gas price oracle configuration used by nodes.
+var DefaultGasPriceConfig = GasPriceConfig{
+	Blocks:     20,
+	Percentile: 60,
+	Default:    big.NewInt(1),
+	Min:        big.NewInt(1),
+	Max:        big.NewInt(500 * denominations.Nano),
+}
+
+// gasPriceBackend is the part of the API backend the oracle samples blocks from.
+type gasPriceBackend interface {
+	HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Header, error)
+	BlockByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Block, error)
+	HarmonyConfig() *hmyparams.ChainConfig
+}
+
+// Oracle recommends gas prices based on the content of recent blocks.
+// The suggestion is cached until the chain head changes.
+type Oracle struct {
+	backend   gasPriceBackend
+	lastHead  common.Hash
+	lastPrice *big.Int
+	min, max  *big.Int
+	cacheLock sync.RWMutex
+	fetchLock sync.Mutex
+
+	checkBlocks, maxEmpty, maxBlocks int
+	percentile                       int
+}
+
+// NewOracle returns a new gas price oracle which samples the blocks of backend.
+func NewOracle(backend gasPriceBackend, config GasPriceConfig) *Oracle {
+	blocks := config.Blocks
+	if blocks < 1 {
+		blocks = 1
+	}
+	percent := config.Percentile
+	if percent < 0 {
+		percent = 0
+	}
+	if percent > 100 {
+		percent = 100
+	}
+	return &Oracle{
+		backend:     backend,
+		lastPrice:   config.Default,
+		min:         config.Min,
+		max:         config.Max,
+		checkBlocks: blocks,
+		maxEmpty:    blocks / 2,
+		maxBlocks:   blocks * 5,
+		percentile:  percent,
+	}
+}
+
+// SuggestPrice returns the recommended gas price.
+func (gpo *Oracle) SuggestPrice(ctx context.Context) (*big.Int, error) {
+	gpo.cacheLock.RLock()
+	lastHead := gpo.lastHead
+	lastPrice := gpo.lastPrice
+	gpo.cacheLock.RUnlock()
+
+	head, err := gpo.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return lastPrice, err
+	}
+	headHash := head.Hash()
+	if headHash == lastHead {
+		return lastPrice, nil
+	}
+
+	gpo.fetchLock.Lock()
+	defer gpo.fetchLock.Unlock()
+
+	// try checking the cache again, maybe the last fetch fetched what we need
+	gpo.cacheLock.RLock()
+	lastHead = gpo.lastHead
+	lastPrice = gpo.lastPrice
+	gpo.cacheLock.RUnlock()
+	if headHash == lastHead {
+		return lastPrice, nil
+	}
+
+	blockNum := head.Number.Uint64()
+	ch := make(chan getBlockPricesResult, gpo.checkBlocks)
+	sent := 0
+	exp := 0
+	var blockPrices []*big.Int
+	for sent < gpo.checkBlocks && blockNum > 0 {
+		go gpo.getBlockPrices(ctx, blockNum, ch)
+		sent++
+		exp++
+		blockNum--
+	}
+	maxEmpty := gpo.maxEmpty
+	for exp > 0 {
+		res := <-ch
+		if res.err != nil {
+			return lastPrice, res.err
+		}
+		exp--
+		if res.price != nil {
+			blockPrices = append(blockPrices, res.price)
+			continue
+		}
+		if maxEmpty > 0 {
+			maxEmpty--
+			continue
+		}
+		if blockNum > 0 && sent < gpo.maxBlocks {
+			go gpo.getBlockPrices(ctx, blockNum, ch)
+			sent++
+			exp++
+			blockNum--
+		}
+	}
+	price := lastPrice
+	if len(blockPrices) > 0 {
+		sort.Sort(bigIntArray(blockPrices))
+		price = blockPrices[(len(blockPrices)-1)*gpo.percentile/100]
+	}
+	if gpo.max != nil && price.Cmp(gpo.max) > 0 {
+		price = new(big.Int).Set(gpo.max)
+	}
+	if gpo.min != nil && price.Cmp(gpo.min) < 0 {
+		price = new(big.Int).Set(gpo.min)
+	}
+
+	gpo.cacheLock.Lock()
+	gpo.lastHead = headHash
+	gpo.lastPrice = price
+	gpo.cacheLock.Unlock()
+	return price, nil
+}
+
+type getBlockPricesResult struct {
+	price *big.Int
+	err   error
+}
+
+type transactionsByGasPrice []*types.Transaction
+
+func (t transactionsByGasPrice) Len() int           { return len(t) }
+func (t transactionsByGasPrice) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+func (t transactionsByGasPrice) Less(i, j int) bool { return t[i].GasPrice().Cmp(t[j].GasPrice()) < 0 }
+
+// getBlockPrices calculates the lowest transaction gas price in a given block
+// and sends it to the result channel. If the block is empty, price is nil.
+// Transactions sent by the block's coinbase do not compete for space and are
+// ignored.
+func (gpo *Oracle) getBlockPrices(ctx context.Context, blockNum uint64, ch chan getBlockPricesResult) {
+	block, err := gpo.backend.BlockByNumber(ctx, rpc.BlockNumber(blockNum))
+	if block == nil {
+		ch <- getBlockPricesResult{nil, err}
+		return
+	}
+	blockTxs := block.Transactions()
+	txs := make([]*types.Transaction, len(blockTxs))
+	copy(txs, blockTxs)
+	sort.Sort(transactionsByGasPrice(txs))
+
+	signer := types.MakeShardSigner(gpo.backend.HarmonyConfig(), block.ShardID(), block.Header().Epoch)
+	for _, tx := range txs {
+		sender, err := types.Sender(signer, tx)
+		if err == nil && sender != block.Coinbase() {
+			ch <- getBlockPricesResult{tx.GasPrice(), nil}
+			return
+		}
+	}
+	ch <- getBlockPricesResult{nil, nil}
+}
+
+type bigIntArray []*big.Int
+
+func (s bigIntArray) Len() int           { return len(s) }
+func (s bigIntArray) Less(i, j int) bool { return s[i].Cmp(s[j]) < 0 }
+func (s bigIntArray) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,87 @@
+package hmy
+
+import (
+	"context"
+	"math/big"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/harmony-one/harmony/core/types"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
+)
+
+type testGasPriceBackend struct {
+	blocks []*types.Block
+	calls  int32
+}
+
+func (b *testGasPriceBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Header, error) {
+	return b.blocks[len(b.blocks)-1].Header(), nil
+}
+
+func (b *testGasPriceBackend) HarmonyConfig() *hmyparams.ChainConfig {
+	return hmyparams.TestChainConfig
+}
+
+func (b *testGasPriceBackend) BlockByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Block, error) {
+	atomic.AddInt32(&b.calls, 1)
+	return b.blocks[blockNr], nil
+}
+
+// newTestGasPriceBackend returns a chain whose block i holds one transaction
+// priced at prices[i-1].
+func newTestGasPriceBackend(t *testing.T, prices ...int64) *testGasPriceBackend {
+	key, _ := crypto.GenerateKey()
+	signer := types.NewHarmonySigner(hmyparams.TestChainConfig.ChainID, 0)
+	blocks := []*types.Block{types.NewBlock(&types.Header{Number: big.NewInt(0)}, nil, nil)}
+	for i, price := range prices {
+		tx, err := types.SignTx(types.NewTransaction(uint64(i), common.Address{}, 0, big.NewInt(1), 21000, big.NewInt(price), nil), signer, key)
+		if err != nil {
+			t.Fatalf("cannot sign transaction: %v", err)
+		}
+		header := &types.Header{Number: big.NewInt(int64(i + 1)), ParentHash: blocks[i].Hash()}
+		blocks = append(blocks, types.NewBlock(header, []*types.Transaction{tx}, nil))
+	}
+	return &testGasPriceBackend{blocks: blocks}
+}
+
+func TestSuggestPrice(t *testing.T) {
+	config := GasPriceConfig{Blocks: 5, Percentile: 50, Default: big.NewInt(1), Min: big.NewInt(2), Max: big.NewInt(100)}
+	tests := []struct {
+		prices []int64
+		want   int64
+	}{
+		{[]int64{10, 20, 30, 40, 50}, 30},
+		{[]int64{1000, 30, 20, 10, 50}, 30},
+		{[]int64{1, 1, 1, 1, 1}, 2},             // clamped to Min
+		{[]int64{500, 600, 700, 800, 900}, 100}, // clamped to Max
+	}
+	for _, test := range tests {
+		gpo := NewOracle(newTestGasPriceBackend(t, test.prices...), config)
+		price, err := gpo.SuggestPrice(context.Background())
+		if err != nil {
+			t.Fatalf("SuggestPrice(%v) failed: %v", test.prices, err)
+		}
+		if price.Cmp(big.NewInt(test.want)) != 0 {
+			t.Errorf("SuggestPrice(%v) = %v, expected = %v", test.prices, price, test.want)
+		}
+	}
+}
+
+func TestSuggestPriceCachedPerHead(t *testing.T) {
+	backend := newTestGasPriceBackend(t, 10, 20, 30)
+	gpo := NewOracle(backend, DefaultGasPriceConfig)
+	if _, err := gpo.SuggestPrice(context.Background()); err != nil {
+		t.Fatalf("SuggestPrice failed: %v", err)
+	}
+	calls := atomic.LoadInt32(&backend.calls)
+	if _, err := gpo.SuggestPrice(context.Background()); err != nil {
+		t.Fatalf("SuggestPrice failed: %v", err)
+	}
+	if n := atomic.LoadInt32(&backend.calls); n != calls {
+		t.Errorf("blocks fetched again for the same head: %d calls, expected = %d", n, calls)
+	}
+}
@@ -0,0 +1,12 @@
+package shardingconfig
+
+import "github.com/harmony-one/harmony/internal/genesis"
+
+// devnetBlocksPerEpoch is kept small so that local development networks and
+// integration tests go through several epochs quickly.
+const devnetBlocksPerEpoch = 5
+
+var devnetV0 = MustNewInstance(2, 10, 10, devnetBlocksPerEpoch, genesis.GenesisAccounts[:], genesis.GenesisFNAccounts[:])
+
+// DevnetSchedule is the sharding configuration schedule of development networks.
+var DevnetSchedule = NewFixedSchedule(devnetV0)
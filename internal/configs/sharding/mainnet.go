@@ -0,0 +1,13 @@
+package shardingconfig
+
+import "github.com/harmony-one/harmony/internal/genesis"
+
+// mainnetBlocksPerEpoch is inflated to effectively disable resharding until
+// we can 1) fix shard state mutation bug and 2) implement key passphrase
+// recycle across process restart (exec) for shard migration.
+const mainnetBlocksPerEpoch = 1000000000000
+
+var mainnetV0 = MustNewInstance(4, 100, 72, mainnetBlocksPerEpoch, genesis.GenesisAccounts[:], genesis.GenesisFNAccounts[:])
+
+// MainnetSchedule is the sharding configuration schedule of the main network.
+var MainnetSchedule = NewFixedSchedule(mainnetV0)
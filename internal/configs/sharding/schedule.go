@@ -0,0 +1,125 @@
+package shardingconfig
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/harmony-one/harmony/internal/ctxerror"
+)
+
+// ScheduleEntry puts a sharding configuration instance in effect starting at
+// the given epoch.
+type ScheduleEntry struct {
+	StartEpoch uint64
+	Instance   Instance
+}
+
+// scheduleEntry is a ScheduleEntry annotated with the number of its first
+// block, which depends on the epoch lengths of all earlier entries.
+type scheduleEntry struct {
+	ScheduleEntry
+	firstBlock uint64
+}
+
+type schedule struct {
+	entries []scheduleEntry // sorted by StartEpoch (and thus by firstBlock)
+}
+
+// NewSchedule creates and validates a sharding configuration schedule.
+//
+// The first entry must start at the genesis epoch; the others must start at
+// strictly increasing epochs.
+func NewSchedule(entries ...ScheduleEntry) (Schedule, error) {
+	if len(entries) == 0 {
+		return nil, ctxerror.New("sharding schedule must have at least one entry")
+	}
+	if entries[0].StartEpoch != 0 {
+		return nil, ctxerror.New("sharding schedule must start at genesis epoch",
+			"startEpoch", entries[0].StartEpoch)
+	}
+	s := &schedule{}
+	for i, entry := range entries {
+		if entry.Instance == nil {
+			return nil, ctxerror.New("sharding schedule entry has no instance",
+				"index", i)
+		}
+		e := scheduleEntry{ScheduleEntry: entry}
+		if i > 0 {
+			prev := s.entries[i-1]
+			if entry.StartEpoch <= prev.StartEpoch {
+				return nil, ctxerror.New("sharding schedule entries out of order",
+					"index", i, "startEpoch", entry.StartEpoch,
+					"prevStartEpoch", prev.StartEpoch)
+			}
+			e.firstBlock = prev.firstBlock +
+				(entry.StartEpoch-prev.StartEpoch)*prev.Instance.BlocksPerEpoch()
+		}
+		s.entries = append(s.entries, e)
+	}
+	return s, nil
+}
+
+// MustNewSchedule creates a sharding configuration schedule.  It panics if
+// validation fails.  It is intended to be used for static initialization.
+func MustNewSchedule(entries ...ScheduleEntry) Schedule {
+	s, err := NewSchedule(entries...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// NewFixedSchedule returns a sharding configuration schedule that uses the
+// given instance for all epochs.
+func NewFixedSchedule(instance Instance) Schedule {
+	return MustNewSchedule(ScheduleEntry{StartEpoch: 0, Instance: instance})
+}
+
+// entryForEpoch returns the schedule entry in effect during the given epoch.
+func (s *schedule) entryForEpoch(epoch uint64) scheduleEntry {
+	i := sort.Search(len(s.entries), func(i int) bool {
+		return s.entries[i].StartEpoch > epoch
+	})
+	return s.entries[i-1]
+}
+
+// entryForBlock returns the schedule entry in effect for the given block.
+func (s *schedule) entryForBlock(blockNum uint64) scheduleEntry {
+	i := sort.Search(len(s.entries), func(i int) bool {
+		return s.entries[i].firstBlock > blockNum
+	})
+	return s.entries[i-1]
+}
+
+func (s *schedule) InstanceForEpoch(epoch *big.Int) Instance {
+	if !epoch.IsUint64() {
+		if epoch.Sign() < 0 {
+			return s.entries[0].Instance
+		}
+		return s.entries[len(s.entries)-1].Instance
+	}
+	return s.entryForEpoch(epoch.Uint64()).Instance
+}
+
+func (s *schedule) CalcEpochNumber(blockNum uint64) *big.Int {
+	e := s.entryForBlock(blockNum)
+	epoch := e.StartEpoch + (blockNum-e.firstBlock)/e.Instance.BlocksPerEpoch()
+	return new(big.Int).SetUint64(epoch)
+}
+
+func (s *schedule) EpochFirstBlock(epoch uint64) uint64 {
+	e := s.entryForEpoch(epoch)
+	return e.firstBlock + (epoch-e.StartEpoch)*e.Instance.BlocksPerEpoch()
+}
+
+func (s *schedule) EpochLastBlock(epoch uint64) uint64 {
+	return s.EpochFirstBlock(epoch+1) - 1
+}
+
+func (s *schedule) IsFirstBlock(blockNum uint64) bool {
+	return s.EpochFirstBlock(s.CalcEpochNumber(blockNum).Uint64()) == blockNum
+}
+
+func (s *schedule) IsLastBlock(blockNum uint64) bool {
+	return s.EpochLastBlock(s.CalcEpochNumber(blockNum).Uint64()) == blockNum
+}
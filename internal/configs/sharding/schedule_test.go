@@ -0,0 +1,85 @@
+package shardingconfig
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestScheduleEpochBoundaries(t *testing.T) {
+	v0 := instance{numShards: 4, numNodesPerShard: 1, blocksPerEpoch: 10}
+	v1 := instance{numShards: 2, numNodesPerShard: 1, blocksPerEpoch: 5}
+	// Epochs 0-2 have 10 blocks each (blocks 0-29), later epochs 5 blocks each.
+	s := MustNewSchedule(
+		ScheduleEntry{StartEpoch: 0, Instance: v0},
+		ScheduleEntry{StartEpoch: 3, Instance: v1},
+	)
+	tests := []struct {
+		blockNum uint64
+		epoch    uint64
+		first    bool
+		last     bool
+	}{
+		{0, 0, true, false},
+		{9, 0, false, true},
+		{10, 1, true, false},
+		{29, 2, false, true},
+		{30, 3, true, false},
+		{34, 3, false, true},
+		{35, 4, true, false},
+		{52, 7, false, false},
+	}
+	for _, test := range tests {
+		if got := s.CalcEpochNumber(test.blockNum).Uint64(); got != test.epoch {
+			t.Errorf("CalcEpochNumber(%v) = %v, expected = %v", test.blockNum, got, test.epoch)
+		}
+		if got := s.IsFirstBlock(test.blockNum); got != test.first {
+			t.Errorf("IsFirstBlock(%v) = %v, expected = %v", test.blockNum, got, test.first)
+		}
+		if got := s.IsLastBlock(test.blockNum); got != test.last {
+			t.Errorf("IsLastBlock(%v) = %v, expected = %v", test.blockNum, got, test.last)
+		}
+	}
+	if got := s.EpochFirstBlock(4); got != 35 {
+		t.Errorf("EpochFirstBlock(4) = %v, expected = %v", got, 35)
+	}
+	if got := s.EpochLastBlock(2); got != 29 {
+		t.Errorf("EpochLastBlock(2) = %v, expected = %v", got, 29)
+	}
+	if got := s.InstanceForEpoch(big.NewInt(2)).NumShards(); got != 4 {
+		t.Errorf("InstanceForEpoch(2).NumShards() = %v, expected = %v", got, 4)
+	}
+	if got := s.InstanceForEpoch(big.NewInt(3)).NumShards(); got != 2 {
+		t.Errorf("InstanceForEpoch(3).NumShards() = %v, expected = %v", got, 2)
+	}
+}
+
+func TestNewScheduleValidation(t *testing.T) {
+	v0 := instance{numShards: 1, numNodesPerShard: 1, blocksPerEpoch: 10}
+	if _, err := NewSchedule(); err == nil {
+		t.Error("expected error for empty schedule")
+	}
+	if _, err := NewSchedule(ScheduleEntry{StartEpoch: 1, Instance: v0}); err == nil {
+		t.Error("expected error for schedule not starting at genesis")
+	}
+	if _, err := NewSchedule(
+		ScheduleEntry{StartEpoch: 0, Instance: v0},
+		ScheduleEntry{StartEpoch: 0, Instance: v0},
+	); err == nil {
+		t.Error("expected error for out-of-order schedule")
+	}
+}
+
+func TestNetworkSchedules(t *testing.T) {
+	for name, s := range map[string]Schedule{
+		"mainnet": MainnetSchedule,
+		"testnet": TestnetSchedule,
+		"devnet":  DevnetSchedule,
+	} {
+		if s.InstanceForEpoch(big.NewInt(0)) == nil {
+			t.Errorf("%s: no genesis sharding configuration", name)
+		}
+	}
+	if got := DevnetSchedule.CalcEpochNumber(5).Uint64(); got != 1 {
+		t.Errorf("devnet CalcEpochNumber(5) = %v, expected = %v", got, 1)
+	}
+}
@@ -0,0 +1,71 @@
+// Package shardingconfig defines types and utilities that deal with Harmony
+// sharding configuration schedule.
+package shardingconfig
+
+import (
+	"math/big"
+
+	"github.com/harmony-one/harmony/common/config"
+	"github.com/harmony-one/harmony/internal/genesis"
+)
+
+// Schedule returns the sharding configuration instance for the given
+// epoch, and maps block numbers to epochs and back.
+//
+// The sharding configuration, including the epoch length, may change at an
+// epoch boundary; a Schedule accounts for all such changes.
+type Schedule interface {
+	// InstanceForEpoch returns the sharding configuration instance in effect
+	// during the given epoch.
+	InstanceForEpoch(epoch *big.Int) Instance
+
+	// CalcEpochNumber returns the epoch number the given block belongs to.
+	CalcEpochNumber(blockNum uint64) *big.Int
+
+	// EpochFirstBlock returns the number of the first block of the epoch.
+	EpochFirstBlock(epoch uint64) uint64
+
+	// EpochLastBlock returns the number of the last block of the epoch.
+	EpochLastBlock(epoch uint64) uint64
+
+	// IsFirstBlock returns whether the block is the first block of an epoch.
+	IsFirstBlock(blockNum uint64) bool
+
+	// IsLastBlock returns whether the block is the last block of an epoch.
+	IsLastBlock(blockNum uint64) bool
+}
+
+// Instance is one sharding configuration instance.
+type Instance interface {
+	// NumShards returns the number of shards in the network.
+	NumShards() uint32
+
+	// NumNodesPerShard returns number of nodes in each shard.
+	NumNodesPerShard() int
+
+	// NumHarmonyOperatedNodesPerShard returns number of nodes in each shard
+	// that are operated by Harmony.
+	NumHarmonyOperatedNodesPerShard() int
+
+	// BlocksPerEpoch returns the number of blocks in each epoch.
+	BlocksPerEpoch() uint64
+
+	// HmyAccounts returns the list of Harmony accounts
+	HmyAccounts() []genesis.DeployAccount
+
+	// FnAccounts returns the list of Foundational Node accounts
+	FnAccounts() []genesis.DeployAccount
+}
+
+// ScheduleForNetwork returns the sharding configuration schedule of the given
+// network.
+func ScheduleForNetwork(network config.NetworkType) Schedule {
+	switch network {
+	case config.Mainnet:
+		return MainnetSchedule
+	case config.Devnet:
+		return DevnetSchedule
+	default:
+		return TestnetSchedule
+	}
+}
@@ -0,0 +1,12 @@
+package shardingconfig
+
+import "github.com/harmony-one/harmony/internal/genesis"
+
+// testnetBlocksPerEpoch is inflated to effectively disable resharding, like
+// on mainnet.
+const testnetBlocksPerEpoch = 1000000000000
+
+var testnetV0 = MustNewInstance(4, 100, 72, testnetBlocksPerEpoch, genesis.GenesisAccounts[:], genesis.GenesisFNAccounts[:])
+
+// TestnetSchedule is the sharding configuration schedule of the test network.
+var TestnetSchedule = NewFixedSchedule(testnetV0)
@@ -2,6 +2,7 @@ package hmyapi
 
 import (
 	"context"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -13,6 +14,7 @@ import (
 	"github.com/harmony-one/harmony/core"
 	"github.com/harmony-one/harmony/core/state"
 	"github.com/harmony-one/harmony/core/types"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
 )
 
 // Backend interface provides the common API services (that are provided by
@@ -25,7 +27,7 @@ type Backend interface {
 	// General Ethereum API
 	// Downloader() *downloader.Downloader
 	ProtocolVersion() int
-	// SuggestPrice(ctx context.Context) (*big.Int, error)
+	SuggestPrice(ctx context.Context) (*big.Int, error)
 	ChainDb() ethdb.Database
 	EventMux() *event.TypeMux
 	AccountManager() *accounts.Manager
@@ -33,7 +35,7 @@ type Backend interface {
 	// RPCGasCap() *big.Int // global gas cap for eth_call over rpc: DoS protection
 
 	// BlockChain API
-	// SetHead(number uint64)
+	SetHead(number uint64) error
 	HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Header, error)
 	BlockByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Block, error)
 	StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*state.DB, *types.Header, error)
@@ -51,11 +53,13 @@ type Backend interface {
 	GetPoolTransactions() (types.Transactions, error)
 	GetPoolTransaction(txHash common.Hash) *types.Transaction
 	GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error)
-	// Stats() (pending int, queued int)
-	// TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions)
+	Stats() (pending int, queued int)
+	TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions)
+	RemovePoolTransaction(txHash common.Hash) bool
 	SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription
 
 	ChainConfig() *params.ChainConfig
+	HarmonyConfig() *hmyparams.ChainConfig
 	CurrentBlock() *types.Block
 	// Get balance
 	GetBalance(address common.Address) (*hexutil.Big, error)
@@ -91,6 +95,26 @@ func GetAPIs(b Backend) []rpc.API {
 			Version:   "1.0",
 			Service:   NewDebugAPI(b),
 			Public:    true, // FIXME: change to false once IPC implemented
+		}, {
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   NewDebugAPI(b),
+			Public:    false,
+		}, {
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   NewPrivateDebugAPI(b),
+			Public:    false,
+		}, {
+			Namespace: "txpool",
+			Version:   "1.0",
+			Service:   NewPublicTxPoolAPI(b),
+			Public:    true,
+		}, {
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewPrivateTxPoolAPI(b),
+			Public:    false,
 		},
 	}
 }
@@ -1,11 +1,16 @@
 package hmyapi
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/harmony-one/harmony/core/state"
+	"github.com/harmony-one/harmony/core/types"
 )
 
 // PublicBlockChainAPI provides an API to access the Harmony blockchain.
@@ -76,6 +81,117 @@ func (s *PublicBlockChainAPI) GetBalance(ctx context.Context, address common.Add
 	return s.b.GetBalance(address)
 }
 
+// AccountResult is the result of GetProof: an account and the Merkle proofs
+// of the account and of the requested slots of its storage.
+type AccountResult struct {
+	Address      common.Address  `json:"address"`
+	AccountProof []hexutil.Bytes `json:"accountProof"`
+	Balance      *hexutil.Big    `json:"balance"`
+	CodeHash     common.Hash     `json:"codeHash"`
+	Nonce        hexutil.Uint64  `json:"nonce"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	StorageProof []StorageResult `json:"storageProof"`
+}
+
+// StorageResult is a storage slot and its Merkle proof.
+type StorageResult struct {
+	Key   common.Hash     `json:"key"`
+	Value common.Hash     `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
+// GetProof returns the account at the given address in the state of the given
+// block number, along with the Merkle proofs of the account and of the given
+// storage slots. The proofs can be checked against the block header's Root
+// with AccountResult.Verify.
+func (s *PublicBlockChainAPI) GetProof(ctx context.Context, address common.Address, storageKeys []common.Hash, blockNr rpc.BlockNumber) (*AccountResult, error) {
+	stateDB, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if stateDB == nil || err != nil {
+		return nil, err
+	}
+	storageTrie := stateDB.StorageTrie(address)
+	storageHash := types.EmptyRootHash
+	codeHash := stateDB.GetCodeHash(address)
+	if storageTrie != nil {
+		storageHash = storageTrie.Hash()
+	} else {
+		// The account does not exist.
+		codeHash = crypto.Keccak256Hash(nil)
+	}
+	storageProof := make([]StorageResult, len(storageKeys))
+	for i, key := range storageKeys {
+		storageProof[i] = StorageResult{Key: key, Proof: []hexutil.Bytes{}}
+		if storageTrie == nil {
+			continue
+		}
+		proof, err := stateDB.GetStorageProof(address, key)
+		if err != nil {
+			return nil, err
+		}
+		storageProof[i].Value = stateDB.GetState(address, key)
+		storageProof[i].Proof = toHexBytes(proof)
+	}
+	accountProof, err := stateDB.GetProof(address)
+	if err != nil {
+		return nil, err
+	}
+	return &AccountResult{
+		Address:      address,
+		AccountProof: toHexBytes(accountProof),
+		Balance:      (*hexutil.Big)(stateDB.GetBalance(address)),
+		CodeHash:     codeHash,
+		Nonce:        hexutil.Uint64(stateDB.GetNonce(address)),
+		StorageHash:  storageHash,
+		StorageProof: storageProof,
+	}, stateDB.Error()
+}
+
+// Verify checks the proofs of r against the state root of a block header, and
+// that they prove the account and storage values r reports.
+func (r *AccountResult) Verify(root common.Hash) error {
+	proof := make([][]byte, len(r.AccountProof))
+	for i, node := range r.AccountProof {
+		proof[i] = node
+	}
+	account, err := state.VerifyAccountProof(root, r.Address, proof)
+	if err != nil {
+		return fmt.Errorf("invalid account proof: %v", err)
+	}
+	switch {
+	case r.Balance == nil || account.Balance.Cmp(r.Balance.ToInt()) != 0:
+		return fmt.Errorf("balance %v does not match proven balance %v", r.Balance, account.Balance)
+	case account.Nonce != uint64(r.Nonce):
+		return fmt.Errorf("nonce %d does not match proven nonce %d", r.Nonce, account.Nonce)
+	case account.Root != r.StorageHash:
+		return fmt.Errorf("storage hash %x does not match proven storage hash %x", r.StorageHash, account.Root)
+	case !bytes.Equal(account.CodeHash, r.CodeHash.Bytes()):
+		return fmt.Errorf("code hash %x does not match proven code hash %x", r.CodeHash, account.CodeHash)
+	}
+	for _, storage := range r.StorageProof {
+		proof := make([][]byte, len(storage.Proof))
+		for i, node := range storage.Proof {
+			proof[i] = node
+		}
+		value, err := state.VerifyStorageProof(account.Root, storage.Key, proof)
+		if err != nil {
+			return fmt.Errorf("invalid storage proof of %x: %v", storage.Key, err)
+		}
+		if value != storage.Value {
+			return fmt.Errorf("storage value %x of %x does not match proven value %x", storage.Value, storage.Key, value)
+		}
+	}
+	return nil
+}
+
+// toHexBytes converts a Merkle proof to its RPC representation.
+func toHexBytes(proof [][]byte) []hexutil.Bytes {
+	result := make([]hexutil.Bytes, len(proof))
+	for i, node := range proof {
+		result[i] = node
+	}
+	return result
+}
+
 // BlockNumber returns the block number of the chain head.
 func (s *PublicBlockChainAPI) BlockNumber() hexutil.Uint64 {
 	header, _ := s.b.HeaderByNumber(context.Background(), rpc.LatestBlockNumber) // latest header should always be available
@@ -4,7 +4,11 @@ import (
 	"context"
 	"errors"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/harmony/core/rawdb"
 	"github.com/harmony-one/harmony/internal/utils"
 )
 
@@ -30,3 +34,61 @@ func (*DebugAPI) SetLogVerbosity(ctx context.Context, level int) (map[string]int
 	utils.SetLogVerbosity(verbosity)
 	return map[string]interface{}{"verbosity": verbosity.String()}, nil
 }
+
+// PrivateDebugAPI is the collection of debugging RPC methods that expose node
+// internals and are only served when the operator turns the debug namespace on.
+type PrivateDebugAPI struct {
+	b Backend
+}
+
+// NewPrivateDebugAPI creates a new PrivateDebugAPI instance.
+func NewPrivateDebugAPI(b Backend) *PrivateDebugAPI {
+	return &PrivateDebugAPI{b}
+}
+
+// BadBlockArgs represents the entries in the list returned when bad blocks are queried.
+type BadBlockArgs struct {
+	Hash   common.Hash            `json:"hash"`
+	Block  map[string]interface{} `json:"block"`
+	RLP    string                 `json:"rlp"`
+	Error  string                 `json:"error"`
+	Source string                 `json:"source"`
+	Time   hexutil.Uint64         `json:"time"`
+}
+
+// GetBadBlocks returns the bad blocks the node has seen on the network, newest
+// first, together with the validation error and the leader or peer that sent them.
+// Example usage:
+//  curl -H "Content-Type: application/json" -d '{"method":"debug_getBadBlocks","params":[],"id":1}' http://localhost:9123
+func (s *PrivateDebugAPI) GetBadBlocks(ctx context.Context) ([]*BadBlockArgs, error) {
+	badBlocks := rawdb.ReadAllBadBlocks(s.b.ChainDb())
+	results := make([]*BadBlockArgs, len(badBlocks))
+	for i, badBlock := range badBlocks {
+		blockRlp, err := rlp.EncodeToBytes(badBlock.Block)
+		if err != nil {
+			return nil, err
+		}
+		blockJSON, err := RPCMarshalBlock(badBlock.Block, true, true)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = &BadBlockArgs{
+			Hash:   badBlock.Block.Hash(),
+			Block:  blockJSON,
+			RLP:    hexutil.Encode(blockRlp),
+			Error:  badBlock.Error,
+			Source: badBlock.Source,
+			Time:   hexutil.Uint64(badBlock.Time),
+		}
+	}
+	return results, nil
+}
+
+// SetHead rewinds the head of the blockchain to the given block number,
+// discarding the blocks above it. It refuses to rewind past the block that
+// committed the shard state of the current epoch.
+// Example usage:
+//  curl -H "Content-Type: application/json" -d '{"method":"debug_setHead","params":["0x100"],"id":1}' http://localhost:9123
+func (s *PrivateDebugAPI) SetHead(ctx context.Context, number hexutil.Uint64) error {
+	return s.b.SetHead(uint64(number))
+}
@@ -2,7 +2,6 @@ package hmyapi
 
 import (
 	"context"
-	"math/big"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/harmony-one/harmony/api/proto"
@@ -38,6 +37,6 @@ func (s *PublicHarmonyAPI) Syncing() (interface{}, error) {
 
 // GasPrice returns a suggestion for a gas price.
 func (s *PublicHarmonyAPI) GasPrice(ctx context.Context) (*hexutil.Big, error) {
-	// TODO(ricl): add SuggestPrice API
-	return (*hexutil.Big)(big.NewInt(1)), nil
+	price, err := s.b.SuggestPrice(ctx)
+	return (*hexutil.Big)(price), err
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/harmony-one/harmony/p2p"
+	libp2p_peer "github.com/libp2p/go-libp2p-peer"
 )
 
 // PublicNetAPI offers network related RPC methods
@@ -27,3 +28,87 @@ func (s *PublicNetAPI) PeerCount() hexutil.Uint {
 func (s *PublicNetAPI) Version() string {
 	return fmt.Sprintf("%d", s.networkVersion) // TODO(ricl): we should add support for network id (https://github.com/ethereum/wiki/wiki/JSON-RPC#net_version)
 }
+
+// PrivateNetAPI offers network related administrative RPC methods.
+// It must not be exposed on public endpoints.
+type PrivateNetAPI struct {
+	net p2p.Host
+}
+
+// NewPrivateNetAPI creates a new network administration API.
+func NewPrivateNetAPI(net p2p.Host) *PrivateNetAPI {
+	return &PrivateNetAPI{net}
+}
+
+// PeerScoreResult is the score of a peer.
+type PeerScoreResult struct {
+	ID          string  `json:"id"`
+	Score       float64 `json:"score"`
+	BannedUntil int64   `json:"bannedUntil,omitempty"` // unix time, if the peer is banned
+}
+
+// PeerScores returns the scores of the known peers, lowest first.
+// Example usage:
+//  curl -H "Content-Type: application/json" -d '{"method":"admin_peerScores","params":[],"id":1}' http://localhost:9500
+func (s *PrivateNetAPI) PeerScores() []PeerScoreResult {
+	scores := s.net.GetPeerScorer().Scores()
+	result := make([]PeerScoreResult, 0, len(scores))
+	for _, score := range scores {
+		r := PeerScoreResult{ID: score.ID.Pretty(), Score: score.Score}
+		if !score.BannedUntil.IsZero() {
+			r.BannedUntil = score.BannedUntil.Unix()
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// UnbanPeer lifts the ban of a peer and resets its score.
+func (s *PrivateNetAPI) UnbanPeer(id string) error {
+	peerID, err := libp2p_peer.IDB58Decode(id)
+	if err != nil {
+		return err
+	}
+	s.net.GetPeerScorer().Unban(peerID)
+	return nil
+}
+
+// GroupTrafficResult are the message counters of a group.
+type GroupTrafficResult struct {
+	Published      uint64 `json:"published"`
+	PublishedBytes uint64 `json:"publishedBytes"`
+	Received       uint64 `json:"received"`
+	ReceivedBytes  uint64 `json:"receivedBytes"`
+	Duplicates     uint64 `json:"duplicates"`
+	Invalid        uint64 `json:"invalid"`
+}
+
+// TrafficResult are the traffic counters of the node.
+type TrafficResult struct {
+	TotalIn   int64                         `json:"totalIn"`
+	TotalOut  int64                         `json:"totalOut"`
+	RateIn    float64                       `json:"rateIn"`
+	RateOut   float64                       `json:"rateOut"`
+	PubSubIn  int64                         `json:"pubSubIn"`
+	PubSubOut int64                         `json:"pubSubOut"`
+	Groups    map[string]GroupTrafficResult `json:"groups"`
+}
+
+// TrafficStats returns the bytes sent and received by the node, in total and
+// by group messaging, and the message counters of the groups.
+func (s *PrivateNetAPI) TrafficStats() TrafficResult {
+	stats := s.net.GetTrafficStats()
+	result := TrafficResult{
+		TotalIn:   stats.TotalIn,
+		TotalOut:  stats.TotalOut,
+		RateIn:    stats.RateIn,
+		RateOut:   stats.RateOut,
+		PubSubIn:  stats.PubSubIn,
+		PubSubOut: stats.PubSubOut,
+		Groups:    make(map[string]GroupTrafficResult, len(stats.Groups)),
+	}
+	for group, g := range stats.Groups {
+		result.Groups[string(group)] = GroupTrafficResult(g)
+	}
+	return result
+}
@@ -2,6 +2,8 @@ package hmyapi
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -71,6 +73,71 @@ func (s *PublicTransactionPoolAPI) GetTransactionByHash(ctx context.Context, has
 	return nil
 }
 
+const (
+	// defaultTxHistoryPageSize is the page size of the transaction history
+	// when the caller does not give one.
+	defaultTxHistoryPageSize = 100
+	// maxTxHistoryPageSize caps the page size of the transaction history.
+	maxTxHistoryPageSize = 1000
+)
+
+// TransactionsHistory is one page of the transaction history of an address.
+type TransactionsHistory struct {
+	Total        hexutil.Uint64    `json:"total"`
+	Transactions []*RPCTransaction `json:"transactions"`
+}
+
+// GetTransactionsHistory returns a page of the transactions sent from or to the
+// given address, in chain order ("ASC", the default) or newest first ("DESC").
+// The history is served from the address transaction history index and only
+// covers blocks indexed while the node had it turned on.
+func (s *PublicTransactionPoolAPI) GetTransactionsHistory(ctx context.Context, address common.Address, pageIndex uint64, pageSize uint64, order string) (*TransactionsHistory, error) {
+	if pageSize == 0 {
+		pageSize = defaultTxHistoryPageSize
+	}
+	if pageSize > maxTxHistoryPageSize {
+		pageSize = maxTxHistoryPageSize
+	}
+	var desc bool
+	switch strings.ToUpper(order) {
+	case "", "ASC":
+	case "DESC":
+		desc = true
+	default:
+		return nil, fmt.Errorf("invalid order %q, expected ASC or DESC", order)
+	}
+	db := s.b.ChainDb()
+	total := rawdb.ReadAddressTxCount(db, address)
+	history := &TransactionsHistory{
+		Total:        hexutil.Uint64(total),
+		Transactions: []*RPCTransaction{},
+	}
+	if pageIndex > total/pageSize {
+		return history, nil
+	}
+	for i := pageIndex * pageSize; i < total && i < (pageIndex+1)*pageSize; i++ {
+		seq := i
+		if desc {
+			seq = total - 1 - i
+		}
+		entry := rawdb.ReadAddressTxEntry(db, address, seq)
+		if entry == nil {
+			continue
+		}
+		block, err := s.b.GetBlock(ctx, entry.BlockHash)
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			continue
+		}
+		if tx := newRPCTransactionFromBlockIndex(block, entry.Index); tx != nil {
+			history.Transactions = append(history.Transactions, tx)
+		}
+	}
+	return history, nil
+}
+
 // GetTransactionCount returns the number of transactions the given address has sent for the given block number
 func (s *PublicTransactionPoolAPI) GetTransactionCount(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (*hexutil.Uint64, error) {
 	// Ask transaction pool for the nonce which includes pending transactions
@@ -115,7 +182,7 @@ func (s *PublicTransactionPoolAPI) SendTransaction(ctx context.Context, args Sen
 	// Assemble the transaction and sign with the wallet
 	tx := args.toTransaction()
 
-	signed, err := wallet.SignTx(account, tx, s.b.ChainConfig().ChainID)
+	signed, err := wallet.SignTx(account, tx, s.b.HarmonyConfig().ChainID)
 	if err != nil {
 		return common.Hash{}, err
 	}
@@ -149,7 +216,7 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 
 	var signer types.Signer = types.FrontierSigner{}
 	if tx.Protected() {
-		signer = types.NewEIP155Signer(tx.ChainID())
+		signer = types.NewHarmonySigner(tx.ChainID(), tx.ShardID())
 	}
 	from, _ := types.Sender(signer, tx)
 
@@ -201,7 +268,7 @@ func (s *PublicTransactionPoolAPI) PendingTransactions() ([]*RPCTransaction, err
 	for _, tx := range pending {
 		var signer types.Signer = types.HomesteadSigner{}
 		if tx.Protected() {
-			signer = types.NewEIP155Signer(tx.ChainID())
+			signer = types.NewHarmonySigner(tx.ChainID(), tx.ShardID())
 		}
 		from, _ := types.Sender(signer, tx)
 		if _, exists := accounts[from]; exists {
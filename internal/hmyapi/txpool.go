@@ -0,0 +1,121 @@
+package hmyapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/harmony-one/harmony/core/types"
+)
+
+// PublicTxPoolAPI offers an API to inspect the transaction pool.
+type PublicTxPoolAPI struct {
+	b Backend
+}
+
+// NewPublicTxPoolAPI creates a new transaction pool inspection API.
+func NewPublicTxPoolAPI(b Backend) *PublicTxPoolAPI {
+	return &PublicTxPoolAPI{b}
+}
+
+// Status returns the number of pending and queued transactions in the pool.
+func (s *PublicTxPoolAPI) Status() map[string]hexutil.Uint {
+	pending, queued := s.b.Stats()
+	return map[string]hexutil.Uint{
+		"pending": hexutil.Uint(pending),
+		"queued":  hexutil.Uint(queued),
+	}
+}
+
+// Content returns the pending and queued transactions in the pool, grouped by
+// sender and keyed by nonce.
+func (s *PublicTxPoolAPI) Content() map[string]map[string]map[string]*RPCTransaction {
+	pending, queue := s.b.TxPoolContent()
+	return map[string]map[string]map[string]*RPCTransaction{
+		"pending": rpcTxsBySender(pending),
+		"queued":  rpcTxsBySender(queue),
+	}
+}
+
+// ContentFrom returns the pending and queued transactions of an address in the
+// pool, keyed by nonce.
+func (s *PublicTxPoolAPI) ContentFrom(address common.Address) map[string]map[string]*RPCTransaction {
+	pending, queue := s.b.TxPoolContent()
+	return map[string]map[string]*RPCTransaction{
+		"pending": rpcTxsByNonce(pending[address]),
+		"queued":  rpcTxsByNonce(queue[address]),
+	}
+}
+
+// Inspect returns a textual summary of the pending and queued transactions in
+// the pool, grouped by sender and keyed by nonce, to quickly spot nonce gaps.
+func (s *PublicTxPoolAPI) Inspect() map[string]map[string]map[string]string {
+	pending, queue := s.b.TxPoolContent()
+	return map[string]map[string]map[string]string{
+		"pending": summarizeTxsBySender(pending),
+		"queued":  summarizeTxsBySender(queue),
+	}
+}
+
+// PrivateTxPoolAPI offers administrative operations on the transaction pool.
+// It must not be exposed on public endpoints.
+type PrivateTxPoolAPI struct {
+	b Backend
+}
+
+// NewPrivateTxPoolAPI creates a new transaction pool administration API.
+func NewPrivateTxPoolAPI(b Backend) *PrivateTxPoolAPI {
+	return &PrivateTxPoolAPI{b}
+}
+
+// DropTransaction removes a transaction from the pool, such as one stuck
+// behind a nonce gap. The later pending transactions of its sender move back
+// to the queue. It reports whether the transaction was in the pool.
+// Example usage:
+//  curl -H "Content-Type: application/json" -d '{"method":"admin_dropTransaction","params":["0x..."],"id":1}' http://localhost:9500
+func (s *PrivateTxPoolAPI) DropTransaction(ctx context.Context, hash common.Hash) bool {
+	return s.b.RemovePoolTransaction(hash)
+}
+
+// rpcTxsBySender converts pool transactions to their RPC representation,
+// keyed by sender and nonce.
+func rpcTxsBySender(txsBySender map[common.Address]types.Transactions) map[string]map[string]*RPCTransaction {
+	result := make(map[string]map[string]*RPCTransaction, len(txsBySender))
+	for sender, txs := range txsBySender {
+		result[sender.Hex()] = rpcTxsByNonce(txs)
+	}
+	return result
+}
+
+// rpcTxsByNonce converts pool transactions to their RPC representation, keyed
+// by nonce.
+func rpcTxsByNonce(txs types.Transactions) map[string]*RPCTransaction {
+	result := make(map[string]*RPCTransaction, len(txs))
+	for _, tx := range txs {
+		result[fmt.Sprint(tx.Nonce())] = newRPCPendingTransaction(tx)
+	}
+	return result
+}
+
+// summarizeTxsBySender summarizes pool transactions, keyed by sender and nonce.
+func summarizeTxsBySender(txsBySender map[common.Address]types.Transactions) map[string]map[string]string {
+	result := make(map[string]map[string]string, len(txsBySender))
+	for sender, txs := range txsBySender {
+		summaries := make(map[string]string, len(txs))
+		for _, tx := range txs {
+			summaries[fmt.Sprint(tx.Nonce())] = summarizeTx(tx)
+		}
+		result[sender.Hex()] = summaries
+	}
+	return result
+}
+
+// summarizeTx returns a one-line summary of a pool transaction.
+func summarizeTx(tx *types.Transaction) string {
+	to := "contract creation"
+	if tx.To() != nil {
+		to = tx.To().Hex()
+	}
+	return fmt.Sprintf("%s: %v wei + %v gas × %v wei", to, tx.Value(), tx.Gas(), tx.GasPrice())
+}
@@ -16,7 +16,8 @@ func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (c
 		return common.Hash{}, err
 	}
 	if tx.To() == nil {
-		signer := types.MakeSigner(b.ChainConfig(), b.CurrentBlock().Number())
+		header := b.CurrentBlock().Header()
+		signer := types.MakeShardSigner(b.HarmonyConfig(), header.ShardID, header.Epoch)
 		from, err := types.Sender(signer, tx)
 		if err != nil {
 			return common.Hash{}, err
@@ -0,0 +1,227 @@
+// Package params holds the Harmony chain configuration.
+//
+// Unlike go-ethereum's params.ChainConfig, which schedules hard forks by block
+// number, Harmony schedules protocol upgrades by epoch so that all shards
+// switch over at the same, coordinated point in time.
+package params
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/harmony-one/harmony/common/config"
+	"github.com/harmony-one/harmony/common/denominations"
+)
+
+// EpochTBD is a large, not-yet-scheduled epoch; forks set to it are not
+// activated on the network yet.
+//
+// Mainnet and testnet do not leave epoch 0 while their sharding schedules
+// inflate BlocksPerEpoch to disable resharding, so their forks stay at
+// EpochTBD and are inert there: replay protection and gas targets are only
+// enforced on devnet until a release schedules them with the resharding epochs.
+var EpochTBD = big.NewInt(10000000)
+
+// DefaultBlockReward is the reward paid to each committee member that signed
+// a block, on chains whose configuration does not set one.
+var DefaultBlockReward = big.NewInt(denominations.One / 10)
+
+var (
+	// MainnetChainConfig is the chain parameters to run a node on the main network.
+	MainnetChainConfig = &ChainConfig{
+		ChainID:               config.Mainnet.ChainID(),
+		ReplayProtectionEpoch: EpochTBD,
+		GasTargetEpoch:        EpochTBD,
+		BlockReward:           DefaultBlockReward,
+	}
+
+	// TestnetChainConfig is the chain parameters to run a node on the test network.
+	TestnetChainConfig = &ChainConfig{
+		ChainID:               config.Testnet.ChainID(),
+		ReplayProtectionEpoch: EpochTBD,
+		GasTargetEpoch:        EpochTBD,
+		BlockReward:           DefaultBlockReward,
+	}
+
+	// DevnetChainConfig is the chain parameters to run a node on a development
+	// network, where every fork is active from genesis.
+	DevnetChainConfig = &ChainConfig{
+		ChainID:               config.Devnet.ChainID(),
+		ReplayProtectionEpoch: big.NewInt(0),
+		GasTargetEpoch:        big.NewInt(0),
+		BlockReward:           DefaultBlockReward,
+	}
+
+	// TestChainConfig is the chain parameters used in tests. Forks that would
+	// reject legacy test fixtures (such as unprotected transactions) are left
+	// unscheduled.
+	TestChainConfig = &ChainConfig{
+		ChainID:     config.Testnet.ChainID(),
+		BlockReward: DefaultBlockReward,
+	}
+)
+
+// DefaultGasTargetPolicy is the gas target policy of shards without one of
+// their own. Its ceiling fits about 9500 plain transfers per block.
+var DefaultGasTargetPolicy = GasTargetPolicy{
+	MinGasLimit:       20000000,
+	MaxGasLimit:       200000000,
+	TargetUtilization: 50,
+}
+
+// GasTargetPolicy bounds the gas limit of the blocks of a shard. Leaders raise
+// the gas limit when blocks use more than the target share of it and lower it
+// when they use less, by at most 1/1024 of the parent's limit per block.
+type GasTargetPolicy struct {
+	MinGasLimit       uint64 `json:"minGasLimit"`
+	MaxGasLimit       uint64 `json:"maxGasLimit"`
+	TargetUtilization uint64 `json:"targetUtilization"` // percent of the gas limit
+}
+
+// ChainConfigByNetwork returns the chain configuration of the given network.
+func ChainConfigByNetwork(network config.NetworkType) *ChainConfig {
+	switch network {
+	case config.Mainnet:
+		return MainnetChainConfig
+	case config.Devnet:
+		return DevnetChainConfig
+	default:
+		return TestnetChainConfig
+	}
+}
+
+// ChainConfig is the core config which determines the blockchain settings.
+//
+// ChainConfig is stored in the database on a per block basis. This means
+// that any network, identified by its genesis block, can have its own
+// set of configuration options.
+type ChainConfig struct {
+	// ChainID identifies the network; replay-protected transactions commit to it.
+	ChainID *big.Int `json:"chainId"`
+
+	// ReplayProtectionEpoch is the first epoch in which transactions must be
+	// signed with chain ID and shard ID; unprotected transactions are rejected.
+	ReplayProtectionEpoch *big.Int `json:"replayProtectionEpoch,omitempty"`
+
+	// GasTargetEpoch is the first epoch in which block gas limits follow the
+	// gas target policy of their shard, and validators enforce its bounds.
+	GasTargetEpoch *big.Int `json:"gasTargetEpoch,omitempty"`
+
+	// GasTargets holds the gas target policies of shards that do not use
+	// DefaultGasTargetPolicy, by shard ID.
+	GasTargets map[uint32]GasTargetPolicy `json:"gasTargets,omitempty"`
+
+	// BlockReward is the reward paid to each committee member that signed the
+	// parent of a block.
+	BlockReward *big.Int `json:"blockReward,omitempty"`
+}
+
+// String implements the fmt.Stringer interface.
+func (c *ChainConfig) String() string {
+	return fmt.Sprintf("{ChainID: %v ReplayProtectionEpoch: %v GasTargetEpoch: %v BlockReward: %v}",
+		c.ChainID,
+		c.ReplayProtectionEpoch,
+		c.GasTargetEpoch,
+		c.BlockReward,
+	)
+}
+
+// IsReplayProtection returns whether epoch requires replay-protected transactions.
+func (c *ChainConfig) IsReplayProtection(epoch *big.Int) bool {
+	return isForked(c.ReplayProtectionEpoch, epoch)
+}
+
+// IsGasTarget returns whether block gas limits follow the gas target policy in
+// epoch.
+func (c *ChainConfig) IsGasTarget(epoch *big.Int) bool {
+	return isForked(c.GasTargetEpoch, epoch)
+}
+
+// GasTargetPolicy returns the gas target policy of the given shard.
+func (c *ChainConfig) GasTargetPolicy(shardID uint32) GasTargetPolicy {
+	if policy, ok := c.GasTargets[shardID]; ok {
+		return policy
+	}
+	return DefaultGasTargetPolicy
+}
+
+// SignerReward returns the reward paid to each signer of a block, which is
+// DefaultBlockReward for configurations stored before it was configurable.
+func (c *ChainConfig) SignerReward() *big.Int {
+	if c.BlockReward == nil {
+		return DefaultBlockReward
+	}
+	return c.BlockReward
+}
+
+// CheckCompatible checks whether scheduled fork transitions have been imported
+// with a mismatching chain configuration.
+func (c *ChainConfig) CheckCompatible(newcfg *ChainConfig, epoch uint64) *ConfigCompatError {
+	bhead := new(big.Int).SetUint64(epoch)
+	if c.ChainID.Cmp(newcfg.ChainID) != 0 {
+		return &ConfigCompatError{What: "chain ID", StoredConfig: c.ChainID, NewConfig: newcfg.ChainID}
+	}
+	if isForkIncompatible(c.ReplayProtectionEpoch, newcfg.ReplayProtectionEpoch, bhead) {
+		return newCompatError("replay protection fork epoch", c.ReplayProtectionEpoch, newcfg.ReplayProtectionEpoch)
+	}
+	if isForkIncompatible(c.GasTargetEpoch, newcfg.GasTargetEpoch, bhead) {
+		return newCompatError("gas target fork epoch", c.GasTargetEpoch, newcfg.GasTargetEpoch)
+	}
+	return nil
+}
+
+// isForkIncompatible returns true if a fork scheduled at s1 cannot be
+// rescheduled to epoch s2 because head is already past the fork.
+func isForkIncompatible(s1, s2, head *big.Int) bool {
+	return (isForked(s1, head) || isForked(s2, head)) && !configNumEqual(s1, s2)
+}
+
+// isForked returns whether a fork scheduled at epoch s is active at the given
+// head epoch.
+func isForked(s, epoch *big.Int) bool {
+	if s == nil || epoch == nil {
+		return false
+	}
+	return s.Cmp(epoch) <= 0
+}
+
+func configNumEqual(x, y *big.Int) bool {
+	if x == nil {
+		return y == nil
+	}
+	if y == nil {
+		return x == nil
+	}
+	return x.Cmp(y) == 0
+}
+
+// ConfigCompatError is raised if the locally-stored blockchain is initialised
+// with a ChainConfig that would alter the past.
+type ConfigCompatError struct {
+	What string
+	// epoch numbers of the stored and new configurations
+	StoredConfig, NewConfig *big.Int
+	// the epoch to which the local chain must be rewound to correct the error
+	RewindTo uint64
+}
+
+func newCompatError(what string, storedepoch, newepoch *big.Int) *ConfigCompatError {
+	var rew *big.Int
+	switch {
+	case storedepoch == nil:
+		rew = newepoch
+	case newepoch == nil || storedepoch.Cmp(newepoch) < 0:
+		rew = storedepoch
+	default:
+		rew = newepoch
+	}
+	err := &ConfigCompatError{What: what, StoredConfig: storedepoch, NewConfig: newepoch}
+	if rew != nil && rew.Sign() > 0 {
+		err.RewindTo = rew.Uint64() - 1
+	}
+	return err
+}
+
+func (err *ConfigCompatError) Error() string {
+	return fmt.Sprintf("mismatching %s in database (have %d, want %d, rewindto epoch %d)", err.What, err.StoredConfig, err.NewConfig, err.RewindTo)
+}
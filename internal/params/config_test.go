@@ -0,0 +1,74 @@
+package params
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestIsReplayProtection(t *testing.T) {
+	c := &ChainConfig{ChainID: big.NewInt(2), ReplayProtectionEpoch: big.NewInt(3)}
+	tests := []struct {
+		epoch *big.Int
+		want  bool
+	}{
+		{big.NewInt(0), false},
+		{big.NewInt(2), false},
+		{big.NewInt(3), true},
+		{big.NewInt(100), true},
+		{nil, false},
+	}
+	for _, test := range tests {
+		if got := c.IsReplayProtection(test.epoch); got != test.want {
+			t.Errorf("IsReplayProtection(%v) = %v, expected = %v", test.epoch, got, test.want)
+		}
+	}
+	if TestChainConfig.IsReplayProtection(big.NewInt(100)) {
+		t.Error("replay protection should not be scheduled in TestChainConfig")
+	}
+}
+
+func TestCheckCompatible(t *testing.T) {
+	stored := &ChainConfig{ChainID: big.NewInt(2), ReplayProtectionEpoch: big.NewInt(10)}
+
+	// Rescheduling a fork that is still in the future is fine.
+	newcfg := &ChainConfig{ChainID: big.NewInt(2), ReplayProtectionEpoch: big.NewInt(20)}
+	if err := stored.CheckCompatible(newcfg, 5); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Rescheduling a fork that already happened is not.
+	err := stored.CheckCompatible(newcfg, 15)
+	if err == nil {
+		t.Fatal("expected compatibility error")
+	}
+	if err.RewindTo != 9 {
+		t.Errorf("RewindTo = %v, expected = %v", err.RewindTo, 9)
+	}
+
+	// Neither is switching networks.
+	if err := stored.CheckCompatible(DevnetChainConfig, 0); err == nil {
+		t.Error("expected chain ID compatibility error")
+	}
+}
+
+func TestGasTargetPolicy(t *testing.T) {
+	custom := GasTargetPolicy{MinGasLimit: 1000, MaxGasLimit: 2000, TargetUtilization: 80}
+	c := &ChainConfig{ChainID: big.NewInt(2), GasTargets: map[uint32]GasTargetPolicy{1: custom}}
+	if got := c.GasTargetPolicy(1); got != custom {
+		t.Errorf("GasTargetPolicy(1) = %+v, expected = %+v", got, custom)
+	}
+	if got := c.GasTargetPolicy(0); got != DefaultGasTargetPolicy {
+		t.Errorf("GasTargetPolicy(0) = %+v, expected = %+v", got, DefaultGasTargetPolicy)
+	}
+}
+
+func TestSignerReward(t *testing.T) {
+	c := &ChainConfig{ChainID: big.NewInt(2)}
+	if got := c.SignerReward(); got.Cmp(DefaultBlockReward) != 0 {
+		t.Errorf("SignerReward() = %v, expected = %v", got, DefaultBlockReward)
+	}
+	c.BlockReward = big.NewInt(5)
+	if got := c.SignerReward(); got.Cmp(big.NewInt(5)) != 0 {
+		t.Errorf("SignerReward() = %v, expected = %v", got, 5)
+	}
+}
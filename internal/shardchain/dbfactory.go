@@ -21,8 +21,12 @@ type LDBFactory struct {
 
 // NewChainDB returns a new LDB for the blockchain for given shard.
 func (f *LDBFactory) NewChainDB(shardID uint32) (ethdb.Database, error) {
-	dir := path.Join(f.RootDir, fmt.Sprintf("harmony_db_%d", shardID))
-	return ethdb.NewLDBDatabase(dir, 0, 0)
+	return ethdb.NewLDBDatabase(f.ChainDBDir(shardID), 0, 0)
+}
+
+// ChainDBDir returns the directory of the LDB for the given shard.
+func (f *LDBFactory) ChainDBDir(shardID uint32) string {
+	return path.Join(f.RootDir, fmt.Sprintf("harmony_db_%d", shardID))
 }
 
 // MemDBFactory is a memory-backed blockchain database factory.
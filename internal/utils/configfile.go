@@ -12,6 +12,8 @@ import (
 // WalletProfile contains a section and key value pair map
 type WalletProfile struct {
 	Profile   string
+	Network   string // network type, testnet unless given
+	Genesis   string // network genesis file of a custom network, if any
 	Bootnodes []string
 	Shards    int
 	RPCServer [][]p2p.Peer
@@ -32,6 +34,14 @@ func ReadWalletProfile(iniBytes []byte, profile string) (*WalletProfile, error)
 		return nil, err
 	}
 
+	config.Network = "testnet"
+	if sec.HasKey("network") {
+		config.Network = sec.Key("network").String()
+	}
+	if sec.HasKey("genesis") {
+		config.Genesis = sec.Key("genesis").String()
+	}
+
 	if sec.HasKey("bootnode") {
 		config.Bootnodes = sec.Key("bootnode").ValueWithShadows()
 	} else {
@@ -12,6 +12,7 @@ func TestReadWalletProfile(t *testing.T) {
 	config := []*WalletProfile{
 		{
 			Profile:   "default",
+			Network:   "testnet",
 			Bootnodes: []string{"127.0.0.1:9000/abcd", "127.0.0.1:9999/daeg"},
 			Shards:    4,
 			RPCServer: [][]p2p.Peer{
@@ -59,6 +60,8 @@ func TestReadWalletProfile(t *testing.T) {
 		},
 		{
 			Profile:   "testnet",
+			Network:   "testnet",
+			Genesis:   "testnet_genesis.json",
 			Bootnodes: []string{"192.168.0.1:9990/abcd", "127.0.0.1:8888/daeg"},
 			Shards:    3,
 			RPCServer: [][]p2p.Peer{
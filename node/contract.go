@@ -36,6 +36,12 @@ const (
 	scStaking
 )
 
+// shardSigner returns the signer of the transactions the node issues to the
+// given shard, bound to the chain ID of its blockchain.
+func (node *Node) shardSigner(shardID uint32) types.Signer {
+	return types.NewHarmonySigner(node.Blockchain().HarmonyConfig().ChainID, shardID)
+}
+
 // AddStakingContractToPendingTransactions adds the deposit smart contract the genesis block.
 func (node *Node) AddStakingContractToPendingTransactions() {
 	// Add a contract deployment transaction
@@ -47,7 +53,7 @@ func (node *Node) AddStakingContractToPendingTransactions() {
 	contractFunds = contractFunds.Mul(contractFunds, big.NewInt(denominations.One))
 	dataEnc := common.FromHex(contracts.StakeLockContractBin)
 	// Unsigned transaction to avoid the case of transaction address.
-	mycontracttx, _ := types.SignTx(types.NewContractCreation(uint64(0), node.Consensus.ShardID, contractFunds, params.TxGasContractCreation*100, nil, dataEnc), types.HomesteadSigner{}, priKey)
+	mycontracttx, _ := types.SignTx(types.NewContractCreation(uint64(0), node.Consensus.ShardID, contractFunds, params.TxGasContractCreation*100, nil, dataEnc), node.shardSigner(node.Consensus.ShardID), priKey)
 	//node.StakingContractAddress = crypto.CreateAddress(contractAddress, uint64(0))
 	node.StakingContractAddress = node.generateDeployedStakingContractAddress(contractAddress)
 	node.addPendingTransactions(types.Transactions{mycontracttx})
@@ -103,7 +109,7 @@ func (node *Node) QueryStakeInfo() *structs.StakeInfoReturnValue {
 		nil,
 		bytesData,
 	)
-	signedTx, err := types.SignTx(tx, types.HomesteadSigner{}, priKey)
+	signedTx, err := types.SignTx(tx, node.shardSigner(node.NodeConfig.ShardID), priKey)
 	if err != nil {
 		utils.GetLogInstance().Error("Failed to sign contract call tx", "error", err)
 		return nil
@@ -161,7 +167,7 @@ func (node *Node) AddFaucetContractToPendingTransactions() {
 	contractFunds = contractFunds.Mul(contractFunds, big.NewInt(denominations.One))
 	mycontracttx, _ := types.SignTx(
 		types.NewContractCreation(uint64(0), node.Consensus.ShardID, contractFunds, params.TxGasContractCreation*10, nil, dataEnc),
-		types.HomesteadSigner{},
+		node.shardSigner(node.Consensus.ShardID),
 		priKey)
 	node.ContractAddresses = append(node.ContractAddresses, crypto.CreateAddress(crypto.PubkeyToAddress(priKey.PublicKey), uint64(0)))
 	node.addPendingTransactions(types.Transactions{mycontracttx})
@@ -171,7 +177,7 @@ func (node *Node) AddFaucetContractToPendingTransactions() {
 func (node *Node) CallFaucetContract(address common.Address) common.Hash {
 	// Temporary code to workaround explorer issue for searching new addresses (https://github.com/harmony-one/harmony/issues/503)
 	nonce := atomic.AddUint64(&node.ContractDeployerCurrentNonce, 1)
-	tx, _ := types.SignTx(types.NewTransaction(nonce-1, address, node.Consensus.ShardID, big.NewInt(0), params.TxGasContractCreation*10, nil, nil), types.HomesteadSigner{}, node.ContractDeployerKey)
+	tx, _ := types.SignTx(types.NewTransaction(nonce-1, address, node.Consensus.ShardID, big.NewInt(0), params.TxGasContractCreation*10, nil, nil), node.shardSigner(node.Consensus.ShardID), node.ContractDeployerKey)
 	utils.GetLogInstance().Info("Sending placeholder token to ", "Address", common2.MustAddressToBech32(address))
 	node.addPendingTransactions(types.Transactions{tx})
 	// END Temporary code
@@ -200,7 +206,7 @@ func (node *Node) callGetFreeTokenWithNonce(address common.Address, nonce uint64
 		utils.GetLogInstance().Error("Failed to find the contract address")
 		return common.Hash{}
 	}
-	tx, _ := types.SignTx(types.NewTransaction(nonce, node.ContractAddresses[0], node.Consensus.ShardID, big.NewInt(0), params.TxGasContractCreation*10, nil, bytesData), types.HomesteadSigner{}, node.ContractDeployerKey)
+	tx, _ := types.SignTx(types.NewTransaction(nonce, node.ContractAddresses[0], node.Consensus.ShardID, big.NewInt(0), params.TxGasContractCreation*10, nil, bytesData), node.shardSigner(node.Consensus.ShardID), node.ContractDeployerKey)
 	utils.GetLogInstance().Info("Sending Free Token to ", "Address", common2.MustAddressToBech32(address))
 
 	node.addPendingTransactions(types.Transactions{tx})
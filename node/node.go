@@ -17,6 +17,7 @@ import (
 	clientService "github.com/harmony-one/harmony/api/client/service"
 	msg_pb "github.com/harmony-one/harmony/api/proto/message"
 	"github.com/harmony-one/harmony/api/service"
+	"github.com/harmony-one/harmony/api/service/explorer"
 	"github.com/harmony-one/harmony/api/service/syncing"
 	"github.com/harmony-one/harmony/api/service/syncing/downloader"
 	"github.com/harmony-one/harmony/consensus"
@@ -48,7 +49,7 @@ const (
 )
 
 const (
-	// TxPoolLimit is the limit of transaction pool.
+	// TxPoolLimit is the maximum number of executable transactions in the transaction pool.
 	TxPoolLimit = 20000
 )
 
@@ -87,13 +88,11 @@ type syncConfig struct {
 
 // Node represents a protocol-participating node in the network
 type Node struct {
-	Consensus             *consensus.Consensus // Consensus object containing all Consensus related data (e.g. committee members, signatures, commits)
-	BlockChannel          chan *types.Block    // The channel to send newly proposed blocks
-	ConfirmedBlockChannel chan *types.Block    // The channel to send confirmed blocks
-	BeaconBlockChannel    chan *types.Block    // The channel to send beacon blocks for non-beaconchain nodes
-	pendingTransactions   types.Transactions   // All the transactions received but not yet processed for Consensus
-	pendingTxMutex        sync.Mutex
-	DRand                 *drand.DRand // The instance for distributed randomness protocol
+	Consensus             *consensus.Consensus       // Consensus object containing all Consensus related data (e.g. committee members, signatures, commits)
+	BlockChannel          chan *types.Block          // The channel to send newly proposed blocks
+	ConfirmedBlockChannel chan *types.Block          // The channel to send confirmed blocks
+	BeaconBlockChannel    chan syncing.LastMileBlock // The channel to send beacon blocks for non-beaconchain nodes
+	DRand                 *drand.DRand               // The instance for distributed randomness protocol
 
 	// Shard databases
 	shardChains shardchain.Collection
@@ -198,6 +197,13 @@ type Node struct {
 	}
 
 	isFirstTime bool // the node was started with a fresh database
+
+	adminRPC bool // whether the HTTP RPC endpoint serves the admin namespace
+	debugRPC bool // whether the HTTP RPC endpoint serves the debug namespace
+
+	compressMessages bool // whether block, sync and shard state messages are sent compressed
+
+	dispatcher *messageDispatcher // queues received group messages by category
 }
 
 // Blockchain returns the blockchain for the node's current shard.
@@ -222,40 +228,66 @@ func (node *Node) Beaconchain() *core.BlockChain {
 	return bc
 }
 
-func (node *Node) reducePendingTransactions() {
-	// If length of pendingTransactions is greater than TxPoolLimit then by greedy take the TxPoolLimit recent transactions.
-	if len(node.pendingTransactions) > TxPoolLimit+TxPoolLimit {
-		curLen := len(node.pendingTransactions)
-		node.pendingTransactions = append(types.Transactions(nil), node.pendingTransactions[curLen-TxPoolLimit:]...)
-		utils.GetLogger().Info("mem stat reduce pending transaction")
+// RewindBlockchain rewinds the node's shard chain, and the explorer index if
+// the node serves one, to the given block number.
+func (node *Node) RewindBlockchain(head uint64) error {
+	if err := node.Blockchain().RewindTo(head); err != nil {
+		return err
+	}
+	if node.serviceManager != nil && node.serviceManager.GetServices()[service.SupportExplorer] != nil {
+		storage := explorer.GetStorageInstance(node.SelfPeer.IP, node.SelfPeer.Port, false)
+		if err := storage.Rewind(head, node.Blockchain().HarmonyConfig()); err != nil {
+			return ctxerror.New("cannot rewind explorer storage").WithCause(err)
+		}
 	}
+	if err := node.Worker.UpdateCurrent(); err != nil {
+		return ctxerror.New("cannot update worker after rewind").WithCause(err)
+	}
+	utils.GetLogInstance().Warn("Rewound blockchain", "head", node.Blockchain().CurrentBlock().NumberU64())
+	return nil
 }
 
-// Add new transactions to the pending transaction list.
+// addPendingTransactions adds transactions received from the network to the
+// transaction pool, dropping those the pool rejects.
 func (node *Node) addPendingTransactions(newTxs types.Transactions) {
-	node.pendingTxMutex.Lock()
-	node.pendingTransactions = append(node.pendingTransactions, newTxs...)
-	node.reducePendingTransactions()
-	node.pendingTxMutex.Unlock()
-	utils.GetLogInstance().Info("Got more transactions", "num", len(newTxs), "totalPending", len(node.pendingTransactions))
+	added := 0
+	for i, err := range node.TxPool.AddRemotes(newTxs) {
+		if err != nil {
+			utils.GetLogInstance().Debug("Dropped transaction", "hash", newTxs[i].Hash(), "error", err)
+			continue
+		}
+		added++
+	}
+	pending, queued := node.TxPool.Stats()
+	utils.GetLogInstance().Info("Got more transactions", "num", len(newTxs), "added", added, "totalPending", pending, "totalQueued", queued)
 }
 
-// AddPendingTransaction adds one new transaction to the pending transaction list.
-func (node *Node) AddPendingTransaction(newTx *types.Transaction) {
-	node.addPendingTransactions(types.Transactions{newTx})
-	utils.GetLogInstance().Debug("Got ONE more transaction", "totalPending", len(node.pendingTransactions))
+// AddPendingTransaction adds one new transaction submitted through RPC to the
+// transaction pool.
+func (node *Node) AddPendingTransaction(newTx *types.Transaction) error {
+	if err := node.TxPool.AddLocal(newTx); err != nil {
+		return err
+	}
+	pending, _ := node.TxPool.Stats()
+	utils.GetLogInstance().Debug("Got ONE more transaction", "totalPending", pending)
+	return nil
 }
 
-// Take out a subset of valid transactions from the pending transaction list
-// Note the pending transaction list will then contain the rest of the txs
+// Take out a subset of valid transactions from the pending transactions of the
+// pool. They stay in the pool until the block including them is committed.
 func (node *Node) getTransactionsForNewBlock(maxNumTxs int) types.Transactions {
-	node.pendingTxMutex.Lock()
-	selected, unselected, invalid := node.Worker.SelectTransactionsForNewBlock(node.pendingTransactions, maxNumTxs)
-
-	node.pendingTransactions = unselected
-	node.reducePendingTransactions()
-	utils.GetLogInstance().Debug("Selecting Transactions", "remainPending", len(node.pendingTransactions), "selected", len(selected), "invalidDiscarded", len(invalid))
-	node.pendingTxMutex.Unlock()
+	pending, err := node.TxPool.Pending()
+	if err != nil {
+		ctxerror.Log15(utils.GetLogger().Error,
+			ctxerror.New("cannot get pending transactions").WithCause(err))
+		return nil
+	}
+	selected, invalid := node.Worker.SelectTransactionsForNewBlock(pending, maxNumTxs)
+	for _, tx := range invalid {
+		node.TxPool.RemoveTx(tx.Hash())
+	}
+	remaining, _ := node.TxPool.Stats()
+	utils.GetLogInstance().Debug("Selecting Transactions", "remainPending", remaining-len(selected), "selected", len(selected), "invalidDiscarded", len(invalid))
 	return selected
 }
 
@@ -321,8 +353,13 @@ func New(host p2p.Host, consensusObj *consensus.Consensus, chainDBFactory shardc
 
 		node.BlockChannel = make(chan *types.Block)
 		node.ConfirmedBlockChannel = make(chan *types.Block)
-		node.BeaconBlockChannel = make(chan *types.Block)
-		node.TxPool = core.NewTxPool(core.DefaultTxPoolConfig, params.TestChainConfig, chain)
+		node.BeaconBlockChannel = make(chan syncing.LastMileBlock)
+		txPoolConfig := core.DefaultTxPoolConfig
+		txPoolConfig.GlobalSlots = TxPoolLimit
+		node.TxPool = core.NewTxPool(txPoolConfig, params.TestChainConfig, chain)
+		// Clients do not price their transactions yet, so the node accepts
+		// free ones below the pool's sanitized price limit.
+		node.TxPool.SetGasPrice(big.NewInt(0))
 		node.Worker = worker.New(params.TestChainConfig, chain, node.Consensus, node.Consensus.SelfAddress, node.Consensus.ShardID)
 
 		node.Consensus.VerifiedNewBlock = make(chan *types.Block)
@@ -358,6 +395,11 @@ func New(host p2p.Host, consensusObj *consensus.Consensus, chainDBFactory shardc
 		node.State = NodeInit
 	}
 
+	// received messages are queued by category and handled by a worker of
+	// each category, helped by shared workers taking consensus messages first
+	node.dispatcher = newMessageDispatcher(node.messageHandler)
+	node.dispatcher.start()
+
 	// start the goroutine to receive client message
 	// client messages are sent by clients, like txgen, wallet
 	go node.ReceiveClientGroupMessage()
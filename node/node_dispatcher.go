@@ -0,0 +1,222 @@
+package node
+
+import (
+	"sync/atomic"
+
+	"github.com/harmony-one/harmony/api/proto"
+	proto_node "github.com/harmony-one/harmony/api/proto/node"
+	"github.com/harmony-one/harmony/internal/utils"
+)
+
+// messageQueue is a queue of received messages of some categories.
+// Queues are listed from the highest priority to the lowest.
+type messageQueue int
+
+const (
+	consensusQueue   messageQueue = iota // consensus messages
+	drandQueue                           // randomness messages
+	blockQueue                           // block sync and shard state messages
+	pingQueue                            // ping and pong messages
+	transactionQueue                     // transaction and staking messages, and anything else
+	numMessageQueues
+)
+
+var messageQueueNames = [numMessageQueues]string{
+	consensusQueue:   "consensus",
+	drandQueue:       "drand",
+	blockQueue:       "block",
+	pingQueue:        "ping",
+	transactionQueue: "transaction",
+}
+
+// messageQueueSizes are the number of messages each queue holds, beyond
+// which received messages are dropped.
+var messageQueueSizes = [numMessageQueues]int{
+	consensusQueue:   1024,
+	drandQueue:       256,
+	blockQueue:       256,
+	pingQueue:        1024,
+	transactionQueue: 4096,
+}
+
+// numSharedWorkers is the number of workers which handle the messages of all
+// queues, besides the worker of each queue.
+const numSharedWorkers = 3
+
+// dropLogInterval is the number of drops of a queue between two warnings.
+const dropLogInterval = 1000
+
+func (q messageQueue) String() string {
+	if q < 0 || q >= numMessageQueues {
+		return "unknown"
+	}
+	return messageQueueNames[q]
+}
+
+// queueOf returns the queue of a received message content, without decoding
+// it. Compressed node messages are blocks or shard states.
+func queueOf(content []byte) messageQueue {
+	if len(content) < proto.MessageCategoryBytes {
+		return transactionQueue
+	}
+	switch proto.MessageCategory(content[0] &^ proto.Compressed) {
+	case proto.Consensus:
+		return consensusQueue
+	case proto.DRand:
+		return drandQueue
+	case proto.Node:
+		if proto.IsCompressed(content) {
+			return blockQueue
+		}
+		msgType, err := proto.GetMessageType(content)
+		if err != nil {
+			return transactionQueue
+		}
+		switch proto_node.MessageType(msgType) {
+		case proto_node.Block, proto_node.ShardState:
+			return blockQueue
+		case proto_node.PING, proto_node.PONG:
+			return pingQueue
+		}
+	}
+	return transactionQueue
+}
+
+// receivedMessage is a message waiting in a queue.
+type receivedMessage struct {
+	content []byte
+	sender  string
+}
+
+// MessageQueueStats are the counters of a receive queue.
+type MessageQueueStats struct {
+	Name     string
+	Length   int // messages waiting
+	Capacity int
+	Received uint64 // messages queued
+	Dropped  uint64 // messages dropped because the queue was full
+}
+
+// messageDispatcher queues received messages by category in bounded queues.
+// Each queue has a worker of its own, so that a handler blocked on a busy
+// consumer, such as the consensus loop or beacon syncing, never holds up the
+// other queues, and a pool of shared workers which always take the message of
+// the highest priority queue first. A flood of messages of one category thus
+// neither delays messages of higher priority nor grows memory without bounds;
+// messages beyond the queue size are dropped.
+type messageDispatcher struct {
+	received [numMessageQueues]uint64 // atomic, first for 64-bit alignment
+	dropped  [numMessageQueues]uint64 // atomic
+	queues   [numMessageQueues]chan receivedMessage
+	handle   func(content []byte, sender string)
+	quit     chan struct{}
+}
+
+// newMessageDispatcher creates a dispatcher which handles messages with handle.
+func newMessageDispatcher(handle func(content []byte, sender string)) *messageDispatcher {
+	d := &messageDispatcher{handle: handle, quit: make(chan struct{})}
+	for q := range d.queues {
+		d.queues[q] = make(chan receivedMessage, messageQueueSizes[q])
+	}
+	return d
+}
+
+// start starts the worker of each queue and the shared workers.
+func (d *messageDispatcher) start() {
+	for q := range d.queues {
+		go d.work(messageQueue(q))
+	}
+	for i := 0; i < numSharedWorkers; i++ {
+		go d.workShared()
+	}
+}
+
+// stop stops the workers once they are done with their current message.
+func (d *messageDispatcher) stop() {
+	close(d.quit)
+}
+
+// dispatch queues a received message, or drops it if its queue is full.
+func (d *messageDispatcher) dispatch(content []byte, sender string) {
+	q := queueOf(content)
+	select {
+	case d.queues[q] <- receivedMessage{content, sender}:
+		atomic.AddUint64(&d.received[q], 1)
+	default:
+		if dropped := atomic.AddUint64(&d.dropped[q], 1); dropped%dropLogInterval == 1 {
+			utils.GetLogInstance().Warn("receive queue full, dropping messages",
+				"queue", q, "dropped", dropped)
+		}
+	}
+}
+
+// next returns the next message to handle, waiting for one if all queues are
+// empty, or false once the dispatcher stops.
+func (d *messageDispatcher) next() (receivedMessage, bool) {
+	for _, queue := range d.queues {
+		select {
+		case m := <-queue:
+			return m, true
+		default:
+		}
+	}
+	select {
+	case m := <-d.queues[consensusQueue]:
+		return m, true
+	case m := <-d.queues[drandQueue]:
+		return m, true
+	case m := <-d.queues[blockQueue]:
+		return m, true
+	case m := <-d.queues[pingQueue]:
+		return m, true
+	case m := <-d.queues[transactionQueue]:
+		return m, true
+	case <-d.quit:
+		return receivedMessage{}, false
+	}
+}
+
+// work handles the messages of a queue until the dispatcher stops.
+func (d *messageDispatcher) work(q messageQueue) {
+	for {
+		select {
+		case m := <-d.queues[q]:
+			d.handle(m.content, m.sender)
+		case <-d.quit:
+			return
+		}
+	}
+}
+
+// workShared handles the messages of all queues, by priority, until the
+// dispatcher stops.
+func (d *messageDispatcher) workShared() {
+	for {
+		m, ok := d.next()
+		if !ok {
+			return
+		}
+		d.handle(m.content, m.sender)
+	}
+}
+
+// stats returns the counters of the queues, from the highest priority.
+func (d *messageDispatcher) stats() []MessageQueueStats {
+	stats := make([]MessageQueueStats, numMessageQueues)
+	for q := range d.queues {
+		stats[q] = MessageQueueStats{
+			Name:     messageQueue(q).String(),
+			Length:   len(d.queues[q]),
+			Capacity: cap(d.queues[q]),
+			Received: atomic.LoadUint64(&d.received[q]),
+			Dropped:  atomic.LoadUint64(&d.dropped[q]),
+		}
+	}
+	return stats
+}
+
+// ReceiveQueueStats returns the counters of the queues of received group
+// messages, from the highest priority.
+func (node *Node) ReceiveQueueStats() []MessageQueueStats {
+	return node.dispatcher.stats()
+}
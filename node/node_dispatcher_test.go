@@ -0,0 +1,126 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/harmony-one/harmony/api/proto"
+	proto_node "github.com/harmony-one/harmony/api/proto/node"
+)
+
+func TestQueueOf(t *testing.T) {
+	tests := []struct {
+		content []byte
+		queue   messageQueue
+	}{
+		{nil, transactionQueue},
+		{proto.ConstructConsensusMessage([]byte{1}), consensusQueue},
+		{proto.ConstructDRandMessage([]byte{1}), drandQueue},
+		{proto.ConstructStakingMessage([]byte{1}), transactionQueue},
+		{[]byte{byte(proto.Node), byte(proto_node.Transaction), 0}, transactionQueue},
+		{[]byte{byte(proto.Node), byte(proto_node.Block), 0}, blockQueue},
+		{[]byte{byte(proto.Node), byte(proto_node.ShardState), 0}, blockQueue},
+		{[]byte{byte(proto.Node) | proto.Compressed, 0}, blockQueue},
+		{[]byte{byte(proto.Node), byte(proto_node.PING), 0}, pingQueue},
+		{[]byte{byte(proto.Node), byte(proto_node.PONG), 0}, pingQueue},
+		{[]byte{byte(proto.Node)}, transactionQueue},
+	}
+	for i, test := range tests {
+		if queue := queueOf(test.content); queue != test.queue {
+			t.Errorf("message %d: expected queue %s, got %s", i, test.queue, queue)
+		}
+	}
+}
+
+func TestMessageDispatcherPriority(t *testing.T) {
+	var handled []messageQueue
+	d := newMessageDispatcher(func(content []byte, sender string) {
+		handled = append(handled, queueOf(content))
+	})
+	tx := []byte{byte(proto.Node), byte(proto_node.Transaction), 0}
+	ping := []byte{byte(proto.Node), byte(proto_node.PING), 0}
+	consensus := proto.ConstructConsensusMessage([]byte{1})
+	d.dispatch(tx, "a")
+	d.dispatch(ping, "a")
+	d.dispatch(tx, "a")
+	d.dispatch(consensus, "a")
+
+	// Run a single shared worker in this goroutine, until all messages are handled.
+	for len(handled) < 4 {
+		m, ok := d.next()
+		if !ok {
+			t.Fatal("dispatcher stopped")
+		}
+		d.handle(m.content, m.sender)
+	}
+	expected := []messageQueue{consensusQueue, pingQueue, transactionQueue, transactionQueue}
+	for i := range expected {
+		if handled[i] != expected[i] {
+			t.Fatalf("expected messages handled in order %v, got %v", expected, handled)
+		}
+	}
+	d.stop()
+	if _, ok := d.next(); ok {
+		t.Error("stopped dispatcher returned a message")
+	}
+}
+
+func TestMessageDispatcherBlockedHandler(t *testing.T) {
+	consensus := proto.ConstructConsensusMessage([]byte{1})
+	tx := []byte{byte(proto.Node), byte(proto_node.Transaction), 0}
+	release := make(chan struct{})
+	// More blocked consensus messages than there are workers in all.
+	const numConsensus = 10
+	handled := make(chan messageQueue, numConsensus+1)
+	d := newMessageDispatcher(func(content []byte, sender string) {
+		q := queueOf(content)
+		if q == consensusQueue {
+			// Like a handler waiting for the consensus loop.
+			<-release
+		}
+		handled <- q
+	})
+	d.start()
+	defer d.stop()
+
+	for i := 0; i < numConsensus; i++ {
+		d.dispatch(consensus, "a")
+	}
+	d.dispatch(tx, "a")
+	select {
+	case q := <-handled:
+		if q != transactionQueue {
+			t.Fatalf("expected the transaction handled first, got %s", q)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("blocked consensus handler held up the transaction")
+	}
+
+	close(release)
+	for i := 0; i < numConsensus; i++ {
+		select {
+		case q := <-handled:
+			if q != consensusQueue {
+				t.Errorf("unexpected message of queue %s", q)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("consensus messages not handled once released")
+		}
+	}
+}
+
+func TestMessageDispatcherDrop(t *testing.T) {
+	d := newMessageDispatcher(func(content []byte, sender string) {})
+	drand := proto.ConstructDRandMessage([]byte{1})
+	for i := 0; i < messageQueueSizes[drandQueue]+3; i++ {
+		d.dispatch(drand, "a")
+	}
+	stats := d.stats()[drandQueue]
+	if stats.Name != "drand" || stats.Received != uint64(messageQueueSizes[drandQueue]) ||
+		stats.Dropped != 3 || stats.Length != stats.Capacity {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if other := d.stats()[consensusQueue]; other.Received != 0 || other.Dropped != 0 {
+		t.Errorf("unexpected stats of another queue %+v", other)
+	}
+}
@@ -8,20 +8,18 @@ import (
 
 	"github.com/harmony-one/harmony/common/config"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/params"
-	"github.com/pkg/errors"
 
 	"github.com/harmony-one/harmony/common/denominations"
 	"github.com/harmony-one/harmony/core"
-	"github.com/harmony-one/harmony/core/rawdb"
-	"github.com/harmony-one/harmony/core/types"
 	common2 "github.com/harmony-one/harmony/internal/common"
 	"github.com/harmony-one/harmony/internal/ctxerror"
 	"github.com/harmony-one/harmony/internal/genesis"
+	hmyparams "github.com/harmony-one/harmony/internal/params"
 	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/p2p"
 )
 
 const (
@@ -40,25 +38,15 @@ type genesisInitializer struct {
 
 // InitChainDB sets up a new genesis block in the database for the given shard.
 func (gi *genesisInitializer) InitChainDB(db ethdb.Database, shardID uint32) error {
-	shardState := core.GetInitShardState()
-	if shardID != 0 {
-		// store only the local shard
-		c := shardState.FindCommitteeByID(shardID)
-		if c == nil {
-			return errors.New("cannot find local shard in genesis")
-		}
-		shardState = types.ShardState{*c}
-	}
-	if err := rawdb.WriteShardState(db, common.Big0, shardState); err != nil {
-		return ctxerror.New("cannot store epoch shard state").WithCause(err)
-	}
 	if err := gi.node.SetupGenesisBlock(db, shardID); err != nil {
 		return ctxerror.New("cannot setup genesis block").WithCause(err)
 	}
 	return nil
 }
 
-// SetupGenesisBlock sets up a genesis blockchain.
+// SetupGenesisBlock sets up a genesis blockchain.  The custom network genesis
+// in core.CustomNetworkGenesis is used if set, otherwise the built-in genesis
+// of the network the node runs on.
 func (node *Node) SetupGenesisBlock(db ethdb.Database, shardID uint32) error {
 	utils.GetLogger().Info("setting up a brand new chain database",
 		"shardID", shardID)
@@ -66,53 +54,108 @@ func (node *Node) SetupGenesisBlock(db ethdb.Database, shardID uint32) error {
 		node.isFirstTime = true
 	}
 
-	// Initialize genesis block and blockchain
+	ng := core.CustomNetworkGenesis
+	if ng == nil {
+		var err error
+		if ng, err = BuiltinNetworkGenesis(config.Network); err != nil {
+			return err
+		}
+		if config.Network == config.Testnet {
+			// Smart contract deployer account used to deploy initial smart contract
+			node.ContractDeployerKey = contractDeployerKey()
+		}
+	}
 
+	// Store genesis block into db.
+	_, err := ng.Commit(db, shardID)
+
+	return err
+}
+
+// BuiltinNetworkGenesis returns the genesis of the given built-in network,
+// laid out according to the current sharding schedule.
+func BuiltinNetworkGenesis(network config.NetworkType) (*core.NetworkGenesis, error) {
 	genesisAlloc := make(core.GenesisAlloc)
 	chainConfig := params.ChainConfig{}
 
-	switch config.Network {
+	switch network {
 	case config.Mainnet:
 		chainConfig = *params.MainnetChainConfig
 	case config.Testnet:
 		chainConfig = *params.TestnetChainConfig
 		// Tests account for txgen to use
-		node.AddTestingAddresses(genesisAlloc, TestAccountNumber)
+		testBankKeys, err := CreateTestBankKeys(TestAccountNumber)
+		if err != nil {
+			return nil, ctxerror.New("cannot create test bank keys").WithCause(err)
+		}
+		AddTestingAddresses(genesisAlloc, testBankKeys)
 
 		// Smart contract deployer account used to deploy initial smart contract
-		contractDeployerKey, _ := ecdsa.GenerateKey(crypto.S256(), strings.NewReader("Test contract key string stream that is fixed so that generated test key are deterministic every time"))
-		contractDeployerAddress := crypto.PubkeyToAddress(contractDeployerKey.PublicKey)
+		contractDeployerAddress := crypto.PubkeyToAddress(contractDeployerKey().PublicKey)
 		contractDeployerFunds := big.NewInt(TotalInitFund)
 		contractDeployerFunds = contractDeployerFunds.Mul(contractDeployerFunds, big.NewInt(denominations.One))
 		genesisAlloc[contractDeployerAddress] = core.GenesisAccount{Balance: contractDeployerFunds}
-		node.ContractDeployerKey = contractDeployerKey
 	}
 
-	if shardID == 0 {
-		// Accounts used by validator/nodes to stake and participate in the network.
-		// AddNodeAddressesToGenesisAlloc(genesisAlloc)
-	}
+	// Accounts used by validator/nodes to stake and participate in the network
+	// are not funded at genesis yet; see AddNodeAddressesToGenesisAlloc.
+
+	return core.NewNetworkGenesis(&chainConfig,
+		hmyparams.ChainConfigByNetwork(network), core.ShardingSchedule,
+		genesisAlloc), nil
+}
 
-	// TODO: add ShardID into chainconfig and change ChainID to NetworkID
-	chainConfig.ChainID = big.NewInt(int64(shardID)) // Use ChainID as piggybacked ShardID
-	gspec := core.Genesis{
-		Config:  &chainConfig,
-		Alloc:   genesisAlloc,
-		ShardID: shardID,
+// UseNetworkGenesisFile switches to the custom network genesis in the given
+// file, the one the nodes of the network were initialized with, so that
+// clients of a custom network get its sharding schedule and NetworkScope.
+func UseNetworkGenesisFile(path string) error {
+	ng, err := core.ReadNetworkGenesis(path)
+	if err != nil {
+		return err
 	}
+	schedule, err := ng.Schedule()
+	if err != nil {
+		return ctxerror.New("invalid network genesis", "path", path).WithCause(err)
+	}
+	core.CustomNetworkGenesis = ng
+	core.ShardingSchedule = schedule
+	return nil
+}
 
-	// Store genesis block into db.
-	_, err := gspec.Commit(db)
+// NetworkScope returns the p2p scope of the given network: its network ID and
+// the hash of its beacon chain genesis block, built from the custom network
+// genesis in core.CustomNetworkGenesis if set.
+func NetworkScope(network config.NetworkType) (p2p.NetworkScope, error) {
+	ng := core.CustomNetworkGenesis
+	if ng == nil {
+		var err error
+		if ng, err = BuiltinNetworkGenesis(network); err != nil {
+			return p2p.NetworkScope{}, err
+		}
+	}
+	block, err := ng.Commit(ethdb.NewMemDatabase(), 0)
+	if err != nil {
+		return p2p.NetworkScope{}, ctxerror.New("cannot build beacon chain genesis block").WithCause(err)
+	}
+	return p2p.NetworkScope{
+		NetworkID:   network.ChainID().Uint64(),
+		GenesisHash: block.Hash(),
+	}, nil
+}
 
-	return err
+// contractDeployerKey returns the deterministic key of the test network
+// contract deployer account.
+func contractDeployerKey() *ecdsa.PrivateKey {
+	key, _ := ecdsa.GenerateKey(crypto.S256(), strings.NewReader("Test contract key string stream that is fixed so that generated test key are deterministic every time"))
+	return key
 }
 
 // CreateTestBankKeys deterministically generates testing addresses.
 func CreateTestBankKeys(numAddresses int) (keys []*ecdsa.PrivateKey, err error) {
-	rand.Seed(0)
+	r := rand.New(rand.NewSource(0))
 	bytes := make([]byte, 1000000)
 	for i := range bytes {
-		bytes[i] = byte(rand.Intn(100))
+		bytes[i] = byte(r.Intn(100))
 	}
 	reader := strings.NewReader(string(bytes))
 	for i := 0; i < numAddresses; i++ {
@@ -127,8 +170,8 @@ func CreateTestBankKeys(numAddresses int) (keys []*ecdsa.PrivateKey, err error)
 
 // AddTestingAddresses create the genesis block allocation that contains deterministically
 // generated testing addresses with tokens. This is mostly used for generated simulated transactions in txgen.
-func (node *Node) AddTestingAddresses(gAlloc core.GenesisAlloc, numAddress int) {
-	for _, testBankKey := range node.TestBankKeys {
+func AddTestingAddresses(gAlloc core.GenesisAlloc, testBankKeys []*ecdsa.PrivateKey) {
+	for _, testBankKey := range testBankKeys {
 		testBankAddress := crypto.PubkeyToAddress(testBankKey.PublicKey)
 		testBankFunds := big.NewInt(InitFreeFundInEther)
 		testBankFunds = testBankFunds.Mul(testBankFunds, big.NewInt(denominations.One))
@@ -26,6 +26,7 @@ import (
 	"github.com/harmony-one/harmony/api/proto/message"
 	proto_node "github.com/harmony-one/harmony/api/proto/node"
 	"github.com/harmony-one/harmony/api/service"
+	"github.com/harmony-one/harmony/api/service/syncing"
 	"github.com/harmony-one/harmony/contracts/structs"
 	"github.com/harmony-one/harmony/core"
 	"github.com/harmony-one/harmony/core/types"
@@ -34,12 +35,16 @@ import (
 	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/p2p"
 	"github.com/harmony-one/harmony/p2p/host"
+	libp2p_peer "github.com/libp2p/go-libp2p-peer"
 )
 
 const (
-	// MaxNumberOfTransactionsPerBlock is the max number of transaction per a block.
+	// MaxNumberOfTransactionsPerBlock is the max number of transaction per a block;
+	// within it, blocks are filled up to their gas limit.
 	MaxNumberOfTransactionsPerBlock = 8000
 	consensusTimeout                = 30 * time.Second
+	// maxMessageSize bounds the size of received messages once decompressed.
+	maxMessageSize = 32 << 20
 )
 
 // ReceiveGlobalMessage use libp2p pubsub mechanism to receive global broadcast messages
@@ -55,7 +60,7 @@ func (node *Node) ReceiveGlobalMessage() {
 			//utils.GetLogInstance().Info("[PUBSUB]", "received global msg", len(msg), "sender", sender)
 			if err == nil {
 				// skip the first 5 bytes, 1 byte is p2p type, 4 bytes are message size
-				go node.messageHandler(msg[5:], string(sender))
+				node.dispatcher.dispatch(msg[5:], string(sender))
 			}
 		}
 	}
@@ -74,7 +79,7 @@ func (node *Node) ReceiveGroupMessage() {
 			//utils.GetLogInstance().Info("[PUBSUB]", "received group msg", len(msg), "sender", sender)
 			if err == nil {
 				// skip the first 5 bytes, 1 byte is p2p type, 4 bytes are message size
-				go node.messageHandler(msg[5:], string(sender))
+				node.dispatcher.dispatch(msg[5:], string(sender))
 			}
 		}
 	}
@@ -94,7 +99,7 @@ func (node *Node) ReceiveClientGroupMessage() {
 			// utils.GetLogInstance().Info("[CLIENT]", "received group msg", len(msg), "sender", sender, "error", err)
 			if err == nil {
 				// skip the first 5 bytes, 1 byte is p2p type, 4 bytes are message size
-				go node.messageHandler(msg[5:], string(sender))
+				node.dispatcher.dispatch(msg[5:], string(sender))
 			}
 		}
 	}
@@ -102,21 +107,31 @@ func (node *Node) ReceiveClientGroupMessage() {
 
 // messageHandler parses the message and dispatch the actions
 func (node *Node) messageHandler(content []byte, sender string) {
+	content, err := proto.DecompressMessage(content, maxMessageSize)
+	if err != nil {
+		utils.GetLogInstance().Error("Decompress message failed", "err", err, "node", node)
+		node.reportPeer(sender, p2p.PeerInvalidMessage)
+		return
+	}
+
 	msgCategory, err := proto.GetMessageCategory(content)
 	if err != nil {
 		utils.GetLogInstance().Error("Read node type failed", "err", err, "node", node)
+		node.reportPeer(sender, p2p.PeerInvalidMessage)
 		return
 	}
 
 	msgType, err := proto.GetMessageType(content)
 	if err != nil {
 		utils.GetLogInstance().Error("Read action type failed", "err", err, "node", node)
+		node.reportPeer(sender, p2p.PeerInvalidMessage)
 		return
 	}
 
 	msgPayload, err := proto.GetMessagePayload(content)
 	if err != nil {
 		utils.GetLogInstance().Error("Read message payload failed", "err", err, "node", node)
+		node.reportPeer(sender, p2p.PeerInvalidMessage)
 		return
 	}
 
@@ -157,13 +172,17 @@ func (node *Node) messageHandler(content []byte, sender string) {
 				err := rlp.DecodeBytes(msgPayload[1:], &blocks)
 				if err != nil {
 					utils.GetLogInstance().Error("block sync", "error", err)
+					node.reportPeer(sender, p2p.PeerInvalidMessage)
 				} else {
 					// for non-beaconchain node, subscribe to beacon block broadcast
 					role := node.NodeConfig.Role()
 					if proto_node.BlockMessageType(msgPayload[0]) == proto_node.Sync && (role == nodeconfig.ShardValidator || role == nodeconfig.ShardLeader || role == nodeconfig.NewNode) {
 						utils.GetLogInstance().Info("Block being handled by block channel", "self peer", node.SelfPeer, "block", blocks[0].NumberU64())
+						// The sender is credited once a block is added to the
+						// beacon chain; group messages are keyed by their
+						// signed author, as pubsub does not tell who relayed them.
 						for _, block := range blocks {
-							node.BeaconBlockChannel <- block
+							node.BeaconBlockChannel <- syncing.LastMileBlock{Block: block, Source: libp2p_peer.ID(sender)}
 						}
 					}
 					if node.Client != nil && node.Client.UpdateBlocks != nil && blocks != nil {
@@ -183,6 +202,18 @@ func (node *Node) messageHandler(content []byte, sender string) {
 		}
 	default:
 		utils.GetLogInstance().Error("Unknown", "MsgCategory", msgCategory)
+		node.reportPeer(sender, p2p.PeerInvalidMessage)
+	}
+}
+
+// reportPeer reports an event about the sender of a message to the peer scorer.
+func (node *Node) reportPeer(sender string, event p2p.PeerEvent) {
+	scorer := node.host.GetPeerScorer()
+	if sender == "" || scorer == nil {
+		return
+	}
+	if scorer.Report(libp2p_peer.ID(sender), event) {
+		utils.GetLogInstance().Warn("banned peer", "peer", libp2p_peer.ID(sender).Pretty(), "event", event)
 	}
 }
 
@@ -231,21 +262,37 @@ func (node *Node) transactionMessageHandler(msgPayload []byte) {
 		}
 
 		var txToReturn []*types.Transaction
-		for _, tx := range node.pendingTransactions {
-			if txIDs[tx.Hash()] {
+		for txID := range txIDs {
+			if tx := node.TxPool.Get(txID); tx != nil {
 				txToReturn = append(txToReturn, tx)
 			}
 		}
 	}
 }
 
+// SetCompressMessages turns snappy compression of the block, sync and shard
+// state messages the node sends on or off. Nodes which do not support
+// compression drop compressed messages, so it should only be turned on once
+// the network supports it.
+func (node *Node) SetCompressMessages(enabled bool) {
+	node.compressMessages = enabled
+}
+
+// compress compresses a message if message compression is on.
+func (node *Node) compress(content []byte) []byte {
+	if !node.compressMessages {
+		return content
+	}
+	return proto.CompressMessage(content)
+}
+
 // BroadcastNewBlock is called by consensus leader to sync new blocks with other clients/nodes.
 // NOTE: For now, just send to the client (basically not broadcasting)
 // TODO (lc): broadcast the new blocks to new nodes doing state sync
 func (node *Node) BroadcastNewBlock(newBlock *types.Block) {
 	if node.ClientPeer != nil {
 		utils.GetLogInstance().Debug("Sending new block to client", "client", node.ClientPeer)
-		node.host.SendMessageToGroups([]p2p.GroupID{node.NodeConfig.GetClientGroupID()}, host.ConstructP2pMessage(byte(0), proto_node.ConstructBlocksSyncMessage([]*types.Block{newBlock})))
+		node.host.SendMessageToGroups([]p2p.GroupID{node.NodeConfig.GetClientGroupID()}, host.ConstructP2pMessage(byte(0), node.compress(proto_node.ConstructBlocksSyncMessage([]*types.Block{newBlock}))))
 	}
 }
 
@@ -253,7 +300,7 @@ func (node *Node) BroadcastNewBlock(newBlock *types.Block) {
 func (node *Node) VerifyNewBlock(newBlock *types.Block) error {
 	// TODO ek – where do we verify parent-child invariants,
 	//  e.g. "child.Number == child.IsGenesis() ? 0 : parent.Number+1"?
-	err := node.Blockchain().ValidateNewBlock(newBlock)
+	err := node.Blockchain().ValidateNewBlock(newBlock, node.leaderSource())
 	if err != nil {
 		return ctxerror.New("cannot ValidateNewBlock",
 			"blockHash", newBlock.Hash(),
@@ -271,6 +318,15 @@ func (node *Node) VerifyNewBlock(newBlock *types.Block) error {
 	return nil
 }
 
+// leaderSource identifies the current consensus leader as the source of the
+// blocks we validate, so that bad blocks can be attributed to it.
+func (node *Node) leaderSource() string {
+	if node.Consensus == nil || node.Consensus.LeaderPubKey == nil {
+		return ""
+	}
+	return "leader " + node.Consensus.LeaderPubKey.SerializeToHexStr()
+}
+
 // BigMaxUint64 is maximum possible uint64 value, that is, (1**64)-1.
 var BigMaxUint64 = new(big.Int).SetBytes([]byte{
 	255, 255, 255, 255, 255, 255, 255, 255,
@@ -390,7 +446,7 @@ func (node *Node) PostConsensusProcessing(newBlock *types.Block) {
 	atomic.StoreUint64(&node.ContractDeployerCurrentNonce, nonce)
 
 	for _, tx := range newBlock.Transactions() {
-		msg, err := tx.AsMessage(types.HomesteadSigner{})
+		msg, err := tx.AsMessage(node.shardSigner(tx.ShardID()))
 		if err != nil {
 			utils.GetLogInstance().Error("Error when parsing tx into message")
 		}
@@ -441,12 +497,12 @@ func (node *Node) broadcastEpochShardState(newBlock *types.Block) error {
 	)
 	return node.host.SendMessageToGroups(
 		[]p2p.GroupID{node.NodeConfig.GetClientGroupID()},
-		host.ConstructP2pMessage(byte(0), epochShardStateMessage))
+		host.ConstructP2pMessage(byte(0), node.compress(epochShardStateMessage)))
 }
 
 // AddNewBlock is usedd to add new block into the blockchain.
 func (node *Node) AddNewBlock(newBlock *types.Block) {
-	blockNum, err := node.Blockchain().InsertChain([]*types.Block{newBlock})
+	blockNum, err := node.Blockchain().InsertChainFrom([]*types.Block{newBlock}, node.leaderSource())
 	if err != nil {
 		utils.GetLogInstance().Debug("Error Adding new block to blockchain", "blockNum", blockNum, "hash", newBlock.Header().Hash(), "Error", err)
 	} else {
@@ -473,6 +529,7 @@ func (node *Node) pingMessageHandler(msgPayload []byte, sender string) int {
 	peer.IP = ping.Node.IP
 	peer.Port = ping.Node.Port
 	peer.PeerID = ping.Node.PeerID
+	peer.Addrs = ping.Node.Multiaddrs()
 	peer.ConsensusPubKey = nil
 
 	if ping.Node.PubKey != nil {
@@ -608,6 +665,7 @@ func (node *Node) pongMessageHandler(msgPayload []byte) int {
 		peer.IP = p.IP
 		peer.Port = p.Port
 		peer.PeerID = p.PeerID
+		peer.Addrs = p.Multiaddrs()
 
 		peer.ConsensusPubKey = &bls.PublicKey{}
 		err = peer.ConsensusPubKey.Deserialize(p.PubKey[:])
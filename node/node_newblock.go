@@ -67,7 +67,7 @@ func (node *Node) WaitForConsensusReadyv2(readySignal chan struct{}, stopChan ch
 						threshold = FirstTimeThreshold
 						firstTime = false
 					}
-					if len(node.pendingTransactions) < threshold && time.Now().Before(deadline) {
+					if pending, _ := node.TxPool.Stats(); pending < threshold && time.Now().Before(deadline) {
 						continue
 					}
 					deadline = time.Now().Add(BlockPeriod)
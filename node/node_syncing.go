@@ -46,10 +46,18 @@ func (node *Node) DoSyncWithoutConsensus() {
 	go node.DoSyncing(node.Blockchain(), node.Worker, node.GetSyncingPeers, false) //Don't join consensus
 }
 
+// createStateSync creates a state sync which reports the behaviour of the
+// sync peers to the peer scorer of the host.
+func (node *Node) createStateSync() *syncing.StateSync {
+	stateSync := syncing.CreateStateSync(node.SelfPeer.IP, node.SelfPeer.Port, node.GetSyncID())
+	stateSync.SetPeerScorer(node.host.GetPeerScorer())
+	return stateSync
+}
+
 // IsSameHeight tells whether node is at same bc height as a peer
 func (node *Node) IsSameHeight() (uint64, bool) {
 	if node.stateSync == nil {
-		node.stateSync = syncing.CreateStateSync(node.SelfPeer.IP, node.SelfPeer.Port, node.GetSyncID())
+		node.stateSync = node.createStateSync()
 	}
 	return node.stateSync.IsSameBlockchainHeight(node.Blockchain())
 }
@@ -70,7 +78,7 @@ func (node *Node) DoBeaconSyncing() {
 		select {
 		case beaconBlock := <-node.BeaconBlockChannel:
 			if node.beaconSync == nil {
-				node.beaconSync = syncing.CreateStateSync(node.SelfPeer.IP, node.SelfPeer.Port, node.GetSyncID())
+				node.beaconSync = node.createStateSync()
 			}
 			if node.beaconSync.GetActivePeerNumber() == 0 {
 				peers := node.GetBeaconSyncingPeers()
@@ -96,7 +104,7 @@ SyncingLoop:
 		select {
 		case <-ticker.C:
 			if node.stateSync == nil {
-				node.stateSync = syncing.CreateStateSync(node.SelfPeer.IP, node.SelfPeer.Port, node.GetSyncID())
+				node.stateSync = node.createStateSync()
 				logger = logger.New("syncID", node.GetSyncID())
 				getLogger().Debug("initialized state sync")
 			}
@@ -228,6 +236,7 @@ func (node *Node) CalculateResponse(request *downloader_pb.DownloaderRequest) (*
 				response.Payload = append(response.Payload, encodedBlock)
 			}
 		}
+		response.CompressPayload(request)
 
 	case downloader_pb.DownloaderRequest_BLOCKHEIGHT:
 		response.BlockHeight = node.Blockchain().CurrentBlock().NumberU64()
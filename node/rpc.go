@@ -31,7 +31,7 @@ var (
 	httpEndpoint = ""
 	wsEndpoint   = ""
 
-	httpModules      = []string{"hmy", "net"}
+	httpModules      = []string{"hmy", "net", "txpool"}
 	httpVirtualHosts = []string{"*"}
 	httpTimeouts     = rpc.DefaultHTTPTimeouts
 
@@ -54,13 +54,22 @@ func (node *Node) StartRPC(nodePort string) error {
 
 	port, _ := strconv.Atoi(nodePort)
 
+	modules := append([]string{}, httpModules...)
+	if node.adminRPC {
+		modules = append(modules, "admin")
+	}
+	if node.debugRPC {
+		modules = append(modules, "debug")
+	}
 	httpEndpoint = fmt.Sprintf(":%v", port+rpcHTTPPortOffset)
-	if err := node.startHTTP(httpEndpoint, apis, httpModules, nil, httpVirtualHosts, httpTimeouts); err != nil {
+	if err := node.startHTTP(httpEndpoint, apis, modules, nil, httpVirtualHosts, httpTimeouts); err != nil {
 		return err
 	}
 
+	// The websocket endpoint serves every public namespace, and never the
+	// private ones.
 	wsEndpoint = fmt.Sprintf(":%v", port+rpcWSPortOffset)
-	if err := node.startWS(wsEndpoint, apis, wsModules, wsOrigins, true); err != nil {
+	if err := node.startWS(wsEndpoint, publicAPIs(apis), wsModules, wsOrigins, true); err != nil {
 		node.stopHTTP()
 		return err
 	}
@@ -69,6 +78,31 @@ func (node *Node) StartRPC(nodePort string) error {
 	return nil
 }
 
+// SetAdminRPC turns serving the admin RPC namespace on the HTTP endpoint on or
+// off. It must be called before StartRPC, and only on nodes whose RPC port is
+// not reachable by untrusted clients.
+func (node *Node) SetAdminRPC(enabled bool) {
+	node.adminRPC = enabled
+}
+
+// SetDebugRPC turns serving the debug RPC namespace on the HTTP endpoint on or
+// off. It must be called before StartRPC, and only on nodes whose RPC port is
+// not reachable by untrusted clients.
+func (node *Node) SetDebugRPC(enabled bool) {
+	node.debugRPC = enabled
+}
+
+// publicAPIs returns the APIs of apis that are safe to serve to anyone.
+func publicAPIs(apis []rpc.API) []rpc.API {
+	var public []rpc.API
+	for _, api := range apis {
+		if api.Public {
+			public = append(public, api)
+		}
+	}
+	return public
+}
+
 // startHTTP initializes and starts the HTTP RPC endpoint.
 func (node *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors []string, vhosts []string, timeouts rpc.HTTPTimeouts) error {
 	// Short circuit if the HTTP endpoint isn't being exposed
@@ -155,5 +189,11 @@ func (node *Node) APIs() []rpc.API {
 			Service:   hmyapi.NewPublicNetAPI(node.host, harmony.APIBackend.NetVersion()),
 			Public:    true,
 		},
+		{
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   hmyapi.NewPrivateNetAPI(node.host),
+			Public:    false,
+		},
 	}...)
 }
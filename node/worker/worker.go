@@ -43,43 +43,67 @@ type Worker struct {
 	shardID uint32
 }
 
-// SelectTransactionsForNewBlock selects transactions for new block.
-func (w *Worker) SelectTransactionsForNewBlock(txs types.Transactions, maxNumTxs int) (types.Transactions, types.Transactions, types.Transactions) {
+// SelectTransactionsForNewBlock selects transactions for a new block from
+// pending, the executable transactions of the pool grouped by sender and sorted
+// by nonce, until the block gas limit or maxNumTxs is reached. Transactions are
+// taken by gas price, in nonce order per sender. It also returns the
+// transactions that can never be included, which the caller should drop from
+// the pool.
+func (w *Worker) SelectTransactionsForNewBlock(pending map[common.Address]types.Transactions, maxNumTxs int) (types.Transactions, types.Transactions) {
 	if w.current.gasPool == nil {
 		w.current.gasPool = new(core.GasPool).AddGas(w.current.header.GasLimit)
 	}
 	selected := types.Transactions{}
-	unselected := types.Transactions{}
 	invalid := types.Transactions{}
-	for _, tx := range txs {
-		if tx.ShardID() != w.shardID {
+	replayProtection := w.chain.HarmonyConfig().IsReplayProtection(w.current.header.Epoch)
+	signer := types.NewHarmonySigner(w.chain.HarmonyConfig().ChainID, w.shardID)
+	txs := types.NewTransactionsByPriceAndNonce(signer, pending)
+	for len(selected) < maxNumTxs {
+		if w.current.gasPool.Gas() < params.TxGas {
+			// Not even a plain transfer fits any more.
+			break
+		}
+		tx := txs.Peek()
+		if tx == nil {
+			break
+		}
+		if tx.ShardID() != w.shardID || (replayProtection && !tx.Protected()) {
+			// The later transactions of the sender depend on this one.
 			invalid = append(invalid, tx)
+			txs.Pop()
+			continue
 		}
-		snap := w.current.state.Snapshot()
 		_, err := w.commitTransaction(tx, w.coinbase)
-		if len(selected) > maxNumTxs {
-			unselected = append(unselected, tx)
-		} else {
-			if err != nil {
-				w.current.state.RevertToSnapshot(snap)
-				invalid = append(invalid, tx)
-				log.Debug("Invalid transaction", "Error", err)
-			} else {
-				selected = append(selected, tx)
-			}
+		switch err {
+		case nil:
+			selected = append(selected, tx)
+			txs.Shift()
+		case core.ErrGasLimitReached:
+			// Not enough gas left in the block for this sender; try the others.
+			txs.Pop()
+		case core.ErrNonceTooLow:
+			// Already included; the pool drops it when it catches up with the chain.
+			txs.Shift()
+		case core.ErrNonceTooHigh:
+			// A nonce gap; the later transactions of the sender cannot be executed.
+			txs.Pop()
+		default:
+			invalid = append(invalid, tx)
+			log.Debug("Invalid transaction", "Error", err)
+			txs.Shift()
 		}
 	}
 	err := w.UpdateCurrent()
 	if err != nil {
 		log.Debug("Failed updating worker's state", "Error", err)
 	}
-	return selected, unselected, invalid
+	return selected, invalid
 }
 
 func (w *Worker) commitTransaction(tx *types.Transaction, coinbase common.Address) ([]*types.Log, error) {
 	snap := w.current.state.Snapshot()
 
-	receipt, _, err := core.ApplyTransaction(w.config, w.chain, &coinbase, w.current.gasPool, w.current.state, w.current.header, tx, &w.current.header.GasUsed, vm.Config{})
+	receipt, _, err := core.ApplyTransaction(w.config, w.chain.HarmonyConfig(), w.chain, &coinbase, w.current.gasPool, w.current.state, w.current.header, tx, &w.current.header.GasUsed, vm.Config{})
 	if err != nil {
 		w.current.state.RevertToSnapshot(snap)
 		return nil, err
@@ -96,12 +120,8 @@ func (w *Worker) CommitTransactions(txs types.Transactions) error {
 		w.current.gasPool = new(core.GasPool).AddGas(w.current.header.GasLimit)
 	}
 	for _, tx := range txs {
-		snap := w.current.state.Snapshot()
-		_, err := w.commitTransaction(tx, w.coinbase)
-		if err != nil {
-			w.current.state.RevertToSnapshot(snap)
+		if _, err := w.commitTransaction(tx, w.coinbase); err != nil {
 			return err
-
 		}
 	}
 	return nil
@@ -121,7 +141,7 @@ func (w *Worker) UpdateCurrent() error {
 	header := &types.Header{
 		ParentHash: parent.Hash(),
 		Number:     num.Add(num, common.Big1),
-		GasLimit:   core.CalcGasLimit(parent, w.gasFloor, w.gasCeil),
+		GasLimit:   w.calcGasLimit(parent, epoch),
 		Time:       big.NewInt(timestamp),
 		Epoch:      epoch,
 		ShardID:    w.chain.ShardID(),
@@ -129,6 +149,16 @@ func (w *Worker) UpdateCurrent() error {
 	return w.makeCurrent(parent, header)
 }
 
+// calcGasLimit returns the gas limit of the block after parent in epoch. Once
+// the gas target fork is active, it follows the gas target policy of the shard.
+func (w *Worker) calcGasLimit(parent *types.Block, epoch *big.Int) uint64 {
+	config := w.chain.HarmonyConfig()
+	if config.IsGasTarget(epoch) {
+		return core.CalcGasLimitByPolicy(parent, config.GasTargetPolicy(w.chain.ShardID()))
+	}
+	return core.CalcGasLimit(parent, w.gasFloor, w.gasCeil)
+}
+
 // makeCurrent creates a new environment for the current cycle.
 func (w *Worker) makeCurrent(parent *types.Block, header *types.Header) error {
 	state, err := w.chain.StateAt(parent.Root())
@@ -188,7 +218,7 @@ func New(config *params.ChainConfig, chain *core.BlockChain, engine consensus_en
 	header := &types.Header{
 		ParentHash: parent.Hash(),
 		Number:     num.Add(num, common.Big1),
-		GasLimit:   core.CalcGasLimit(parent, worker.gasFloor, worker.gasCeil),
+		GasLimit:   worker.calcGasLimit(parent, epoch),
 		Time:       big.NewInt(timestamp),
 		Epoch:      epoch,
 		ShardID:    worker.chain.ShardID(),
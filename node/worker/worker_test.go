@@ -5,6 +5,7 @@ import (
 	"math/rand"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/params"
@@ -83,3 +84,51 @@ func TestCommitTransactions(t *testing.T) {
 		t.Error("Transaction is not committed")
 	}
 }
+
+func TestSelectTransactionsForNewBlock(t *testing.T) {
+	var (
+		database = ethdb.NewMemDatabase()
+		gspec    = core.Genesis{
+			Config:  chainConfig,
+			Alloc:   core.GenesisAlloc{testBankAddress: {Balance: testBankFunds}},
+			ShardID: 10,
+		}
+	)
+
+	gspec.MustCommit(database)
+	chain, _ := core.NewBlockChain(database, nil, gspec.Config, consensus.NewFaker(), vm.Config{}, nil)
+	worker := New(params.TestChainConfig, chain, consensus.NewFaker(), testBankAddress, 0)
+
+	newTx := func(nonce uint64, shardID uint32) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, testBankAddress, shardID, big.NewInt(1), params.TxGas, nil, nil), types.HomesteadSigner{}, testBankKey)
+		return tx
+	}
+	// Nonce 2 is missing, so the transaction of nonce 3 cannot be executed yet.
+	pending := map[common.Address]types.Transactions{
+		testBankAddress: {newTx(0, 0), newTx(1, 0), newTx(3, 0)},
+	}
+	selected, invalid := worker.SelectTransactionsForNewBlock(pending, 10)
+	if len(selected) != 2 || selected[0].Nonce() != 0 || selected[1].Nonce() != 1 {
+		t.Errorf("selected %d transactions, expected the ones of nonce 0 and 1", len(selected))
+	}
+	if len(invalid) != 0 {
+		t.Errorf("%d invalid transactions, expected none", len(invalid))
+	}
+
+	// A transaction of another shard is invalid, and so are the later ones of its sender.
+	pending = map[common.Address]types.Transactions{
+		testBankAddress: {newTx(0, 1), newTx(1, 0)},
+	}
+	selected, invalid = worker.SelectTransactionsForNewBlock(pending, 10)
+	if len(selected) != 0 || len(invalid) != 1 {
+		t.Errorf("selected %d, invalid %d transactions, expected = 0, 1", len(selected), len(invalid))
+	}
+
+	// At most maxNumTxs transactions are selected.
+	pending = map[common.Address]types.Transactions{
+		testBankAddress: {newTx(0, 0), newTx(1, 0), newTx(2, 0)},
+	}
+	if selected, _ = worker.SelectTransactionsForNewBlock(pending, 1); len(selected) != 1 {
+		t.Errorf("selected %d transactions, expected = 1", len(selected))
+	}
+}
@@ -0,0 +1,109 @@
+package p2p
+
+import (
+	"fmt"
+	"time"
+
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// Pubsub routers for group messaging.
+const (
+	// GossipSub relays messages to a mesh of a few peers per group and
+	// gossips message IDs to the others.
+	GossipSub = "gossipsub"
+	// FloodSub relays every message to every peer of the group.
+	FloodSub = "floodsub"
+)
+
+// HostConfig configures a Host.
+type HostConfig struct {
+	// Network is the network the host belongs to; see NetworkScope.
+	Network NetworkScope
+
+	PubSub PubSubConfig
+
+	// PeersFile is the file in which the host keeps its known peers across
+	// restarts, to redial some of them on startup. Empty disables it.
+	PeersFile string
+
+	NAT NATConfig
+}
+
+// NATConfig configures how a host behind a NAT gets reachable, and how a
+// public host helps others to. The zero value disables all of it.
+type NATConfig struct {
+	// PortMap asks the gateway to forward the listen port to the host, with
+	// UPnP or NAT-PMP.
+	PortMap bool
+	// AutoNAT asks peers serving AutoNAT to dial the host back, to learn
+	// whether it is reachable and at which public address.
+	AutoNAT bool
+	// Relays are the p2p multiaddrs of circuit relays the host stays
+	// connected to. When AutoNAT finds the host unreachable, or no public
+	// address of it is known, it advertises addresses through them instead.
+	Relays []ma.Multiaddr
+
+	// Service serves AutoNAT dial-backs and relays connections for other
+	// peers. Only publicly reachable hosts, such as bootnodes, should.
+	Service bool
+}
+
+// PubSubConfig configures the group messaging of a Host.
+type PubSubConfig struct {
+	// Router is GossipSub or FloodSub.
+	Router string
+
+	// D is the number of peers of the gossipsub mesh of a group, which the
+	// heartbeat keeps between Dlo and Dhi.
+	D, Dlo, Dhi int
+	// HeartbeatInterval is the interval of the gossipsub heartbeat.
+	HeartbeatInterval time.Duration
+	// HistoryLength is the number of heartbeats for which messages are kept
+	// for peers that ask for them, and HistoryGossip the number of heartbeats
+	// for which their IDs are gossiped.
+	HistoryLength, HistoryGossip int
+	// FanoutTTL is how long the peers of a group we publish to but did not
+	// join are kept.
+	FanoutTTL time.Duration
+
+	// DedupTTL is how long the IDs of received messages are remembered to
+	// drop copies of the same message relayed by other peers.
+	DedupTTL time.Duration
+}
+
+// Validate checks the router and the gossipsub parameters.
+func (c PubSubConfig) Validate() error {
+	switch c.Router {
+	case FloodSub:
+		return nil
+	case GossipSub:
+	default:
+		return fmt.Errorf("unknown pubsub router %q", c.Router)
+	}
+	if c.Dlo < 1 || c.Dlo > c.D || c.D > c.Dhi {
+		return fmt.Errorf("invalid gossipsub mesh degrees: need 1 <= Dlo %d <= D %d <= Dhi %d", c.Dlo, c.D, c.Dhi)
+	}
+	if c.HeartbeatInterval <= 0 {
+		return fmt.Errorf("invalid gossipsub heartbeat interval %v", c.HeartbeatInterval)
+	}
+	if c.HistoryGossip > c.HistoryLength {
+		return fmt.Errorf("invalid gossipsub history: gossip %d > length %d", c.HistoryGossip, c.HistoryLength)
+	}
+	return nil
+}
+
+// DefaultHostConfig is the configuration of hosts created without one.
+var DefaultHostConfig = HostConfig{
+	PubSub: PubSubConfig{
+		Router:            GossipSub,
+		D:                 6,
+		Dlo:               4,
+		Dhi:               12,
+		HeartbeatInterval: time.Second,
+		HistoryLength:     5,
+		HistoryGossip:     3,
+		FanoutTTL:         time.Minute,
+		DedupTTL:          2 * time.Minute,
+	},
+}
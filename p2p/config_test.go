@@ -0,0 +1,29 @@
+package p2p
+
+import (
+	"testing"
+)
+
+func TestPubSubConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *PubSubConfig)
+		valid  bool
+	}{
+		{"default", func(c *PubSubConfig) {}, true},
+		{"floodsub", func(c *PubSubConfig) { c.Router, c.D = FloodSub, 0 }, true},
+		{"unknown router", func(c *PubSubConfig) { c.Router = "randomsub" }, false},
+		{"D below Dlo", func(c *PubSubConfig) { c.D = c.Dlo - 1 }, false},
+		{"D above Dhi", func(c *PubSubConfig) { c.D = c.Dhi + 1 }, false},
+		{"zero Dlo", func(c *PubSubConfig) { c.Dlo, c.D = 0, 0 }, false},
+		{"zero heartbeat", func(c *PubSubConfig) { c.HeartbeatInterval = 0 }, false},
+		{"long gossip", func(c *PubSubConfig) { c.HistoryGossip = c.HistoryLength + 1 }, false},
+	}
+	for _, test := range tests {
+		config := DefaultHostConfig.PubSub
+		test.modify(&config)
+		if err := config.Validate(); (err == nil) != test.valid {
+			t.Errorf("%s: expected valid %v, got error %v", test.name, test.valid, err)
+		}
+	}
+}
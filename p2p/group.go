@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	libp2p_peer "github.com/libp2p/go-libp2p-peer"
+	protocol "github.com/libp2p/go-libp2p-protocol"
 )
 
 // GroupID is a multicast group ID.
@@ -34,6 +37,63 @@ const (
 	GroupIDUnknown           GroupID = "B1acKh0lE"
 )
 
+// NetworkScope identifies the network of a host by its network ID and the
+// hash of its beacon chain genesis block. Hosts scope the groups they join,
+// the rendezvous points they advertise and the pubsub and DHT protocols they
+// speak to their network, so that nodes of different networks which connect
+// to each other never exchange messages or routing records. The zero
+// NetworkScope scopes nothing.
+type NetworkScope struct {
+	NetworkID   uint64
+	GenesisHash common.Hash
+}
+
+// IsZero reports whether s is the zero NetworkScope.
+func (s NetworkScope) IsZero() bool {
+	return s == NetworkScope{}
+}
+
+func (s NetworkScope) String() string {
+	return fmt.Sprintf("%d/%x", s.NetworkID, s.GenesisHash[:8])
+}
+
+// GroupID returns the ID of a group in the network.
+func (s NetworkScope) GroupID(group GroupID) GroupID {
+	if s.IsZero() {
+		return group
+	}
+	return GroupID(fmt.Sprintf("%s/%s", s, group))
+}
+
+// Unscope returns the group of an ID returned by GroupID, and false if the ID
+// belongs to another network.
+func (s NetworkScope) Unscope(id GroupID) (GroupID, bool) {
+	if s.IsZero() {
+		return id, true
+	}
+	prefix := s.String() + "/"
+	if !strings.HasPrefix(string(id), prefix) {
+		return id, false
+	}
+	return id[len(prefix):], true
+}
+
+// ProtocolID returns the ID of a stream protocol in the network.
+func (s NetworkScope) ProtocolID(id protocol.ID) protocol.ID {
+	if s.IsZero() {
+		return id
+	}
+	return protocol.ID(fmt.Sprintf("/harmony/%s%s", s, id))
+}
+
+// UnscopeProtocolID returns the protocol of an ID returned by ProtocolID.
+func (s NetworkScope) UnscopeProtocolID(id protocol.ID) protocol.ID {
+	if s.IsZero() {
+		return id
+	}
+	return protocol.ID(strings.TrimPrefix(string(id), fmt.Sprintf("/harmony/%s", s)))
+}
+
 // ShardID defines the ID of a shard
 type ShardID uint32
 
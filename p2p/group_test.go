@@ -1,6 +1,11 @@
 package p2p
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	protocol "github.com/libp2p/go-libp2p-protocol"
+)
 
 func TestGroupID_String(t *testing.T) {
 	tests := []struct {
@@ -62,3 +67,33 @@ func TestGroupAction(t *testing.T) {
 	}
 
 }
+
+func TestNetworkScope(t *testing.T) {
+	testnet := NetworkScope{NetworkID: 2, GenesisHash: common.HexToHash("0x0123456789abcdef")}
+	devnet := NetworkScope{NetworkID: 3, GenesisHash: testnet.GenesisHash}
+
+	id := testnet.GroupID(GroupIDBeacon)
+	if id == GroupIDBeacon || id == devnet.GroupID(GroupIDBeacon) {
+		t.Errorf("group ID not scoped to the network: %s", id)
+	}
+	if group, ok := testnet.Unscope(id); !ok || group != GroupIDBeacon {
+		t.Errorf("Unscope(%s) = %s, %v", id, group, ok)
+	}
+	if _, ok := devnet.Unscope(id); ok {
+		t.Errorf("group ID %s of another network accepted", id)
+	}
+
+	const meshsub = protocol.ID("/meshsub/1.0.0")
+	pid := testnet.ProtocolID(meshsub)
+	if pid == meshsub || pid == devnet.ProtocolID(meshsub) {
+		t.Errorf("protocol ID not scoped to the network: %s", pid)
+	}
+	if got := testnet.UnscopeProtocolID(pid); got != meshsub {
+		t.Errorf("UnscopeProtocolID(%s) = %s", pid, got)
+	}
+
+	var unscoped NetworkScope
+	if unscoped.GroupID(GroupIDBeacon) != GroupIDBeacon || unscoped.ProtocolID(meshsub) != meshsub {
+		t.Error("zero scope changed IDs")
+	}
+}
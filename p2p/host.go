@@ -3,6 +3,7 @@ package p2p
 import (
 	libp2p_host "github.com/libp2p/go-libp2p-host"
 	libp2p_peer "github.com/libp2p/go-libp2p-peer"
+	ma "github.com/multiformats/go-multiaddr"
 )
 
 //go:generate mockgen -source host.go -destination=host/mock/host_mock.go
@@ -16,6 +17,21 @@ type Host interface {
 	GetP2PHost() libp2p_host.Host
 	GetPeerCount() int
 
+	// GetPeerScorer returns the scorer of the peers of the host. Peers it bans
+	// are disconnected and cannot connect again until the ban expires.
+	GetPeerScorer() *PeerScorer
+
+	// GetTrafficStats returns the traffic counters of the host.
+	GetTrafficStats() TrafficStats
+
+	// GetAdvertisedAddrs returns the addresses peers can reach the host at,
+	// as far as it knows, to advertise in discovery messages.
+	GetAdvertisedAddrs() []ma.Multiaddr
+
+	// GetNetworkScope returns the network of the host, to which its groups
+	// and rendezvous points are scoped.
+	GetNetworkScope() NetworkScope
+
 	//AddIncomingPeer(Peer)
 	//AddOutgoingPeer(Peer)
 	ConnectHostPeer(Peer)
@@ -13,10 +13,14 @@ import (
 	"github.com/harmony-one/harmony/p2p"
 
 	libp2p "github.com/libp2p/go-libp2p"
+	autonat "github.com/libp2p/go-libp2p-autonat"
 	libp2p_crypto "github.com/libp2p/go-libp2p-crypto"
 	libp2p_host "github.com/libp2p/go-libp2p-host"
+	libp2p_metrics "github.com/libp2p/go-libp2p-metrics"
+	libp2p_net "github.com/libp2p/go-libp2p-net"
 	libp2p_peer "github.com/libp2p/go-libp2p-peer"
 	libp2p_peerstore "github.com/libp2p/go-libp2p-peerstore"
+	protocol "github.com/libp2p/go-libp2p-protocol"
 	libp2p_pubsub "github.com/libp2p/go-libp2p-pubsub"
 	ma "github.com/multiformats/go-multiaddr"
 )
@@ -36,6 +40,7 @@ const (
 type pubsub interface {
 	Publish(topic string, data []byte) error
 	Subscribe(topic string, opts ...libp2p_pubsub.SubOpt) (*libp2p_pubsub.Subscription, error)
+	RegisterTopicValidator(topic string, val libp2p_pubsub.Validator, opts ...libp2p_pubsub.ValidatorOpt) error
 }
 
 // HostV2 is the version 2 p2p host
@@ -45,6 +50,21 @@ type HostV2 struct {
 	self   p2p.Peer
 	priKey libp2p_crypto.PrivKey
 	lock   sync.Mutex
+	scorer *p2p.PeerScorer
+	scope  p2p.NetworkScope
+
+	validatedGroups map[p2p.GroupID]bool // groups with a registered validator, guarded by lock
+	seen            *messageIDCache
+	traffic         trafficCounters
+	bandwidth       *libp2p_metrics.BandwidthCounter
+
+	lastSeen  peerSeen
+	quit      chan struct{} // closed when the host closes
+	persisted chan struct{} // closed once the known peers are saved, if saved
+	closeOnce sync.Once
+
+	autonat autonat.AutoNAT // nil unless AutoNAT is enabled
+	relays  []libp2p_peerstore.PeerInfo
 
 	//incomingPeers []p2p.Peer // list of incoming Peers. TODO: fixed number incoming
 	//outgoingPeers []p2p.Peer // list of outgoing Peers. TODO: fixed number of outgoing
@@ -57,10 +77,15 @@ type HostV2 struct {
 func (host *HostV2) SendMessageToGroups(groups []p2p.GroupID, msg []byte) error {
 	var error error
 	for _, group := range groups {
-		err := host.pubsub.Publish(string(group), msg)
+		err := host.pubsub.Publish(string(host.scope.GroupID(group)), msg)
 		if err != nil {
 			error = err
+			continue
 		}
+		host.traffic.update([]string{string(group)}, func(stats *p2p.GroupTrafficStats) {
+			stats.Published++
+			stats.PublishedBytes += uint64(len(msg))
+		})
 	}
 	return error
 }
@@ -103,37 +128,64 @@ func (r *GroupReceiverImpl) Receive(ctx context.Context) (
 func (host *HostV2) GroupReceiver(group p2p.GroupID) (
 	receiver p2p.GroupReceiver, err error,
 ) {
-	sub, err := host.pubsub.Subscribe(string(group))
+	if err := host.registerGroupValidator(group); err != nil {
+		return nil, err
+	}
+	sub, err := host.pubsub.Subscribe(string(host.scope.GroupID(group)))
 	if err != nil {
 		return nil, err
 	}
 	return &GroupReceiverImpl{sub: sub}, nil
 }
 
-// AddPeer add p2p.Peer into Peerstore
-func (host *HostV2) AddPeer(p *p2p.Peer) error {
-	if p.PeerID != "" && len(p.Addrs) != 0 {
-		host.Peerstore().AddAddrs(p.PeerID, p.Addrs, libp2p_peerstore.PermanentAddrTTL)
+// registerGroupValidator registers the message validator of a group, once.
+func (host *HostV2) registerGroupValidator(group p2p.GroupID) error {
+	host.lock.Lock()
+	defer host.lock.Unlock()
+	if host.validatedGroups[group] {
 		return nil
 	}
+	topic := string(host.scope.GroupID(group))
+	if err := host.pubsub.RegisterTopicValidator(topic, host.validateGroupMessage); err != nil {
+		return err
+	}
+	if host.validatedGroups == nil {
+		host.validatedGroups = make(map[p2p.GroupID]bool)
+	}
+	host.validatedGroups[group] = true
+	return nil
+}
 
+// AddPeer add p2p.Peer into Peerstore
+func (host *HostV2) AddPeer(p *p2p.Peer) error {
+	if p.PeerID != "" && !host.scorer.InterceptPeerDial(p.PeerID) {
+		return fmt.Errorf("AddPeer error: peer %s is banned", p.PeerID.Pretty())
+	}
 	if p.PeerID == "" {
 		host.logger.Error("AddPeer PeerID is EMPTY")
 		return fmt.Errorf("AddPeer error: peerID is empty")
 	}
 
+	// The addresses the peer advertises, such as its public or relay
+	// addresses, may change, so they expire unless advertised again.
+	if len(p.Addrs) != 0 {
+		host.Peerstore().AddAddrs(p.PeerID, p.Addrs, libp2p_peerstore.AddressTTL)
+	}
+
 	// reconstruct the multiaddress based on ip/port
 	// PeerID has to be known for the ip/port
 	addr := fmt.Sprintf("/ip4/%s/tcp/%s", p.IP, p.Port)
 	targetAddr, err := ma.NewMultiaddr(addr)
 	if err != nil {
+		if len(p.Addrs) != 0 {
+			host.logger.Debug("AddPeer without ip/port", "error", err, "peer", *p)
+			return nil
+		}
 		host.logger.Error("AddPeer NewMultiaddr error", "error", err)
 		return err
 	}
 
-	p.Addrs = append(p.Addrs, targetAddr)
-
-	host.Peerstore().AddAddrs(p.PeerID, p.Addrs, libp2p_peerstore.PermanentAddrTTL)
+	host.Peerstore().AddAddr(p.PeerID, targetAddr, libp2p_peerstore.PermanentAddrTTL)
 	host.logger.Info("AddPeer add to libp2p_peerstore", "peer", *p)
 
 	return nil
@@ -154,8 +206,13 @@ func (host *HostV2) Peerstore() libp2p_peerstore.Peerstore {
 	return host.h.Peerstore()
 }
 
-// New creates a host for p2p communication
+// New creates a host for p2p communication with the default configuration.
 func New(self *p2p.Peer, priKey libp2p_crypto.PrivKey) *HostV2 {
+	return NewWithConfig(self, priKey, p2p.DefaultHostConfig)
+}
+
+// NewWithConfig creates a host for p2p communication
+func NewWithConfig(self *p2p.Peer, priKey libp2p_crypto.PrivKey, config p2p.HostConfig) *HostV2 {
 	listenAddr, err := ma.NewMultiaddr(fmt.Sprintf("/ip4/0.0.0.0/tcp/%s", self.Port))
 	logger := utils.GetLogInstance()
 	if err != nil {
@@ -164,12 +221,14 @@ func New(self *p2p.Peer, priKey libp2p_crypto.PrivKey) *HostV2 {
 	}
 	// TODO – use WithCancel for orderly host teardown (which we don't have yet)
 	ctx := context.Background()
-	p2pHost, err := libp2p.New(ctx,
+	bandwidth := libp2p_metrics.NewBandwidthCounter()
+	opts := []libp2p.Option{
 		libp2p.ListenAddrs(listenAddr), libp2p.Identity(priKey),
-	)
+		libp2p.BandwidthReporter(bandwidth),
+	}
+	p2pHost, err := libp2p.New(ctx, append(opts, natOptions(config.NAT)...)...)
 	catchError(err)
-	pubsub, err := libp2p_pubsub.NewGossipSub(ctx, p2pHost)
-	// pubsub, err := libp2p_pubsub.NewFloodSub(ctx, p2pHost)
+	pubsub, err := newPubSub(ctx, scopeHost(p2pHost, config.Network), config.PubSub)
 	catchError(err)
 
 	self.PeerID = p2pHost.ID()
@@ -180,15 +239,80 @@ func New(self *p2p.Peer, priKey libp2p_crypto.PrivKey) *HostV2 {
 		pubsub: pubsub,
 		self:   *self,
 		priKey: priKey,
+		scorer: p2p.NewPeerScorer(p2p.DefaultPeerScoreConfig),
+		scope:  config.Network,
 		logger: logger.New("hostID", p2pHost.ID().Pretty()),
+
+		seen:      newMessageIDCache(config.PubSub.DedupTTL),
+		bandwidth: bandwidth,
+		quit:      make(chan struct{}),
+	}
+	h.scorer.SetBanHandler(h.disconnectPeer)
+	p2pHost.Network().Notify(&libp2p_net.NotifyBundle{
+		ConnectedF:    h.gateConnection,
+		DisconnectedF: h.trackPeer,
+	})
+	h.startNAT(ctx, config.NAT)
+	if config.PeersFile != "" {
+		h.restorePeers(config.PeersFile)
+		h.persisted = make(chan struct{})
+		go h.persistPeersLoop(config.PeersFile)
 	}
 
 	h.logger.Debug("HostV2 is up!",
-		"port", self.Port, "id", p2pHost.ID().Pretty(), "addr", listenAddr)
+		"port", self.Port, "id", p2pHost.ID().Pretty(), "addr", listenAddr,
+		"router", config.PubSub.Router, "network", config.Network, "nat", config.NAT)
 
 	return h
 }
 
+// newPubSub creates the pubsub router of a host. Messages are signed by their
+// author and unsigned messages are rejected.
+func newPubSub(ctx context.Context, p2pHost libp2p_host.Host, config p2p.PubSubConfig) (*libp2p_pubsub.PubSub, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	opts := []libp2p_pubsub.Option{
+		libp2p_pubsub.WithMessageSigning(true),
+		libp2p_pubsub.WithStrictSignatureVerification(true),
+	}
+	if config.Router == p2p.FloodSub {
+		return libp2p_pubsub.NewFloodSub(ctx, p2pHost, opts...)
+	}
+	if err := setGossipSubParams(config); err != nil {
+		return nil, err
+	}
+	return libp2p_pubsub.NewGossipSub(ctx, p2pHost, opts...)
+}
+
+var (
+	gossipSubParamsOnce sync.Once
+	gossipSubParams     p2p.PubSubConfig
+)
+
+// setGossipSubParams sets the gossipsub parameters. The libp2p version in use
+// keeps them in globals, read by the routers of all hosts without locking, so
+// the first gossipsub host of the process sets them before any router runs,
+// and the later ones must use the same.
+func setGossipSubParams(config p2p.PubSubConfig) error {
+	params := config
+	params.Router, params.DedupTTL = "", 0
+	gossipSubParamsOnce.Do(func() {
+		gossipSubParams = params
+		libp2p_pubsub.GossipSubD = params.D
+		libp2p_pubsub.GossipSubDlo = params.Dlo
+		libp2p_pubsub.GossipSubDhi = params.Dhi
+		libp2p_pubsub.GossipSubHeartbeatInterval = params.HeartbeatInterval
+		libp2p_pubsub.GossipSubHistoryLength = params.HistoryLength
+		libp2p_pubsub.GossipSubHistoryGossip = params.HistoryGossip
+		libp2p_pubsub.GossipSubFanoutTTL = params.FanoutTTL
+	})
+	if params != gossipSubParams {
+		return fmt.Errorf("gossipsub parameters %+v differ from %+v, already in use by another host", params, gossipSubParams)
+	}
+	return nil
+}
+
 // GetID returns ID.Pretty
 func (host *HostV2) GetID() libp2p_peer.ID {
 	return host.h.ID()
@@ -199,11 +323,25 @@ func (host *HostV2) GetSelfPeer() p2p.Peer {
 	return host.self
 }
 
-// Close closes the host
+// Close closes the host, after saving its known peers if configured to.
 func (host *HostV2) Close() error {
+	host.closeOnce.Do(func() {
+		if host.quit == nil {
+			return
+		}
+		close(host.quit)
+		if host.persisted != nil {
+			<-host.persisted
+		}
+	})
 	return host.h.Close()
 }
 
+// GetNetworkScope returns the network of the host.
+func (host *HostV2) GetNetworkScope() p2p.NetworkScope {
+	return host.scope
+}
+
 // GetP2PHost returns the p2p.Host
 func (host *HostV2) GetP2PHost() libp2p_host.Host {
 	return host.h
@@ -214,8 +352,51 @@ func (host *HostV2) GetPeerCount() int {
 	return host.h.Peerstore().Peers().Len()
 }
 
+// GetPeerScorer returns the peer scorer of the host.
+func (host *HostV2) GetPeerScorer() *p2p.PeerScorer {
+	return host.scorer
+}
+
+// GetTrafficStats returns the traffic counters of the host.
+func (host *HostV2) GetTrafficStats() p2p.TrafficStats {
+	stats := p2p.TrafficStats{Groups: host.traffic.snapshot()}
+	if host.bandwidth != nil {
+		total := host.bandwidth.GetBandwidthTotals()
+		stats.TotalIn, stats.TotalOut = total.TotalIn, total.TotalOut
+		stats.RateIn, stats.RateOut = total.RateIn, total.RateOut
+		for _, id := range []protocol.ID{libp2p_pubsub.GossipSubID, libp2p_pubsub.FloodSubID} {
+			pubsub := host.bandwidth.GetBandwidthForProtocol(id)
+			stats.PubSubIn += pubsub.TotalIn
+			stats.PubSubOut += pubsub.TotalOut
+		}
+	}
+	return stats
+}
+
+// disconnectPeer closes the connections with a peer that got banned.
+func (host *HostV2) disconnectPeer(id libp2p_peer.ID) {
+	host.logger.Warn("banning peer", "peer", id.Pretty())
+	if err := host.h.Network().ClosePeer(id); err != nil {
+		host.logger.Warn("cannot disconnect banned peer", "error", err, "peer", id.Pretty())
+	}
+}
+
+// gateConnection closes new connections with banned peers.
+func (host *HostV2) gateConnection(net libp2p_net.Network, conn libp2p_net.Conn) {
+	if !host.scorer.InterceptSecured(conn.RemotePeer()) {
+		host.logger.Debug("refusing connection with banned peer", "peer", conn.RemotePeer().Pretty())
+		conn.Close()
+		return
+	}
+	host.trackPeer(net, conn)
+}
+
 // ConnectHostPeer connects to peer host
 func (host *HostV2) ConnectHostPeer(peer p2p.Peer) {
+	if !host.scorer.InterceptPeerDial(peer.PeerID) {
+		host.logger.Debug("not connecting to banned peer", "peer", peer)
+		return
+	}
 	ctx := context.Background()
 	addr := fmt.Sprintf("/ip4/%s/tcp/%s/ipfs/%s", peer.IP, peer.Port, peer.PeerID.Pretty())
 	peerAddr, err := ma.NewMultiaddr(addr)
@@ -229,6 +410,9 @@ func (host *HostV2) ConnectHostPeer(peer p2p.Peer) {
 			peer)
 		return
 	}
+	// Also try the addresses the peer advertises, such as its public or relay
+	// addresses when it is behind a NAT.
+	peerInfo.Addrs = append(peerInfo.Addrs, peer.Addrs...)
 	if err := host.h.Connect(ctx, *peerInfo); err != nil {
 		host.logger.Warn("can't connect to peer", "error", err, "peer", peer)
 	} else {
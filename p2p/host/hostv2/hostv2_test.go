@@ -2,6 +2,7 @@ package hostv2
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"reflect"
 	"testing"
@@ -10,7 +11,10 @@ import (
 	libp2p_peer "github.com/libp2p/go-libp2p-peer"
 	libp2p_pubsub "github.com/libp2p/go-libp2p-pubsub"
 	libp2p_pubsub_pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	ma "github.com/multiformats/go-multiaddr"
 
+	"github.com/harmony-one/harmony/internal/utils"
 	"github.com/harmony-one/harmony/p2p"
 	mock "github.com/harmony-one/harmony/p2p/host/hostv2/mock"
 )
@@ -59,8 +63,15 @@ func TestGroupReceiver_Close(t *testing.T) {
 	}
 }
 
+var pubsubSeqno uint64
+
+// pubsubMessage returns a new message, with the next sequence number, as if
+// published by from.
 func pubsubMessage(from libp2p_peer.ID, data []byte) *libp2p_pubsub.Message {
-	m := libp2p_pubsub_pb.Message{From: []byte(from), Data: data}
+	pubsubSeqno++
+	seqno := make([]byte, 8)
+	binary.BigEndian.PutUint64(seqno, pubsubSeqno)
+	m := libp2p_pubsub_pb.Message{From: []byte(from), Data: data, Seqno: seqno}
 	return &libp2p_pubsub.Message{Message: &m}
 }
 
@@ -102,6 +113,7 @@ func TestHostV2_GroupReceiver(t *testing.T) {
 		defer mc.Finish()
 		sub := &libp2p_pubsub.Subscription{}
 		pubsub := mock.NewMockpubsub(mc)
+		pubsub.EXPECT().RegisterTopicValidator("ABC", gomock.Any())
 		pubsub.EXPECT().Subscribe("ABC").Return(sub, nil)
 		host := &HostV2{pubsub: pubsub}
 		gotReceiver, err := host.GroupReceiver("ABC")
@@ -114,10 +126,38 @@ func TestHostV2_GroupReceiver(t *testing.T) {
 			t.Errorf("expected no error; got %v", err)
 		}
 	})
+	t.Run("ValidatorOnce", func(t *testing.T) {
+		mc := gomock.NewController(t)
+		defer mc.Finish()
+		pubsub := mock.NewMockpubsub(mc)
+		pubsub.EXPECT().RegisterTopicValidator("ABC", gomock.Any())
+		pubsub.EXPECT().Subscribe("ABC").Return(&libp2p_pubsub.Subscription{}, nil).Times(2)
+		host := &HostV2{pubsub: pubsub}
+		for i := 0; i < 2; i++ {
+			if _, err := host.GroupReceiver("ABC"); err != nil {
+				t.Errorf("expected no error; got %v", err)
+			}
+		}
+	})
+	t.Run("ValidatorError", func(t *testing.T) {
+		mc := gomock.NewController(t)
+		defer mc.Finish()
+		pubsub := mock.NewMockpubsub(mc)
+		pubsub.EXPECT().RegisterTopicValidator("ABC", gomock.Any()).Return(errors.New("FIAL"))
+		host := &HostV2{pubsub: pubsub}
+		gotReceiver, err := host.GroupReceiver("ABC")
+		if gotReceiver != nil {
+			t.Errorf("expected a nil hostv2 GroupReceiverImpl; got %v", gotReceiver)
+		}
+		if err == nil {
+			t.Error("expected an error; got none")
+		}
+	})
 	t.Run("Error", func(t *testing.T) {
 		mc := gomock.NewController(t)
 		defer mc.Finish()
 		pubsub := mock.NewMockpubsub(mc)
+		pubsub.EXPECT().RegisterTopicValidator("ABC", gomock.Any())
 		pubsub.EXPECT().Subscribe("ABC").Return(nil, errors.New("FIAL"))
 		host := &HostV2{pubsub: pubsub}
 		gotReceiver, err := host.GroupReceiver("ABC")
@@ -136,3 +176,36 @@ func TestHostV2_GroupReceiver(t *testing.T) {
 		}
 	})
 }
+
+func TestHostV2_AddPeer(t *testing.T) {
+	p2pHost, err := mocknet.New(context.Background()).GenPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	host := &HostV2{
+		h:      p2pHost,
+		scorer: p2p.NewPeerScorer(p2p.DefaultPeerScoreConfig),
+		logger: utils.GetLogInstance(),
+	}
+	advertised, err := ma.NewMultiaddr("/ip4/1.2.3.4/tcp/9000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &p2p.Peer{IP: "127.0.0.1", Port: "9000", PeerID: "peer", Addrs: []ma.Multiaddr{advertised}}
+	if err := host.AddPeer(p); err != nil {
+		t.Fatalf("AddPeer: %v", err)
+	}
+	addrs := map[string]bool{}
+	for _, addr := range host.Peerstore().Addrs(p.PeerID) {
+		addrs[addr.String()] = true
+	}
+	if len(addrs) != 2 || !addrs["/ip4/127.0.0.1/tcp/9000"] || !addrs[advertised.String()] {
+		t.Errorf("unexpected addresses %v", addrs)
+	}
+	if len(p.Addrs) != 1 {
+		t.Errorf("AddPeer changed the addresses of the peer: %v", p.Addrs)
+	}
+	if err := host.AddPeer(&p2p.Peer{IP: "127.0.0.1", Port: "9000"}); err == nil {
+		t.Error("peer without ID added")
+	}
+}
@@ -68,6 +68,25 @@ func (mr *MockpubsubMockRecorder) Subscribe(topic interface{}, opts ...interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*Mockpubsub)(nil).Subscribe), varargs...)
 }
 
+// RegisterTopicValidator mocks base method
+func (m *Mockpubsub) RegisterTopicValidator(topic string, val go_libp2p_pubsub.Validator, opts ...go_libp2p_pubsub.ValidatorOpt) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{topic, val}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RegisterTopicValidator", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterTopicValidator indicates an expected call of RegisterTopicValidator
+func (mr *MockpubsubMockRecorder) RegisterTopicValidator(topic, val interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{topic, val}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterTopicValidator", reflect.TypeOf((*Mockpubsub)(nil).RegisterTopicValidator), varargs...)
+}
+
 // Mocksubscription is a mock of subscription interface
 type Mocksubscription struct {
 	ctrl     *gomock.Controller
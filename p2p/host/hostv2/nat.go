@@ -0,0 +1,152 @@
+package hostv2
+
+import (
+	"context"
+	"time"
+
+	"github.com/harmony-one/harmony/p2p"
+
+	libp2p "github.com/libp2p/go-libp2p"
+	autonat "github.com/libp2p/go-libp2p-autonat"
+	autonatsvc "github.com/libp2p/go-libp2p-autonat-svc"
+	circuit "github.com/libp2p/go-libp2p-circuit"
+	libp2p_net "github.com/libp2p/go-libp2p-net"
+	libp2p_peer "github.com/libp2p/go-libp2p-peer"
+	libp2p_peerstore "github.com/libp2p/go-libp2p-peerstore"
+	ma "github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr-net"
+)
+
+const (
+	// relayCheckInterval is the interval at which the host reconnects to the
+	// relays it lost.
+	relayCheckInterval = time.Minute
+	// relayDialTimeout is the time given to connect to a relay.
+	relayDialTimeout = 10 * time.Second
+)
+
+// natOptions returns the libp2p options of a NAT configuration.
+func natOptions(config p2p.NATConfig) []libp2p.Option {
+	var opts []libp2p.Option
+	if config.PortMap {
+		opts = append(opts, libp2p.NATPortMap())
+	}
+	if config.Service {
+		opts = append(opts, libp2p.EnableRelay(circuit.OptHop))
+	} else if len(config.Relays) > 0 {
+		opts = append(opts, libp2p.EnableRelay())
+	}
+	return opts
+}
+
+// startNAT starts the AutoNAT client and service if configured, and keeps
+// the host connected to its relays.
+func (host *HostV2) startNAT(ctx context.Context, config p2p.NATConfig) {
+	if config.AutoNAT {
+		host.autonat = autonat.NewAutoNAT(ctx, host.h, host.h.Addrs)
+	}
+	if config.Service {
+		if _, err := autonatsvc.NewAutoNATService(ctx, host.h); err != nil {
+			host.logger.Warn("cannot start AutoNAT service", "error", err)
+		}
+	}
+	for _, addr := range config.Relays {
+		info, err := libp2p_peerstore.InfoFromP2pAddr(addr)
+		if err != nil {
+			host.logger.Warn("invalid relay address", "addr", addr, "error", err)
+			continue
+		}
+		host.relays = append(host.relays, *info)
+	}
+	if len(host.relays) > 0 {
+		go host.keepRelays()
+	}
+}
+
+// keepRelays connects to the relays, and reconnects to those it lost, until
+// the host closes.
+func (host *HostV2) keepRelays() {
+	tick := time.NewTicker(relayCheckInterval)
+	defer tick.Stop()
+	for {
+		for _, relay := range host.relays {
+			if host.h.Network().Connectedness(relay.ID) == libp2p_net.Connected {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), relayDialTimeout)
+			if err := host.h.Connect(ctx, relay); err != nil {
+				host.logger.Warn("cannot connect to relay", "error", err, "relay", relay.ID.Pretty())
+			}
+			cancel()
+		}
+		select {
+		case <-tick.C:
+		case <-host.quit:
+			return
+		}
+	}
+}
+
+// GetAdvertisedAddrs returns the addresses the host advertises to peers: its
+// public listen, port-mapped and observed addresses, or circuit addresses
+// through its relays when AutoNAT finds it unreachable or none is known.
+func (host *HostV2) GetAdvertisedAddrs() []ma.Multiaddr {
+	var public ma.Multiaddr
+	private := false
+	if host.autonat != nil {
+		switch host.autonat.Status() {
+		case autonat.NATStatusPublic:
+			public, _ = host.autonat.PublicAddr()
+		case autonat.NATStatusPrivate:
+			private = true
+		}
+	}
+	return advertisedAddrs(host.h.Addrs(), public, private, host.relays)
+}
+
+// advertisedAddrs selects the addresses a host advertises among addrs, the
+// addresses libp2p knows it by, and public, the address AutoNAT found it
+// reachable at if any. Private hosts only advertise circuit addresses.
+func advertisedAddrs(addrs []ma.Multiaddr, public ma.Multiaddr, private bool, relays []libp2p_peerstore.PeerInfo) []ma.Multiaddr {
+	var advertised []ma.Multiaddr
+	if !private {
+		if public != nil {
+			advertised = append(advertised, public)
+		}
+		for _, addr := range addrs {
+			if manet.IsPublicAddr(addr) && !containsAddr(advertised, addr) {
+				advertised = append(advertised, addr)
+			}
+		}
+	}
+	if len(advertised) > 0 {
+		return advertised
+	}
+	for _, relay := range relays {
+		for _, addr := range relay.Addrs {
+			if circuitAddr, err := relayedAddr(addr, relay.ID); err == nil {
+				advertised = append(advertised, circuitAddr)
+			}
+		}
+	}
+	return advertised
+}
+
+// relayedAddr returns the address at which a host is reached through the
+// relay of the given ID listening at addr.
+func relayedAddr(addr ma.Multiaddr, relay libp2p_peer.ID) (ma.Multiaddr, error) {
+	suffix, err := ma.NewMultiaddr("/ipfs/" + relay.Pretty() + "/p2p-circuit")
+	if err != nil {
+		return nil, err
+	}
+	return addr.Encapsulate(suffix), nil
+}
+
+func containsAddr(addrs []ma.Multiaddr, addr ma.Multiaddr) bool {
+	for _, a := range addrs {
+		if a.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
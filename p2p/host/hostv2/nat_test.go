@@ -0,0 +1,56 @@
+package hostv2
+
+import (
+	"reflect"
+	"testing"
+
+	libp2p_peer "github.com/libp2p/go-libp2p-peer"
+	libp2p_peerstore "github.com/libp2p/go-libp2p-peerstore"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+func TestAdvertisedAddrs(t *testing.T) {
+	addrs := func(strs ...string) []ma.Multiaddr {
+		var addrs []ma.Multiaddr
+		for _, s := range strs {
+			addr, err := ma.NewMultiaddr(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			addrs = append(addrs, addr)
+		}
+		return addrs
+	}
+	relayID, err := libp2p_peer.IDB58Decode("QmNnooDu7bfjPFoTZYxMNLWUQJyrVwtbZg5gBMjTezGAJN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	relays := []libp2p_peerstore.PeerInfo{{ID: relayID, Addrs: addrs("/ip4/5.6.7.8/tcp/9876")}}
+	relayed := "/ip4/5.6.7.8/tcp/9876/ipfs/QmNnooDu7bfjPFoTZYxMNLWUQJyrVwtbZg5gBMjTezGAJN/p2p-circuit"
+	local := addrs("/ip4/127.0.0.1/tcp/9000", "/ip4/192.168.1.2/tcp/9000")
+	observed := addrs("/ip4/127.0.0.1/tcp/9000", "/ip4/192.168.1.2/tcp/9000", "/ip4/1.2.3.4/tcp/9000")
+	tests := []struct {
+		name     string
+		addrs    []ma.Multiaddr
+		public   ma.Multiaddr
+		private  bool
+		relays   []libp2p_peerstore.PeerInfo
+		expected []ma.Multiaddr
+	}{
+		{"no public address", local, nil, false, nil, nil},
+		{"observed public address", observed, nil, false, relays, addrs("/ip4/1.2.3.4/tcp/9000")},
+		{"AutoNAT public address", observed, addrs("/ip4/1.2.3.4/tcp/9001")[0], false, nil,
+			addrs("/ip4/1.2.3.4/tcp/9001", "/ip4/1.2.3.4/tcp/9000")},
+		{"AutoNAT public address observed", observed, addrs("/ip4/1.2.3.4/tcp/9000")[0], false, nil,
+			addrs("/ip4/1.2.3.4/tcp/9000")},
+		{"relay fallback", local, nil, false, relays, addrs(relayed)},
+		{"private", observed, nil, true, relays, addrs(relayed)},
+		{"private without relays", observed, nil, true, nil, nil},
+	}
+	for _, test := range tests {
+		advertised := advertisedAddrs(test.addrs, test.public, test.private, test.relays)
+		if !reflect.DeepEqual(advertised, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, advertised)
+		}
+	}
+}
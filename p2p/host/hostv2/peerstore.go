@@ -0,0 +1,260 @@
+package hostv2
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	libp2p_net "github.com/libp2p/go-libp2p-net"
+	libp2p_peer "github.com/libp2p/go-libp2p-peer"
+	libp2p_peerstore "github.com/libp2p/go-libp2p-peerstore"
+	ma "github.com/multiformats/go-multiaddr"
+
+	"github.com/harmony-one/harmony/p2p"
+)
+
+// Persistence of the known peers of a host across restarts.
+const (
+	maxSavedPeers     = 256              // most recently seen peers saved
+	maxRedialPeers    = 16               // most recently seen peers redialed on startup
+	redialTimeout     = 10 * time.Second // time given to the redials on startup
+	peersSaveInterval = time.Minute
+)
+
+// savedPeer is a known peer, as saved in the peers file.
+type savedPeer struct {
+	ID          string   `json:"id"`
+	Addrs       []string `json:"addrs"`
+	LastSeen    int64    `json:"lastSeen"` // unix time of the last connection with the peer
+	Score       float64  `json:"score"`
+	BannedUntil int64    `json:"bannedUntil,omitempty"` // unix time, if banned
+}
+
+// loadPeers reads a peers file. A missing file holds no peers.
+func loadPeers(path string) ([]savedPeer, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var peers []savedPeer
+	if err := json.Unmarshal(data, &peers); err != nil {
+		return nil, err
+	}
+	return peers, nil
+}
+
+// savePeers atomically replaces a peers file.
+func savePeers(path string, peers []savedPeer) error {
+	data, err := json.MarshalIndent(peers, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// recentPeers returns up to n peers which are not banned at now, most
+// recently seen first, best scored first among those seen at the same time.
+func recentPeers(peers []savedPeer, n int, now time.Time) []savedPeer {
+	recent := make([]savedPeer, 0, len(peers))
+	for _, p := range peers {
+		if p.BannedUntil == 0 || now.Unix() >= p.BannedUntil {
+			recent = append(recent, p)
+		}
+	}
+	sort.SliceStable(recent, func(i, j int) bool {
+		if recent[i].LastSeen != recent[j].LastSeen {
+			return recent[i].LastSeen > recent[j].LastSeen
+		}
+		return recent[i].Score > recent[j].Score
+	})
+	if len(recent) > n {
+		recent = recent[:n]
+	}
+	return recent
+}
+
+// peerSeen tracks when the host was last connected with each peer.
+type peerSeen struct {
+	mtx      sync.Mutex
+	lastSeen map[libp2p_peer.ID]time.Time
+}
+
+func (s *peerSeen) seen(id libp2p_peer.ID, at time.Time) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.lastSeen == nil {
+		s.lastSeen = make(map[libp2p_peer.ID]time.Time)
+	}
+	if at.After(s.lastSeen[id]) {
+		s.lastSeen[id] = at
+	}
+}
+
+func (s *peerSeen) get(id libp2p_peer.ID) time.Time {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.lastSeen[id]
+}
+
+// restorePeers adds the peers of the peers file to the peerstore and the
+// peer scorer, and redials the most recently seen ones in the background, so
+// that the host has peers even if the bootnodes are unavailable.
+func (host *HostV2) restorePeers(path string) {
+	peers, err := loadPeers(path)
+	if err != nil {
+		host.logger.Warn("cannot load known peers", "error", err, "file", path)
+		return
+	}
+	byID := make(map[libp2p_peer.ID]savedPeer, len(peers))
+	for _, p := range peers {
+		id, err := libp2p_peer.IDB58Decode(p.ID)
+		if err != nil || id == host.h.ID() {
+			continue
+		}
+		addrs := make([]ma.Multiaddr, 0, len(p.Addrs))
+		for _, addr := range p.Addrs {
+			if a, err := ma.NewMultiaddr(addr); err == nil {
+				addrs = append(addrs, a)
+			}
+		}
+		if len(addrs) == 0 {
+			continue
+		}
+		host.Peerstore().AddAddrs(id, addrs, libp2p_peerstore.AddressTTL)
+		score := p2p.PeerScore{ID: id, Score: p.Score}
+		if p.BannedUntil != 0 {
+			score.BannedUntil = time.Unix(p.BannedUntil, 0)
+		}
+		host.scorer.Restore(score)
+		if p.LastSeen != 0 {
+			host.lastSeen.seen(id, time.Unix(p.LastSeen, 0))
+		}
+		byID[id] = p
+	}
+
+	var redial []libp2p_peer.ID
+	for _, p := range recentPeers(peers, maxRedialPeers, time.Now()) {
+		id, err := libp2p_peer.IDB58Decode(p.ID)
+		if _, ok := byID[id]; err == nil && ok {
+			redial = append(redial, id)
+		}
+	}
+	host.logger.Info("restored known peers", "file", path, "known", len(byID), "redial", len(redial))
+	go host.redialPeers(redial)
+}
+
+// redialPeers dials the given peers at once, giving up after the redial
+// timeout or when the host closes.
+func (host *HostV2) redialPeers(ids []libp2p_peer.ID) {
+	ctx, cancel := context.WithTimeout(context.Background(), redialTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-host.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id libp2p_peer.ID) {
+			defer wg.Done()
+			if err := host.h.Connect(ctx, host.Peerstore().PeerInfo(id)); err != nil {
+				host.logger.Debug("cannot redial known peer", "error", err, "peer", id.Pretty())
+			}
+		}(id)
+	}
+	wg.Wait()
+	host.logger.Info("redialed known peers", "redialed", len(ids), "connected", len(host.h.Network().Peers()))
+}
+
+// persistPeers saves the peers of the peerstore with their addresses.
+func (host *HostV2) persistPeers(path string) error {
+	now := time.Now()
+	for _, id := range host.h.Network().Peers() {
+		host.lastSeen.seen(id, now)
+	}
+	scores := make(map[libp2p_peer.ID]p2p.PeerScore)
+	for _, score := range host.scorer.Scores() {
+		scores[score.ID] = score
+	}
+	var peers []savedPeer
+	for _, id := range host.Peerstore().PeersWithAddrs() {
+		if id == host.h.ID() {
+			continue
+		}
+		addrs := host.Peerstore().Addrs(id)
+		if len(addrs) == 0 {
+			continue
+		}
+		p := savedPeer{ID: id.Pretty()}
+		if seen := host.lastSeen.get(id); !seen.IsZero() {
+			p.LastSeen = seen.Unix()
+		}
+		for _, addr := range addrs {
+			p.Addrs = append(p.Addrs, addr.String())
+		}
+		score := scores[id]
+		p.Score = score.Score
+		if !score.BannedUntil.IsZero() {
+			p.BannedUntil = score.BannedUntil.Unix()
+		}
+		peers = append(peers, p)
+	}
+	sort.SliceStable(peers, func(i, j int) bool { return peers[i].LastSeen > peers[j].LastSeen })
+	if len(peers) > maxSavedPeers {
+		peers = peers[:maxSavedPeers]
+	}
+	return savePeers(path, peers)
+}
+
+// persistPeersLoop saves the known peers periodically, and once more when the
+// host closes.
+func (host *HostV2) persistPeersLoop(path string) {
+	ticker := time.NewTicker(peersSaveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-host.quit:
+			if err := host.persistPeers(path); err != nil {
+				host.logger.Warn("cannot save known peers", "error", err, "file", path)
+			}
+			close(host.persisted)
+			return
+		}
+		if err := host.persistPeers(path); err != nil {
+			host.logger.Warn("cannot save known peers", "error", err, "file", path)
+		}
+	}
+}
+
+// trackPeer records when the host connects or disconnects from a peer.
+func (host *HostV2) trackPeer(net libp2p_net.Network, conn libp2p_net.Conn) {
+	host.lastSeen.seen(conn.RemotePeer(), time.Now())
+}
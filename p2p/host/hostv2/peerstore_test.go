@@ -0,0 +1,62 @@
+package hostv2
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSaveLoadPeers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "peerstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "db", "peers.json")
+
+	if peers, err := loadPeers(path); err != nil || peers != nil {
+		t.Fatalf("missing file loaded as %v, %v", peers, err)
+	}
+	peers := []savedPeer{
+		{ID: "a", Addrs: []string{"/ip4/127.0.0.1/tcp/9000"}, LastSeen: 100, Score: 3},
+		{ID: "b", Addrs: []string{"/ip4/127.0.0.1/tcp/9001"}, Score: -60, BannedUntil: 200},
+	}
+	if err := savePeers(path, peers); err != nil {
+		t.Fatalf("savePeers: %v", err)
+	}
+	loaded, err := loadPeers(path)
+	if err != nil {
+		t.Fatalf("loadPeers: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, peers) {
+		t.Errorf("loaded %+v, saved %+v", loaded, peers)
+	}
+	if files, _ := ioutil.ReadDir(filepath.Dir(path)); len(files) != 1 {
+		t.Errorf("temporary files left: %d files", len(files))
+	}
+}
+
+func TestRecentPeers(t *testing.T) {
+	now := time.Unix(1000, 0)
+	peers := []savedPeer{
+		{ID: "old", LastSeen: 100},
+		{ID: "banned", LastSeen: 900, BannedUntil: 2000},
+		{ID: "unbanned", LastSeen: 500, BannedUntil: 1000},
+		{ID: "recent", LastSeen: 800, Score: 1},
+		{ID: "recent-better", LastSeen: 800, Score: 5},
+		{ID: "never"},
+	}
+	var ids []string
+	for _, p := range recentPeers(peers, 3, now) {
+		ids = append(ids, p.ID)
+	}
+	if expected := []string{"recent-better", "recent", "unbanned"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected %v, got %v", expected, ids)
+	}
+	if n := len(recentPeers(peers, 10, now)); n != 5 {
+		t.Errorf("expected 5 peers, got %d", n)
+	}
+}
@@ -0,0 +1,83 @@
+package hostv2
+
+import (
+	"context"
+
+	"github.com/harmony-one/harmony/p2p"
+
+	libp2p_host "github.com/libp2p/go-libp2p-host"
+	libp2p_net "github.com/libp2p/go-libp2p-net"
+	libp2p_peer "github.com/libp2p/go-libp2p-peer"
+	protocol "github.com/libp2p/go-libp2p-protocol"
+)
+
+// scopedHost is a libp2p host whose stream protocols are scoped to a network.
+// The pubsub routers of the libp2p version in use cannot be given their
+// protocol IDs, so they are given a scopedHost instead, and only negotiate
+// streams with peers of the same network.
+type scopedHost struct {
+	libp2p_host.Host
+	scope p2p.NetworkScope
+}
+
+// scopeHost returns h with its stream protocols scoped to the given network,
+// or h itself for the zero scope.
+func scopeHost(h libp2p_host.Host, scope p2p.NetworkScope) libp2p_host.Host {
+	if scope.IsZero() {
+		return h
+	}
+	return &scopedHost{Host: h, scope: scope}
+}
+
+func (h *scopedHost) wrapHandler(handler libp2p_net.StreamHandler) libp2p_net.StreamHandler {
+	return func(s libp2p_net.Stream) {
+		handler(&scopedStream{Stream: s, scope: h.scope})
+	}
+}
+
+// SetStreamHandler sets the handler of the scoped protocol pid.
+func (h *scopedHost) SetStreamHandler(pid protocol.ID, handler libp2p_net.StreamHandler) {
+	h.Host.SetStreamHandler(h.scope.ProtocolID(pid), h.wrapHandler(handler))
+}
+
+// SetStreamHandlerMatch sets the handler of the scoped protocol pid, matching
+// the unscoped protocol IDs with m.
+func (h *scopedHost) SetStreamHandlerMatch(pid protocol.ID, m func(string) bool, handler libp2p_net.StreamHandler) {
+	match := func(id string) bool {
+		scoped := protocol.ID(id)
+		unscoped := h.scope.UnscopeProtocolID(scoped)
+		return unscoped != scoped && m(string(unscoped))
+	}
+	h.Host.SetStreamHandlerMatch(h.scope.ProtocolID(pid), match, h.wrapHandler(handler))
+}
+
+// RemoveStreamHandler removes the handler of the scoped protocol pid.
+func (h *scopedHost) RemoveStreamHandler(pid protocol.ID) {
+	h.Host.RemoveStreamHandler(h.scope.ProtocolID(pid))
+}
+
+// NewStream opens a stream to peer p with the first of the scoped protocols
+// pids that it speaks.
+func (h *scopedHost) NewStream(ctx context.Context, p libp2p_peer.ID, pids ...protocol.ID) (libp2p_net.Stream, error) {
+	scoped := make([]protocol.ID, len(pids))
+	for i, pid := range pids {
+		scoped[i] = h.scope.ProtocolID(pid)
+	}
+	s, err := h.Host.NewStream(ctx, p, scoped...)
+	if err != nil {
+		return nil, err
+	}
+	return &scopedStream{Stream: s, scope: h.scope}, nil
+}
+
+// scopedStream is a stream of a scopedHost, which reports its protocol
+// unscoped so that the pubsub routers recognize it.
+type scopedStream struct {
+	libp2p_net.Stream
+	scope p2p.NetworkScope
+}
+
+// Protocol returns the unscoped protocol of the stream.
+func (s *scopedStream) Protocol() protocol.ID {
+	return s.scope.UnscopeProtocolID(s.Stream.Protocol())
+}
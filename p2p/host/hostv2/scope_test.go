@@ -0,0 +1,61 @@
+package hostv2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	libp2p_net "github.com/libp2p/go-libp2p-net"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+
+	"github.com/harmony-one/harmony/p2p"
+)
+
+func TestScopedHost(t *testing.T) {
+	mn := mocknet.New(context.Background())
+	server, err := mn.GenPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameNetwork, err := mn.GenPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherNetwork, err := mn.GenPeer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mn.LinkAll(); err != nil {
+		t.Fatal(err)
+	}
+	testnet := p2p.NetworkScope{NetworkID: 2, GenesisHash: common.HexToHash("0x01")}
+	devnet := p2p.NetworkScope{NetworkID: 3, GenesisHash: common.HexToHash("0x02")}
+
+	const pid = "/meshsub/1.0.0"
+	handled := make(chan libp2p_net.Stream, 1)
+	scopeHost(server, testnet).SetStreamHandler(pid, func(s libp2p_net.Stream) {
+		handled <- s
+	})
+
+	s, err := scopeHost(sameNetwork, testnet).NewStream(context.Background(), server.ID(), pid)
+	if err != nil {
+		t.Fatalf("cannot open stream within the network: %v", err)
+	}
+	if s.Protocol() != pid {
+		t.Errorf("Protocol() = %s, expected = %s", s.Protocol(), pid)
+	}
+	if _, err := s.Write([]byte{0}); err != nil {
+		t.Fatal(err)
+	}
+	if in := <-handled; in.Protocol() != pid {
+		t.Errorf("handled Protocol() = %s, expected = %s", in.Protocol(), pid)
+	}
+	s.Close()
+
+	if _, err := scopeHost(otherNetwork, devnet).NewStream(context.Background(), server.ID(), pid); err == nil {
+		t.Error("stream opened with a host of another network")
+	}
+	if _, err := otherNetwork.NewStream(context.Background(), server.ID(), pid); err == nil {
+		t.Error("stream opened with an unscoped host")
+	}
+}
@@ -0,0 +1,93 @@
+package hostv2
+
+import (
+	"sync"
+	"time"
+
+	libp2p_pubsub "github.com/libp2p/go-libp2p-pubsub"
+
+	"github.com/harmony-one/harmony/p2p"
+)
+
+// messageIDCache remembers the IDs of recent group messages, for as long as
+// configured rather than the fixed time pubsub remembers them, so that a copy
+// relayed late by a slow peer is neither delivered nor propagated again.
+type messageIDCache struct {
+	ttl       time.Duration
+	mtx       sync.Mutex
+	seen      map[string]time.Time // message ID -> first seen
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func newMessageIDCache(ttl time.Duration) *messageIDCache {
+	return &messageIDCache{
+		ttl:       ttl,
+		seen:      make(map[string]time.Time),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// messageID returns the ID of a group message, which is the ID pubsub gives
+// it: its author and sequence number. Publishing the same content again, such
+// as the periodic discovery ping, makes a new message.
+func messageID(m *libp2p_pubsub.Message) string {
+	return string(m.GetFrom()) + string(m.GetSeqno())
+}
+
+// add records the ID of a message, and reports whether it was not seen
+// within the TTL.
+func (c *messageIDCache) add(id string) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	now := c.now()
+	if now.Sub(c.lastSweep) >= c.ttl {
+		for seenID, seen := range c.seen {
+			if now.Sub(seen) >= c.ttl {
+				delete(c.seen, seenID)
+			}
+		}
+		c.lastSweep = now
+	}
+	if seen, ok := c.seen[id]; ok && now.Sub(seen) < c.ttl {
+		return false
+	}
+	c.seen[id] = now
+	return true
+}
+
+// trafficCounters counts the messages of the groups.
+// The zero value is ready to use.
+type trafficCounters struct {
+	mtx    sync.Mutex
+	groups map[p2p.GroupID]*p2p.GroupTrafficStats
+}
+
+// update applies f to the counters of the groups.
+func (c *trafficCounters) update(groups []string, f func(stats *p2p.GroupTrafficStats)) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.groups == nil {
+		c.groups = make(map[p2p.GroupID]*p2p.GroupTrafficStats)
+	}
+	for _, group := range groups {
+		stats, ok := c.groups[p2p.GroupID(group)]
+		if !ok {
+			stats = &p2p.GroupTrafficStats{}
+			c.groups[p2p.GroupID(group)] = stats
+		}
+		f(stats)
+	}
+}
+
+// snapshot returns a copy of the counters.
+func (c *trafficCounters) snapshot() map[p2p.GroupID]p2p.GroupTrafficStats {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	groups := make(map[p2p.GroupID]p2p.GroupTrafficStats, len(c.groups))
+	for group, stats := range c.groups {
+		groups[group] = *stats
+	}
+	return groups
+}
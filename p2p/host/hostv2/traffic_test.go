@@ -0,0 +1,70 @@
+package hostv2
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/harmony-one/harmony/p2p"
+	mock "github.com/harmony-one/harmony/p2p/host/hostv2/mock"
+)
+
+func TestMessageIDCache(t *testing.T) {
+	now := time.Unix(1000000, 0)
+	cache := newMessageIDCache(time.Minute)
+	cache.lastSweep = now
+	cache.now = func() time.Time { return now }
+	a, b := messageID(pubsubMessage("ABC", []byte{1})), messageID(pubsubMessage("ABC", []byte{1}))
+	if !cache.add(a) || !cache.add(b) {
+		t.Fatal("new message IDs reported as seen")
+	}
+	now = now.Add(30 * time.Second)
+	if cache.add(a) {
+		t.Error("seen message ID reported as new")
+	}
+	now = now.Add(30 * time.Second)
+	if !cache.add(a) {
+		t.Error("expired message ID reported as seen")
+	}
+	if _, ok := cache.seen[b]; ok {
+		t.Error("expired message ID not swept")
+	}
+}
+
+func TestHostV2_GetTrafficStats(t *testing.T) {
+	mc := gomock.NewController(t)
+	defer mc.Finish()
+	data := []byte{1, 2, 3}
+	pubsub := mock.NewMockpubsub(mc)
+	gomock.InOrder(
+		pubsub.EXPECT().Publish("ABC", data),
+		pubsub.EXPECT().Publish("DEF", data).Return(errors.New("FIAL")),
+		pubsub.EXPECT().Publish("ABC", data),
+	)
+	host := &HostV2{pubsub: pubsub}
+	host.SendMessageToGroups([]p2p.GroupID{"ABC", "DEF"}, data)
+	host.SendMessageToGroups([]p2p.GroupID{"ABC"}, data)
+	stats := host.GetTrafficStats()
+	if len(stats.Groups) != 1 {
+		t.Fatalf("expected stats of 1 group, got %+v", stats.Groups)
+	}
+	if abc := stats.Groups["ABC"]; abc.Published != 2 || abc.PublishedBytes != 6 {
+		t.Errorf("unexpected stats %+v", abc)
+	}
+}
+
+func TestHostV2_SendMessageToGroupsNetwork(t *testing.T) {
+	mc := gomock.NewController(t)
+	defer mc.Finish()
+	data := []byte{1, 2, 3}
+	scope := p2p.NetworkScope{NetworkID: 2}
+	pubsub := mock.NewMockpubsub(mc)
+	pubsub.EXPECT().Publish(string(scope.GroupID("ABC")), data)
+	host := &HostV2{pubsub: pubsub, scope: scope}
+	host.SendMessageToGroups([]p2p.GroupID{"ABC"}, data)
+	if abc := host.GetTrafficStats().Groups["ABC"]; abc.Published != 1 {
+		t.Errorf("unexpected stats %+v", abc)
+	}
+}
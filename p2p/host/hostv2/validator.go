@@ -0,0 +1,133 @@
+package hostv2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/harmony-one/harmony/api/proto"
+	proto_node "github.com/harmony-one/harmony/api/proto/node"
+	"github.com/harmony-one/harmony/p2p"
+	"github.com/harmony-one/harmony/p2p/host"
+
+	libp2p_peer "github.com/libp2p/go-libp2p-peer"
+	libp2p_pubsub "github.com/libp2p/go-libp2p-pubsub"
+)
+
+// maxContentSizes are the size limits of the content of group messages, per
+// message category. Messages of other categories are rejected.
+var maxContentSizes = map[proto.MessageCategory]int{
+	proto.Consensus: 2 << 20, // announce messages carry the proposed block
+	proto.Node:      2 << 20, // blocks and transaction lists
+	proto.DRand:     64 << 10,
+	proto.Staking:   64 << 10,
+}
+
+// maxContentSize is the largest of maxContentSizes, which bounds compressed
+// messages before their category is known.
+const maxContentSize = 2 << 20
+
+// nodeMessageTypes are the message types of the Node category relayed on groups.
+var nodeMessageTypes = map[proto_node.MessageType]bool{
+	proto_node.Transaction: true,
+	proto_node.Block:       true,
+	proto_node.PING:        true,
+	proto_node.PONG:        true,
+	proto_node.ShardState:  true,
+}
+
+// validateMessage cheaply checks the framing, category, type and size of a
+// group message, without decoding its payload. Compressed messages are
+// checked once decompressed.
+func validateMessage(msg []byte) error {
+	content, err := host.GetP2pMessageContent(msg)
+	if err != nil {
+		return err
+	}
+	if content, err = proto.DecompressMessage(content, maxContentSize); err != nil {
+		return err
+	}
+	category, err := proto.GetMessageCategory(content)
+	if err != nil {
+		return err
+	}
+	maxSize, ok := maxContentSizes[category]
+	if !ok {
+		return fmt.Errorf("unknown message category %d", category)
+	}
+	if len(content) > maxSize {
+		return fmt.Errorf("message of category %d too large: %d > %d bytes", category, len(content), maxSize)
+	}
+	switch category {
+	case proto.Consensus, proto.DRand, proto.Staking:
+		// The payload is a protobuf message right after the category.
+		if len(content) <= proto.MessageCategoryBytes {
+			return errors.New("empty message payload")
+		}
+	case proto.Node:
+		msgType, err := proto.GetMessageType(content)
+		if err != nil {
+			return err
+		}
+		if !nodeMessageTypes[proto_node.MessageType(msgType)] {
+			return fmt.Errorf("unknown node message type %d", msgType)
+		}
+		if payload, _ := proto.GetMessagePayload(content); len(payload) == 0 {
+			return errors.New("empty message payload")
+		}
+	}
+	return nil
+}
+
+// validateGroupMessage is the pubsub validator of the groups the host joins.
+// Pubsub checks the signature of the message author before calling it, and
+// drops the message instead of relaying it when it returns false, so invalid
+// and duplicate messages stop at the first hop. Copies of a message are those
+// with the same author and sequence number; the same content published again
+// is a new message. Authors of invalid messages
+// are reported to the peer scorer.
+func (host *HostV2) validateGroupMessage(ctx context.Context, m *libp2p_pubsub.Message) bool {
+	author := libp2p_peer.ID(m.GetFrom())
+	if author == host.self.PeerID {
+		// Our own message, counted when published; resending it is deliberate.
+		return true
+	}
+	if host.scorer.IsBanned(author) {
+		return false
+	}
+	groups, ok := host.unscopeTopics(m.GetTopicIDs())
+	if !ok {
+		host.logger.Debug("dropping group message of another network", "author", author.Pretty(), "topics", m.GetTopicIDs())
+		return false
+	}
+	data := m.GetData()
+	if err := validateMessage(data); err != nil {
+		host.logger.Debug("dropping invalid group message", "error", err, "author", author.Pretty(), "groups", groups)
+		host.traffic.update(groups, func(stats *p2p.GroupTrafficStats) { stats.Invalid++ })
+		host.scorer.Report(author, p2p.PeerInvalidMessage)
+		return false
+	}
+	if !host.seen.add(messageID(m)) {
+		host.traffic.update(groups, func(stats *p2p.GroupTrafficStats) { stats.Duplicates++ })
+		return false
+	}
+	host.traffic.update(groups, func(stats *p2p.GroupTrafficStats) {
+		stats.Received++
+		stats.ReceivedBytes += uint64(len(data))
+	})
+	return true
+}
+
+// unscopeTopics returns the groups of the pubsub topics of a message, and
+// false if a topic belongs to another network.
+func (host *HostV2) unscopeTopics(topics []string) ([]string, bool) {
+	groups := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		group, ok := host.scope.Unscope(p2p.GroupID(topic))
+		if !ok {
+			return nil, false
+		}
+		groups = append(groups, string(group))
+	}
+	return groups, true
+}
@@ -0,0 +1,109 @@
+package hostv2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/harmony-one/harmony/api/proto"
+	proto_node "github.com/harmony-one/harmony/api/proto/node"
+	"github.com/harmony-one/harmony/internal/utils"
+	"github.com/harmony-one/harmony/p2p"
+	"github.com/harmony-one/harmony/p2p/host"
+)
+
+func TestValidateMessage(t *testing.T) {
+	nodeMessage := func(msgType proto_node.MessageType, payload ...byte) []byte {
+		return append([]byte{byte(proto.Node), byte(msgType)}, payload...)
+	}
+	tests := []struct {
+		name  string
+		msg   []byte
+		valid bool
+	}{
+		{"transaction", host.ConstructP2pMessage(0, nodeMessage(proto_node.Transaction, 0, 1)), true},
+		{"consensus", host.ConstructP2pMessage(0, proto.ConstructConsensusMessage([]byte{1, 2})), true},
+		{"drand", host.ConstructP2pMessage(0, proto.ConstructDRandMessage([]byte{1})), true},
+		{"no header", []byte{0x11, 0, 0}, false},
+		{"bad message type", append([]byte{0x12, 0, 0, 0, 3}, nodeMessage(proto_node.Block, 0)...), false},
+		{"bad content size", append([]byte{0x11, 0, 0, 0, 4}, nodeMessage(proto_node.Block, 0)...), false},
+		{"empty content", host.ConstructP2pMessage(0, nil), false},
+		{"client category", host.ConstructP2pMessage(0, []byte{byte(proto.Client), 0, 0}), false},
+		{"unknown category", host.ConstructP2pMessage(0, []byte{0xff, 0, 0}), false},
+		{"unknown node type", host.ConstructP2pMessage(0, nodeMessage(3, 0)), false},
+		{"empty node payload", host.ConstructP2pMessage(0, nodeMessage(proto_node.Block)), false},
+		{"empty consensus payload", host.ConstructP2pMessage(0, proto.ConstructConsensusMessage(nil)), false},
+		{"too large", host.ConstructP2pMessage(0, proto.ConstructStakingMessage(make([]byte, 64<<10))), false},
+		{"compressed block", host.ConstructP2pMessage(0, proto.CompressMessage(nodeMessage(proto_node.Block, make([]byte, 1000)...))), true},
+		{"compressed too large", host.ConstructP2pMessage(0, proto.CompressMessage(proto.ConstructStakingMessage(make([]byte, 64<<10)))), false},
+		{"corrupt compressed", host.ConstructP2pMessage(0, []byte{byte(proto.Node) | proto.Compressed, 0xff}), false},
+	}
+	for _, test := range tests {
+		if err := validateMessage(test.msg); (err == nil) != test.valid {
+			t.Errorf("%s: expected valid %v, got error %v", test.name, test.valid, err)
+		}
+	}
+}
+
+func TestHostV2_validateGroupMessage(t *testing.T) {
+	h := &HostV2{
+		self:   p2p.Peer{PeerID: "self"},
+		scorer: p2p.NewPeerScorer(p2p.DefaultPeerScoreConfig),
+		logger: utils.GetLogInstance(),
+		seen:   newMessageIDCache(time.Minute),
+	}
+	data := host.ConstructP2pMessage(0, proto.ConstructConsensusMessage([]byte{1}))
+	valid := pubsubMessage("ABC", data)
+	invalid := pubsubMessage("ABC", []byte{1, 2, 3})
+	ctx := context.Background()
+	if !h.validateGroupMessage(ctx, valid) {
+		t.Error("valid message rejected")
+	}
+	if h.validateGroupMessage(ctx, valid) {
+		t.Error("relayed copy of a message accepted")
+	}
+	if !h.validateGroupMessage(ctx, pubsubMessage("ABC", data)) {
+		t.Error("republished message rejected")
+	}
+	if !h.validateGroupMessage(ctx, pubsubMessage("DEF", data)) {
+		t.Error("same content from another author rejected")
+	}
+	if !h.validateGroupMessage(ctx, pubsubMessage("self", data)) {
+		t.Error("own message rejected")
+	}
+	for i := 0; i < 10; i++ {
+		if h.validateGroupMessage(ctx, invalid) {
+			t.Fatal("invalid message accepted")
+		}
+	}
+	if !h.scorer.IsBanned("ABC") {
+		t.Error("author of invalid messages not banned")
+	}
+	if h.validateGroupMessage(ctx, valid) {
+		t.Error("message of banned author accepted")
+	}
+}
+
+func TestHostV2_validateGroupMessageNetwork(t *testing.T) {
+	testnet := p2p.NetworkScope{NetworkID: 2}
+	devnet := p2p.NetworkScope{NetworkID: 3}
+	h := &HostV2{
+		scorer: p2p.NewPeerScorer(p2p.DefaultPeerScoreConfig),
+		scope:  testnet,
+		logger: utils.GetLogInstance(),
+		seen:   newMessageIDCache(time.Minute),
+	}
+	data := host.ConstructP2pMessage(0, proto.ConstructConsensusMessage([]byte{1}))
+	m := pubsubMessage("ABC", data)
+	m.TopicIDs = []string{string(devnet.GroupID(p2p.GroupIDBeacon))}
+	if h.validateGroupMessage(context.Background(), m) {
+		t.Error("message of another network accepted")
+	}
+	m.TopicIDs = []string{string(testnet.GroupID(p2p.GroupIDBeacon))}
+	if !h.validateGroupMessage(context.Background(), m) {
+		t.Error("message of the network rejected")
+	}
+	if stats := h.GetTrafficStats().Groups[p2p.GroupIDBeacon]; stats.Received != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
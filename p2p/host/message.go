@@ -2,13 +2,36 @@ package host
 
 import (
 	"encoding/binary"
+	"errors"
+)
+
+const (
+	// P2pMessageType is the message type of the messages built by ConstructP2pMessage.
+	P2pMessageType = 0x11
+	// P2pMessageHeaderSize is the number of bytes of the message type and content size.
+	P2pMessageHeaderSize = 5
 )
 
 // ConstructP2pMessage constructs the p2p message as [messageType, contentSize, content]
 func ConstructP2pMessage(msgType byte, content []byte) []byte {
-	message := make([]byte, 5+len(content))
-	message[0] = 17 // messageType 0x11
-	binary.BigEndian.PutUint32(message[1:5], uint32(len(content)))
-	copy(message[5:], content)
+	message := make([]byte, P2pMessageHeaderSize+len(content))
+	message[0] = P2pMessageType
+	binary.BigEndian.PutUint32(message[1:P2pMessageHeaderSize], uint32(len(content)))
+	copy(message[P2pMessageHeaderSize:], content)
 	return message
 }
+
+// GetP2pMessageContent checks the header of a p2p message built by
+// ConstructP2pMessage and returns its content.
+func GetP2pMessageContent(message []byte) ([]byte, error) {
+	if len(message) < P2pMessageHeaderSize {
+		return nil, errors.New("p2p message too short")
+	}
+	if message[0] != P2pMessageType {
+		return nil, errors.New("unknown p2p message type")
+	}
+	if binary.BigEndian.Uint32(message[1:P2pMessageHeaderSize]) != uint32(len(message)-P2pMessageHeaderSize) {
+		return nil, errors.New("p2p message content size mismatch")
+	}
+	return message[P2pMessageHeaderSize:], nil
+}
@@ -9,6 +9,7 @@ import (
 	p2p "github.com/harmony-one/harmony/p2p"
 	go_libp2p_host "github.com/libp2p/go-libp2p-host"
 	go_libp2p_peer "github.com/libp2p/go-libp2p-peer"
+	go_multiaddr "github.com/multiformats/go-multiaddr"
 	reflect "reflect"
 )
 
@@ -119,6 +120,62 @@ func (mr *MockHostMockRecorder) GetPeerCount() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPeerCount", reflect.TypeOf((*MockHost)(nil).GetPeerCount))
 }
 
+// GetPeerScorer mocks base method
+func (m *MockHost) GetPeerScorer() *p2p.PeerScorer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPeerScorer")
+	ret0, _ := ret[0].(*p2p.PeerScorer)
+	return ret0
+}
+
+// GetPeerScorer indicates an expected call of GetPeerScorer
+func (mr *MockHostMockRecorder) GetPeerScorer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPeerScorer", reflect.TypeOf((*MockHost)(nil).GetPeerScorer))
+}
+
+// GetTrafficStats mocks base method
+func (m *MockHost) GetTrafficStats() p2p.TrafficStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrafficStats")
+	ret0, _ := ret[0].(p2p.TrafficStats)
+	return ret0
+}
+
+// GetTrafficStats indicates an expected call of GetTrafficStats
+func (mr *MockHostMockRecorder) GetTrafficStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrafficStats", reflect.TypeOf((*MockHost)(nil).GetTrafficStats))
+}
+
+// GetAdvertisedAddrs mocks base method
+func (m *MockHost) GetAdvertisedAddrs() []go_multiaddr.Multiaddr {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdvertisedAddrs")
+	ret0, _ := ret[0].([]go_multiaddr.Multiaddr)
+	return ret0
+}
+
+// GetAdvertisedAddrs indicates an expected call of GetAdvertisedAddrs
+func (mr *MockHostMockRecorder) GetAdvertisedAddrs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdvertisedAddrs", reflect.TypeOf((*MockHost)(nil).GetAdvertisedAddrs))
+}
+
+// GetNetworkScope mocks base method
+func (m *MockHost) GetNetworkScope() p2p.NetworkScope {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNetworkScope")
+	ret0, _ := ret[0].(p2p.NetworkScope)
+	return ret0
+}
+
+// GetNetworkScope indicates an expected call of GetNetworkScope
+func (mr *MockHostMockRecorder) GetNetworkScope() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNetworkScope", reflect.TypeOf((*MockHost)(nil).GetNetworkScope))
+}
+
 // ConnectHostPeer mocks base method
 func (m *MockHost) ConnectHostPeer(arg0 p2p.Peer) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,291 @@
+package memhost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	libp2p_host "github.com/libp2p/go-libp2p-host"
+	libp2p_peer "github.com/libp2p/go-libp2p-peer"
+	ma "github.com/multiformats/go-multiaddr"
+
+	"github.com/harmony-one/harmony/p2p"
+)
+
+// ReceiveQueueSize is the number of messages a group receiver buffers. Like
+// with libp2p pubsub, messages to a receiver whose buffer is full are dropped.
+const ReceiveQueueSize = 1024
+
+// Errors of in-memory hosts.
+var (
+	ErrHostClosed     = errors.New("memhost: host closed")
+	ErrReceiverClosed = errors.New("memhost: GroupReceiver has been closed")
+)
+
+// Host is an in-memory p2p.Host on a Network.
+type Host struct {
+	network *Network
+	self    p2p.Peer
+	scorer  *p2p.PeerScorer
+
+	mtx       sync.Mutex
+	closed    bool
+	peers     map[libp2p_peer.ID]p2p.Peer // known peers, like the libp2p peerstore
+	connected map[libp2p_peer.ID]bool
+	receivers map[p2p.GroupID][]*GroupReceiver
+	traffic   map[p2p.GroupID]*p2p.GroupTrafficStats
+	bytesIn   int64
+	bytesOut  int64
+}
+
+var _ p2p.Host = (*Host)(nil)
+
+func newHost(network *Network, self p2p.Peer) *Host {
+	return &Host{
+		network:   network,
+		self:      self,
+		scorer:    p2p.NewPeerScorer(p2p.DefaultPeerScoreConfig),
+		peers:     make(map[libp2p_peer.ID]p2p.Peer),
+		connected: make(map[libp2p_peer.ID]bool),
+		receivers: make(map[p2p.GroupID][]*GroupReceiver),
+		traffic:   make(map[p2p.GroupID]*p2p.GroupTrafficStats),
+	}
+}
+
+// GetSelfPeer returns the peer of the host.
+func (host *Host) GetSelfPeer() p2p.Peer {
+	return host.self
+}
+
+// GetID returns the peer ID of the host.
+func (host *Host) GetID() libp2p_peer.ID {
+	return host.self.PeerID
+}
+
+// GetP2PHost returns nil, as there is no libp2p host behind an in-memory host.
+func (host *Host) GetP2PHost() libp2p_host.Host {
+	return nil
+}
+
+// Close removes the host from the network and closes its group receivers.
+func (host *Host) Close() error {
+	host.mtx.Lock()
+	if host.closed {
+		host.mtx.Unlock()
+		return nil
+	}
+	host.closed = true
+	receivers := host.receivers
+	host.receivers = make(map[p2p.GroupID][]*GroupReceiver)
+	host.mtx.Unlock()
+	host.network.removeHost(host.self.PeerID)
+	for _, groupReceivers := range receivers {
+		for _, r := range groupReceivers {
+			r.Close()
+		}
+	}
+	return nil
+}
+
+// AddPeer adds a peer to the known peers of the host.
+func (host *Host) AddPeer(p *p2p.Peer) error {
+	if p.PeerID == "" {
+		return fmt.Errorf("AddPeer error: peerID is empty")
+	}
+	if !host.scorer.InterceptPeerDial(p.PeerID) {
+		return fmt.Errorf("AddPeer error: peer %s is banned", p.PeerID.Pretty())
+	}
+	host.mtx.Lock()
+	defer host.mtx.Unlock()
+	host.peers[p.PeerID] = *p
+	return nil
+}
+
+// GetPeerCount returns the number of known peers.
+func (host *Host) GetPeerCount() int {
+	host.mtx.Lock()
+	defer host.mtx.Unlock()
+	return len(host.peers)
+}
+
+// ConnectHostPeer connects to a peer on the same network, if it is reachable
+// and not banned, and adds it to the known peers of both hosts.
+func (host *Host) ConnectHostPeer(peer p2p.Peer) {
+	if !host.scorer.InterceptPeerDial(peer.PeerID) || !host.network.reachable(host.self.PeerID, peer.PeerID) {
+		return
+	}
+	other := host.network.host(peer.PeerID)
+	if other == nil || !other.scorer.InterceptSecured(host.self.PeerID) {
+		return
+	}
+	host.connect(other.self)
+	other.connect(host.self)
+}
+
+// connect records a connection with a peer.
+func (host *Host) connect(peer p2p.Peer) {
+	host.mtx.Lock()
+	defer host.mtx.Unlock()
+	host.peers[peer.PeerID] = peer
+	host.connected[peer.PeerID] = true
+}
+
+// IsConnected reports whether the host connected to a peer or was connected
+// by it with ConnectHostPeer.
+func (host *Host) IsConnected(id libp2p_peer.ID) bool {
+	host.mtx.Lock()
+	defer host.mtx.Unlock()
+	return host.connected[id]
+}
+
+// GetPeerScorer returns the peer scorer of the host. Messages of banned
+// peers are dropped.
+func (host *Host) GetPeerScorer() *p2p.PeerScorer {
+	return host.scorer
+}
+
+// GetTrafficStats returns the traffic counters of the host. The byte
+// counters count message contents.
+func (host *Host) GetTrafficStats() p2p.TrafficStats {
+	host.mtx.Lock()
+	defer host.mtx.Unlock()
+	stats := p2p.TrafficStats{
+		TotalIn:   host.bytesIn,
+		TotalOut:  host.bytesOut,
+		PubSubIn:  host.bytesIn,
+		PubSubOut: host.bytesOut,
+		Groups:    make(map[p2p.GroupID]p2p.GroupTrafficStats, len(host.traffic)),
+	}
+	for group, groupStats := range host.traffic {
+		stats.Groups[group] = *groupStats
+	}
+	return stats
+}
+
+// GetAdvertisedAddrs returns the addresses of the peer of the host.
+func (host *Host) GetAdvertisedAddrs() []ma.Multiaddr {
+	return host.self.Addrs
+}
+
+// GetNetworkScope returns the zero scope; a memhost network is a network of
+// its own.
+func (host *Host) GetNetworkScope() p2p.NetworkScope {
+	return p2p.NetworkScope{}
+}
+
+// SendMessageToGroups sends a message to one or more multicast groups.
+func (host *Host) SendMessageToGroups(groups []p2p.GroupID, msg []byte) error {
+	host.mtx.Lock()
+	if host.closed {
+		host.mtx.Unlock()
+		return ErrHostClosed
+	}
+	for _, group := range groups {
+		stats := host.groupTraffic(group)
+		stats.Published++
+		stats.PublishedBytes += uint64(len(msg))
+	}
+	host.bytesOut += int64(len(msg))
+	host.mtx.Unlock()
+	host.network.send(host, groups, append([]byte{}, msg...))
+	return nil
+}
+
+// GroupReceiver returns a receiver of messages sent to a multicast group.
+// See the p2p.GroupReceiver interface for details.
+func (host *Host) GroupReceiver(group p2p.GroupID) (p2p.GroupReceiver, error) {
+	host.mtx.Lock()
+	defer host.mtx.Unlock()
+	if host.closed {
+		return nil, ErrHostClosed
+	}
+	r := &GroupReceiver{
+		messages: make(chan message, ReceiveQueueSize),
+		closed:   make(chan struct{}),
+	}
+	host.receivers[group] = append(host.receivers[group], r)
+	return r, nil
+}
+
+// deliver hands a message of a peer to the receivers of its groups.
+func (host *Host) deliver(sender libp2p_peer.ID, groups []p2p.GroupID, msg []byte) {
+	if sender != host.self.PeerID && host.scorer.IsBanned(sender) {
+		return
+	}
+	host.mtx.Lock()
+	defer host.mtx.Unlock()
+	received := false
+	for _, group := range groups {
+		receivers := host.receivers[group]
+		if len(receivers) == 0 {
+			continue
+		}
+		received = true
+		if sender != host.self.PeerID {
+			stats := host.groupTraffic(group)
+			stats.Received++
+			stats.ReceivedBytes += uint64(len(msg))
+		}
+		for _, r := range receivers {
+			r.push(message{data: msg, sender: sender})
+		}
+	}
+	if received && sender != host.self.PeerID {
+		host.bytesIn += int64(len(msg))
+	}
+}
+
+// groupTraffic returns the counters of a group. Caller shall hold mtx.
+func (host *Host) groupTraffic(group p2p.GroupID) *p2p.GroupTrafficStats {
+	stats, ok := host.traffic[group]
+	if !ok {
+		stats = &p2p.GroupTrafficStats{}
+		host.traffic[group] = stats
+	}
+	return stats
+}
+
+type message struct {
+	data   []byte
+	sender libp2p_peer.ID
+}
+
+// GroupReceiver receives the messages of a group on an in-memory host.
+type GroupReceiver struct {
+	messages  chan message
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// Close closes the receiver.
+func (r *GroupReceiver) Close() error {
+	r.closeOnce.Do(func() { close(r.closed) })
+	return nil
+}
+
+// Receive receives a message.
+func (r *GroupReceiver) Receive(ctx context.Context) (msg []byte, sender libp2p_peer.ID, err error) {
+	select {
+	case <-r.closed:
+		return nil, "", ErrReceiverClosed
+	default:
+	}
+	select {
+	case m := <-r.messages:
+		return m.data, m.sender, nil
+	case <-r.closed:
+		return nil, "", ErrReceiverClosed
+	case <-ctx.Done():
+		return nil, "", ctx.Err()
+	}
+}
+
+// push queues a message, or drops it if the queue is full.
+func (r *GroupReceiver) push(m message) {
+	select {
+	case <-r.closed:
+	case r.messages <- m:
+	default:
+	}
+}
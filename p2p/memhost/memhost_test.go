@@ -0,0 +1,174 @@
+package memhost
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	libp2p_peer "github.com/libp2p/go-libp2p-peer"
+
+	"github.com/harmony-one/harmony/p2p"
+)
+
+func newTestHosts(t *testing.T, network *Network, n int, groups ...p2p.GroupID) ([]*Host, []p2p.GroupReceiver) {
+	hosts := make([]*Host, n)
+	receivers := make([]p2p.GroupReceiver, 0, n*len(groups))
+	for i := range hosts {
+		hosts[i] = network.NewHost(&p2p.Peer{IP: "127.0.0.1", Port: strconv.Itoa(9000 + i)})
+		for _, group := range groups {
+			r, err := hosts[i].GroupReceiver(group)
+			if err != nil {
+				t.Fatalf("GroupReceiver: %v", err)
+			}
+			receivers = append(receivers, r)
+		}
+	}
+	return hosts, receivers
+}
+
+// receive returns the next message of r, or nil if none arrives in time.
+func receive(t *testing.T, r p2p.GroupReceiver, timeout time.Duration) ([]byte, libp2p_peer.ID) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	msg, sender, err := r.Receive(ctx)
+	if err != nil && err != context.DeadlineExceeded {
+		t.Fatalf("Receive: %v", err)
+	}
+	return msg, sender
+}
+
+func TestSendMessageToGroups(t *testing.T) {
+	network := NewNetwork()
+	hosts, receivers := newTestHosts(t, network, 3, p2p.GroupIDBeacon)
+	other, err := network.NewHost(&p2p.Peer{IP: "127.0.0.1", Port: "8999"}).GroupReceiver(p2p.GroupIDGlobal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := hosts[0].SendMessageToGroups([]p2p.GroupID{p2p.GroupIDBeacon}, []byte{1, 2, 3}); err != nil {
+		t.Fatalf("SendMessageToGroups: %v", err)
+	}
+	for i, r := range receivers {
+		msg, sender := receive(t, r, time.Second)
+		if string(msg) != string([]byte{1, 2, 3}) || sender != hosts[0].GetID() {
+			t.Errorf("host %d received %v from %v", i, msg, sender)
+		}
+	}
+	if msg, _ := receive(t, other, 10*time.Millisecond); msg != nil {
+		t.Errorf("message delivered to another group: %v", msg)
+	}
+
+	stats := hosts[0].GetTrafficStats().Groups[p2p.GroupIDBeacon]
+	if stats.Published != 1 || stats.PublishedBytes != 3 {
+		t.Errorf("unexpected sender stats %+v", stats)
+	}
+	stats = hosts[1].GetTrafficStats().Groups[p2p.GroupIDBeacon]
+	if stats.Received != 1 || stats.ReceivedBytes != 3 {
+		t.Errorf("unexpected receiver stats %+v", stats)
+	}
+}
+
+func TestLatencyAndLoss(t *testing.T) {
+	network := NewNetwork()
+	network.Seed(1)
+	hosts, receivers := newTestHosts(t, network, 2, p2p.GroupIDBeacon)
+
+	network.SetLatency(50*time.Millisecond, 50*time.Millisecond)
+	start := time.Now()
+	hosts[0].SendMessageToGroups([]p2p.GroupID{p2p.GroupIDBeacon}, []byte{1})
+	if msg, _ := receive(t, receivers[1], time.Second); msg == nil {
+		t.Fatal("delayed message not received")
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("message received after %v, before the latency", elapsed)
+	}
+	receive(t, receivers[0], time.Second)
+
+	network.SetLatency(0, 0)
+	network.SetLossRate(1)
+	hosts[0].SendMessageToGroups([]p2p.GroupID{p2p.GroupIDBeacon}, []byte{2})
+	if msg, _ := receive(t, receivers[1], 10*time.Millisecond); msg != nil {
+		t.Errorf("lost message received: %v", msg)
+	}
+	if msg, _ := receive(t, receivers[0], 10*time.Millisecond); msg == nil {
+		t.Error("own message lost")
+	}
+}
+
+func TestSeedReplay(t *testing.T) {
+	// run returns which hosts receive a message on a lossy seeded network.
+	run := func() []bool {
+		network := NewNetwork()
+		network.Seed(7)
+		network.SetLossRate(0.5)
+		hosts, receivers := newTestHosts(t, network, 8, p2p.GroupIDBeacon)
+		hosts[0].SendMessageToGroups([]p2p.GroupID{p2p.GroupIDBeacon}, []byte{1})
+		received := make([]bool, len(receivers))
+		for i, r := range receivers {
+			msg, _ := receive(t, r, 10*time.Millisecond)
+			received[i] = msg != nil
+		}
+		return received
+	}
+	first := run()
+	for i := 0; i < 5; i++ {
+		if again := run(); !reflect.DeepEqual(again, first) {
+			t.Fatalf("seeded run delivered to %v, then to %v", first, again)
+		}
+	}
+}
+
+func TestPartition(t *testing.T) {
+	network := NewNetwork()
+	hosts, receivers := newTestHosts(t, network, 3, p2p.GroupIDBeacon)
+	network.Partition([]libp2p_peer.ID{hosts[0].GetID(), hosts[1].GetID()})
+
+	hosts[0].SendMessageToGroups([]p2p.GroupID{p2p.GroupIDBeacon}, []byte{1})
+	if msg, _ := receive(t, receivers[1], time.Second); msg == nil {
+		t.Error("message not received in the same partition")
+	}
+	if msg, _ := receive(t, receivers[2], 10*time.Millisecond); msg != nil {
+		t.Errorf("message received across the partition: %v", msg)
+	}
+	hosts[2].ConnectHostPeer(hosts[0].GetSelfPeer())
+	if hosts[2].IsConnected(hosts[0].GetID()) {
+		t.Error("connected across the partition")
+	}
+
+	network.Heal()
+	hosts[2].ConnectHostPeer(hosts[0].GetSelfPeer())
+	if !hosts[2].IsConnected(hosts[0].GetID()) || !hosts[0].IsConnected(hosts[2].GetID()) {
+		t.Error("not connected after healing the partition")
+	}
+	if hosts[2].GetPeerCount() != 1 {
+		t.Errorf("expected 1 peer, got %d", hosts[2].GetPeerCount())
+	}
+}
+
+func TestBannedPeer(t *testing.T) {
+	network := NewNetwork()
+	hosts, receivers := newTestHosts(t, network, 2, p2p.GroupIDBeacon)
+	for !hosts[1].GetPeerScorer().Report(hosts[0].GetID(), p2p.PeerInvalidMessage) {
+	}
+	hosts[0].SendMessageToGroups([]p2p.GroupID{p2p.GroupIDBeacon}, []byte{1})
+	if msg, _ := receive(t, receivers[1], 10*time.Millisecond); msg != nil {
+		t.Errorf("message of banned peer received: %v", msg)
+	}
+	if err := hosts[1].AddPeer(&p2p.Peer{PeerID: hosts[0].GetID()}); err == nil {
+		t.Error("banned peer added")
+	}
+}
+
+func TestClose(t *testing.T) {
+	network := NewNetwork()
+	hosts, receivers := newTestHosts(t, network, 1, p2p.GroupIDBeacon)
+	hosts[0].Close()
+	if _, _, err := receivers[0].Receive(context.Background()); err != ErrReceiverClosed {
+		t.Errorf("expected ErrReceiverClosed, got %v", err)
+	}
+	if err := hosts[0].SendMessageToGroups([]p2p.GroupID{p2p.GroupIDBeacon}, []byte{1}); err != ErrHostClosed {
+		t.Errorf("expected ErrHostClosed, got %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+// Package memhost implements p2p.Host in memory, for tests and simulations.
+//
+// Hosts created from the same Network reach each other without sockets. The
+// network behaves like a fully connected pubsub mesh: a message sent to a group
+// reaches every host of the network that receives the group, unless the
+// network loses it or a partition separates the hosts. Messages are delivered
+// after the configured latency.
+package memhost
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	libp2p_peer "github.com/libp2p/go-libp2p-peer"
+
+	"github.com/harmony-one/harmony/p2p"
+)
+
+// Network is a virtual network shared by in-memory hosts.
+// It is safe for concurrent use.
+type Network struct {
+	mtx        sync.RWMutex
+	hosts      map[libp2p_peer.ID]*Host
+	ids        []libp2p_peer.ID // IDs of hosts, sorted, so that sends replay
+	minLatency time.Duration
+	maxLatency time.Duration
+	lossRate   float64
+	partitions map[libp2p_peer.ID]int // partition of each host; 0 if none
+	rand       *rand.Rand             // guarded by randMtx
+	randMtx    sync.Mutex
+}
+
+// NewNetwork creates a network without latency, loss or partitions.
+func NewNetwork() *Network {
+	return &Network{
+		hosts:      make(map[libp2p_peer.ID]*Host),
+		partitions: make(map[libp2p_peer.ID]int),
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// NewHost creates a host on the network. If self has no peer ID, the host
+// gets one derived from its IP and port, and self.PeerID is set to it.
+func (n *Network) NewHost(self *p2p.Peer) *Host {
+	if self.PeerID == "" {
+		self.PeerID = libp2p_peer.ID("memhost/" + self.IP + ":" + self.Port)
+	}
+	host := newHost(n, *self)
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	if _, ok := n.hosts[self.PeerID]; !ok {
+		i := sort.Search(len(n.ids), func(i int) bool { return n.ids[i] >= self.PeerID })
+		n.ids = append(n.ids, "")
+		copy(n.ids[i+1:], n.ids[i:])
+		n.ids[i] = self.PeerID
+	}
+	n.hosts[self.PeerID] = host
+	return host
+}
+
+// Seed seeds the randomness of latency and loss, to replay a simulation.
+func (n *Network) Seed(seed int64) {
+	n.randMtx.Lock()
+	defer n.randMtx.Unlock()
+	n.rand = rand.New(rand.NewSource(seed))
+}
+
+// SetLatency sets the latency of messages between hosts, drawn uniformly
+// between min and max.
+func (n *Network) SetLatency(min, max time.Duration) {
+	if max < min {
+		max = min
+	}
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.minLatency, n.maxLatency = min, max
+}
+
+// SetLossRate sets the probability, between 0 and 1, that a message to a host
+// is lost.
+func (n *Network) SetLossRate(rate float64) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.lossRate = rate
+}
+
+// Partition splits the network: hosts of different groups cannot reach each
+// other. Hosts not in any group form one more partition together.
+func (n *Network) Partition(groups ...[]libp2p_peer.ID) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.partitions = make(map[libp2p_peer.ID]int)
+	for i, group := range groups {
+		for _, id := range group {
+			n.partitions[id] = i + 1
+		}
+	}
+}
+
+// Heal removes all partitions.
+func (n *Network) Heal() {
+	n.Partition()
+}
+
+// host returns the host with the given ID, or nil.
+func (n *Network) host(id libp2p_peer.ID) *Host {
+	n.mtx.RLock()
+	defer n.mtx.RUnlock()
+	return n.hosts[id]
+}
+
+// removeHost removes a closed host from the network.
+func (n *Network) removeHost(id libp2p_peer.ID) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	if _, ok := n.hosts[id]; !ok {
+		return
+	}
+	delete(n.hosts, id)
+	i := sort.Search(len(n.ids), func(i int) bool { return n.ids[i] >= id })
+	n.ids = append(n.ids[:i], n.ids[i+1:]...)
+}
+
+// reachable reports whether a host can reach another one.
+func (n *Network) reachable(from, to libp2p_peer.ID) bool {
+	n.mtx.RLock()
+	defer n.mtx.RUnlock()
+	return n.hosts[to] != nil && n.partitions[from] == n.partitions[to]
+}
+
+// send delivers msg from a host to the other hosts receiving one of the
+// groups, subject to partitions, loss and latency. The sender receives its
+// own message at once, like with libp2p pubsub. Hosts are visited in the
+// order of their IDs, so that a seeded network draws the same loss and
+// latency for each of them on every run.
+func (n *Network) send(from *Host, groups []p2p.GroupID, msg []byte) {
+	n.mtx.RLock()
+	defer n.mtx.RUnlock()
+	for _, id := range n.ids {
+		to := n.hosts[id]
+		if id == from.self.PeerID {
+			to.deliver(from.self.PeerID, groups, msg)
+			continue
+		}
+		if n.partitions[id] != n.partitions[from.self.PeerID] || n.lost() {
+			continue
+		}
+		if delay := n.latency(); delay > 0 {
+			time.AfterFunc(delay, func() { to.deliver(from.self.PeerID, groups, msg) })
+		} else {
+			to.deliver(from.self.PeerID, groups, msg)
+		}
+	}
+}
+
+// lost draws whether a message is lost. Caller shall hold mtx.
+func (n *Network) lost() bool {
+	if n.lossRate <= 0 {
+		return false
+	}
+	n.randMtx.Lock()
+	defer n.randMtx.Unlock()
+	return n.rand.Float64() < n.lossRate
+}
+
+// latency draws the latency of a message. Caller shall hold mtx.
+func (n *Network) latency() time.Duration {
+	if n.maxLatency <= n.minLatency {
+		return n.minLatency
+	}
+	n.randMtx.Lock()
+	defer n.randMtx.Unlock()
+	return n.minLatency + time.Duration(n.rand.Int63n(int64(n.maxLatency-n.minLatency)))
+}
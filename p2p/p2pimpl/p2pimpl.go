@@ -12,9 +12,14 @@ import (
 
 // NewHost starts the host for p2p
 // for hostv2, it generates multiaddress, keypair and add PeerID to peer, add priKey to host
-// TODO (leo) The peerstore has to be persisted on disk.
 func NewHost(self *p2p.Peer, key libp2p_crypto.PrivKey) (p2p.Host, error) {
-	h := hostv2.New(self, key)
+	return NewHostWithConfig(self, key, p2p.DefaultHostConfig)
+}
+
+// NewHostWithConfig is like NewHost, with the given host configuration. The
+// known peers persist on disk if config.PeersFile is set.
+func NewHostWithConfig(self *p2p.Peer, key libp2p_crypto.PrivKey, config p2p.HostConfig) (p2p.Host, error) {
+	h := hostv2.NewWithConfig(self, key, config)
 
 	utils.GetLogInstance().Info("NewHost", "self", net.JoinHostPort(self.IP, self.Port), "PeerID", self.PeerID)
 
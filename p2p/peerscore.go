@@ -0,0 +1,254 @@
+package p2p
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	libp2p_peer "github.com/libp2p/go-libp2p-peer"
+)
+
+// PeerEvent is an observation about the behaviour of a peer.
+type PeerEvent byte
+
+// Peer events reported to the PeerScorer.
+const (
+	// PeerInvalidMessage is reported when a peer sends a malformed or invalid message.
+	PeerInvalidMessage PeerEvent = iota
+	// PeerTimeout is reported when a peer fails to answer a request in time.
+	PeerTimeout
+	// PeerUsefulBlock is reported when a peer provides a block we accept.
+	PeerUsefulBlock
+)
+
+func (e PeerEvent) String() string {
+	switch e {
+	case PeerInvalidMessage:
+		return "invalid message"
+	case PeerTimeout:
+		return "timeout"
+	case PeerUsefulBlock:
+		return "useful block"
+	}
+	return "unknown"
+}
+
+// PeerScoreConfig configures a PeerScorer.
+type PeerScoreConfig struct {
+	// Weights is the score change of each event. Events without a weight are ignored.
+	Weights map[PeerEvent]float64
+	// MaxScore caps the score, so that good behaviour in the past cannot
+	// shield a peer that starts misbehaving.
+	MaxScore float64
+	// BanThreshold is the score below which a peer is disconnected and banned.
+	BanThreshold float64
+	// BanDuration is how long a peer stays banned.
+	BanDuration time.Duration
+	// DecayHalfLife is the time after which a score has decayed halfway to zero.
+	// Zero disables decay.
+	DecayHalfLife time.Duration
+}
+
+// DefaultPeerScoreConfig is the peer scoring used by the hosts.
+var DefaultPeerScoreConfig = PeerScoreConfig{
+	Weights: map[PeerEvent]float64{
+		PeerInvalidMessage: -10,
+		PeerTimeout:        -2,
+		PeerUsefulBlock:    1,
+	},
+	MaxScore:      20,
+	BanThreshold:  -50,
+	BanDuration:   time.Hour,
+	DecayHalfLife: 10 * time.Minute,
+}
+
+// PeerScore is a snapshot of the score of a peer.
+type PeerScore struct {
+	ID          libp2p_peer.ID
+	Score       float64
+	BannedUntil time.Time // zero if the peer is not banned
+}
+
+// ConnectionGater decides whether connections with a peer are allowed.
+// It mirrors the connection gater of later libp2p releases, which the libp2p
+// version in use lacks; hosts consult it before dialing a peer and once a new
+// connection is up.
+type ConnectionGater interface {
+	// InterceptPeerDial reports whether we may dial the peer.
+	InterceptPeerDial(id libp2p_peer.ID) (allow bool)
+	// InterceptSecured reports whether an established connection with the
+	// peer may be kept.
+	InterceptSecured(id libp2p_peer.ID) (allow bool)
+}
+
+const (
+	// peerPruneInterval is how often the scorer forgets peers whose score
+	// has decayed away.
+	peerPruneInterval = time.Minute
+	// peerPruneScore is the score below which an unbanned peer is forgotten.
+	peerPruneScore = 0.01
+)
+
+type peerScore struct {
+	score       float64
+	updated     time.Time
+	bannedUntil time.Time
+}
+
+// PeerScorer keeps a score per peer from the events reported about it, and
+// bans the peers whose score falls below the ban threshold. It is safe for
+// concurrent use, and implements ConnectionGater.
+type PeerScorer struct {
+	config PeerScoreConfig
+	mtx    sync.Mutex
+	peers  map[libp2p_peer.ID]*peerScore
+	onBan  func(libp2p_peer.ID)
+	now    func() time.Time
+	pruned time.Time // last time decayed peers were forgotten
+}
+
+// NewPeerScorer creates a PeerScorer with the given configuration.
+func NewPeerScorer(config PeerScoreConfig) *PeerScorer {
+	return &PeerScorer{
+		config: config,
+		peers:  make(map[libp2p_peer.ID]*peerScore),
+		now:    time.Now,
+	}
+}
+
+// SetBanHandler sets the function called, outside of the scorer lock, when a
+// peer gets banned. Hosts use it to disconnect the peer.
+func (s *PeerScorer) SetBanHandler(onBan func(libp2p_peer.ID)) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.onBan = onBan
+}
+
+// Report records an event about a peer, and bans the peer if its score falls
+// below the ban threshold. It reports whether the peer got banned by the event.
+func (s *PeerScorer) Report(id libp2p_peer.ID, event PeerEvent) bool {
+	weight, ok := s.config.Weights[event]
+	if id == "" || !ok {
+		return false
+	}
+	s.mtx.Lock()
+	now := s.now()
+	if now.Sub(s.pruned) >= peerPruneInterval {
+		s.prune(now)
+	}
+	p := s.peer(id, now)
+	if now.Before(p.bannedUntil) {
+		s.mtx.Unlock()
+		return false
+	}
+	p.score = math.Min(p.score+weight, s.config.MaxScore)
+	banned := p.score < s.config.BanThreshold
+	if banned {
+		p.bannedUntil = now.Add(s.config.BanDuration)
+	}
+	onBan := s.onBan
+	s.mtx.Unlock()
+	if banned && onBan != nil {
+		onBan(id)
+	}
+	return banned
+}
+
+// IsBanned reports whether a peer is currently banned.
+func (s *PeerScorer) IsBanned(id libp2p_peer.ID) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	p, ok := s.peers[id]
+	return ok && s.now().Before(p.bannedUntil)
+}
+
+// Unban lifts the ban of a peer and resets its score.
+func (s *PeerScorer) Unban(id libp2p_peer.ID) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.peers, id)
+}
+
+// Restore sets the score and ban of a peer, as saved from Scores, such as
+// after a restart.
+func (s *PeerScorer) Restore(score PeerScore) {
+	if score.ID == "" {
+		return
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.peers[score.ID] = &peerScore{
+		score:       math.Min(score.Score, s.config.MaxScore),
+		updated:     s.now(),
+		bannedUntil: score.BannedUntil,
+	}
+}
+
+// Scores returns the current scores of all known peers, lowest first.
+func (s *PeerScorer) Scores() []PeerScore {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	now := s.now()
+	scores := make([]PeerScore, 0, len(s.peers))
+	for id := range s.peers {
+		p := s.peer(id, now)
+		score := PeerScore{ID: id, Score: p.score}
+		if now.Before(p.bannedUntil) {
+			score.BannedUntil = p.bannedUntil
+		}
+		scores = append(scores, score)
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score < scores[j].Score
+		}
+		return scores[i].ID < scores[j].ID
+	})
+	return scores
+}
+
+// InterceptPeerDial refuses to dial banned peers.
+func (s *PeerScorer) InterceptPeerDial(id libp2p_peer.ID) bool {
+	return !s.IsBanned(id)
+}
+
+// InterceptSecured refuses connections with banned peers.
+func (s *PeerScorer) InterceptSecured(id libp2p_peer.ID) bool {
+	return !s.IsBanned(id)
+}
+
+// peer returns the score entry of a peer, decayed to now. A peer whose ban
+// has expired starts over with a zero score.
+// Caller shall hold mtx.
+func (s *PeerScorer) peer(id libp2p_peer.ID, now time.Time) *peerScore {
+	p, ok := s.peers[id]
+	if !ok {
+		p = &peerScore{updated: now}
+		s.peers[id] = p
+		return p
+	}
+	if !p.bannedUntil.IsZero() && !now.Before(p.bannedUntil) {
+		*p = peerScore{updated: now}
+		return p
+	}
+	if s.config.DecayHalfLife > 0 && now.After(p.updated) {
+		halfLives := float64(now.Sub(p.updated)) / float64(s.config.DecayHalfLife)
+		p.score *= math.Pow(0.5, halfLives)
+	}
+	p.updated = now
+	return p
+}
+
+// prune forgets the peers that are not banned and whose score has decayed to
+// about zero, so that the scorer does not grow with every peer ever seen.
+// Caller shall hold mtx.
+func (s *PeerScorer) prune(now time.Time) {
+	for id := range s.peers {
+		p := s.peer(id, now)
+		if !now.Before(p.bannedUntil) && math.Abs(p.score) < peerPruneScore {
+			delete(s.peers, id)
+		}
+	}
+	s.pruned = now
+}
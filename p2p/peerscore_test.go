@@ -0,0 +1,113 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	libp2p_peer "github.com/libp2p/go-libp2p-peer"
+)
+
+func newTestPeerScorer() (*PeerScorer, *time.Time) {
+	now := time.Unix(1000000, 0)
+	scorer := NewPeerScorer(DefaultPeerScoreConfig)
+	scorer.now = func() time.Time { return now }
+	return scorer, &now
+}
+
+func TestPeerScorerBan(t *testing.T) {
+	scorer, now := newTestPeerScorer()
+	id := libp2p_peer.ID("bad")
+	var banned []libp2p_peer.ID
+	scorer.SetBanHandler(func(id libp2p_peer.ID) { banned = append(banned, id) })
+
+	for i := 0; i < 5; i++ {
+		if scorer.Report(id, PeerInvalidMessage) {
+			t.Fatalf("peer banned after %d invalid messages", i+1)
+		}
+	}
+	if !scorer.Report(id, PeerInvalidMessage) {
+		t.Fatal("peer not banned below the threshold")
+	}
+	if len(banned) != 1 || banned[0] != id {
+		t.Errorf("ban handler called with %v", banned)
+	}
+	if scorer.InterceptPeerDial(id) || scorer.InterceptSecured(id) {
+		t.Error("banned peer allowed to connect")
+	}
+	if scorer.Report(id, PeerInvalidMessage) || len(banned) != 1 {
+		t.Error("banned peer banned again")
+	}
+
+	*now = now.Add(DefaultPeerScoreConfig.BanDuration)
+	if scorer.IsBanned(id) {
+		t.Error("ban did not expire")
+	}
+	if scores := scorer.Scores(); len(scores) != 1 || scores[0].Score != 0 || !scores[0].BannedUntil.IsZero() {
+		t.Errorf("unexpected scores after the ban %+v", scores)
+	}
+}
+
+func TestPeerScorerScores(t *testing.T) {
+	scorer, now := newTestPeerScorer()
+	good, slow := libp2p_peer.ID("good"), libp2p_peer.ID("slow")
+	for i := 0; i < 100; i++ {
+		scorer.Report(good, PeerUsefulBlock)
+	}
+	scorer.Report(slow, PeerTimeout)
+	scorer.Report("", PeerTimeout)
+
+	scores := scorer.Scores()
+	if len(scores) != 2 {
+		t.Fatalf("expected 2 scores, got %+v", scores)
+	}
+	if scores[0].ID != slow || scores[0].Score != -2 {
+		t.Errorf("unexpected score of slow peer %+v", scores[0])
+	}
+	if scores[1].ID != good || scores[1].Score != DefaultPeerScoreConfig.MaxScore {
+		t.Errorf("unexpected score of good peer %+v", scores[1])
+	}
+
+	*now = now.Add(DefaultPeerScoreConfig.DecayHalfLife)
+	if scores := scorer.Scores(); scores[1].Score != DefaultPeerScoreConfig.MaxScore/2 {
+		t.Errorf("score did not decay by half, got %v", scores[1].Score)
+	}
+
+	scorer.Unban(slow)
+	if scores := scorer.Scores(); len(scores) != 1 {
+		t.Errorf("peer not forgotten, got %+v", scores)
+	}
+}
+
+func TestPeerScorerRestore(t *testing.T) {
+	scorer, now := newTestPeerScorer()
+	scorer.Restore(PeerScore{ID: "good", Score: 100})
+	scorer.Restore(PeerScore{ID: "bad", Score: -60, BannedUntil: now.Add(time.Minute)})
+	if !scorer.IsBanned("bad") || scorer.IsBanned("good") {
+		t.Error("bans not restored")
+	}
+	scores := scorer.Scores()
+	if len(scores) != 2 || scores[0].Score != -60 || scores[1].Score != DefaultPeerScoreConfig.MaxScore {
+		t.Errorf("unexpected restored scores %+v", scores)
+	}
+}
+
+func TestPeerScorerPrune(t *testing.T) {
+	scorer, now := newTestPeerScorer()
+	scorer.config.BanDuration = 24 * time.Hour
+	old, bad := libp2p_peer.ID("old"), libp2p_peer.ID("bad")
+	scorer.Report(old, PeerTimeout)
+	for !scorer.Report(bad, PeerInvalidMessage) {
+	}
+
+	*now = now.Add(10 * DefaultPeerScoreConfig.DecayHalfLife)
+	scorer.Report("new", PeerUsefulBlock)
+	if _, ok := scorer.peers[old]; ok {
+		t.Error("decayed peer not pruned")
+	}
+	if !scorer.IsBanned(bad) {
+		t.Error("banned peer pruned")
+	}
+	if scores := scorer.Scores(); len(scores) != 2 {
+		t.Errorf("unexpected scores after pruning %+v", scores)
+	}
+}
@@ -0,0 +1,24 @@
+package p2p
+
+// TrafficStats are the traffic counters of a Host, to compare the bandwidth
+// used by group messaging under different pubsub settings.
+type TrafficStats struct {
+	// TotalIn and TotalOut are the bytes received and sent over all protocols,
+	// and RateIn and RateOut their current rates in bytes per second.
+	TotalIn, TotalOut int64
+	RateIn, RateOut   float64
+	// PubSubIn and PubSubOut are the bytes received and sent by the pubsub router.
+	PubSubIn, PubSubOut int64
+	// Groups are the message counters of the groups.
+	Groups map[GroupID]GroupTrafficStats
+}
+
+// GroupTrafficStats are the message counters of a group.
+type GroupTrafficStats struct {
+	Published      uint64 // messages we published
+	PublishedBytes uint64
+	Received       uint64 // new valid messages of other peers
+	ReceivedBytes  uint64
+	Duplicates     uint64 // copies of already received messages, dropped
+	Invalid        uint64 // invalid messages, dropped
+}